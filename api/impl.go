@@ -2,32 +2,49 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
-
-	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
 )
 
 // ensure that we've conformed to the `ServerInterface` with a compile-time check
 var _ ServerInterface = (*Server)(nil)
 
-type Server struct{}
+type Server struct {
+	Validator Validator
+}
 
 func NewServer() Server {
 	return Server{}
 }
 
+func NewValidationServer(v Validator) Server {
+	return Server{
+		Validator: v,
+	}
+}
+
 // (POST /upload)
-func (Server) PostUpload(w http.ResponseWriter, r *http.Request) {
-
-	// TODO: transform the vanilla CSV into workbench CSV
-	resp := IslandoraObject{
-		Title: &islandoraModel.GenericField{
-			islandoraModel.Generic{
-				Value: "foo",
-			},
-		},
+// validates the CSV in the request body and responds with a JSON report
+func (s Server) PostUpload(w http.ResponseWriter, r *http.Request) {
+	if s.Validator == nil {
+		http.Error(w, "validation is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	report, err := s.Validator.Validate(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		slog.Error("Unable to validate CSV", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(report)
 }
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"io"
+)
+
+// Validator checks an uploaded CSV
+type Validator interface {
+	Validate(r io.Reader) (*ValidationReport, error)
+}
+
+// ValidationReport lists the problems found in a CSV
+type ValidationReport struct {
+	Valid  bool              `json:"valid"`
+	Rows   int               `json:"rows"`
+	Errors []ValidationError `json:"errors"`
+	// Warnings do not make the CSV invalid
+	// e.g. a value that will create a new term
+	Warnings []ValidationError `json:"warnings,omitempty"`
+}
+
+type ValidationError struct {
+	// Row is the row number in the spreadsheet, the header is row 1
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+func (r *ValidationReport) Add(row int, column, value, message string) {
+	r.Valid = false
+	r.Errors = append(r.Errors, ValidationError{
+		Row:     row,
+		Column:  column,
+		Value:   value,
+		Message: message,
+	})
+}
+
+func (r *ValidationReport) Warn(row int, column, value, message string) {
+	r.Warnings = append(r.Warnings, ValidationError{
+		Row:     row,
+		Column:  column,
+		Value:   value,
+		Message: message,
+	})
+}
+
+// ByRow groups the errors by row number
+func (r *ValidationReport) ByRow() map[int][]ValidationError {
+	rows := map[int][]ValidationError{}
+	for _, e := range r.Errors {
+		rows[e.Row] = append(rows[e.Row], e)
+	}
+
+	return rows
+}
+
+// ByColumn groups the errors by column
+func (r *ValidationReport) ByColumn() map[string][]ValidationError {
+	columns := map[string][]ValidationError{}
+	for _, e := range r.Errors {
+		columns[e.Column] = append(columns[e.Column], e)
+	}
+
+	return columns
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	htmlTemplate "html/template"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/lehigh-university-libraries/go-islandora/templates"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+type DataDictionary struct {
+	Bundles []DictionaryBundle
+}
+
+type DictionaryBundle struct {
+	Name       string
+	EntityType string
+	Bundle     string
+	Fields     []DictionaryField
+}
+
+type DictionaryField struct {
+	MachineName   string
+	Label         string
+	HelpText      string
+	Type          string
+	Required      bool
+	Cardinality   string
+	Vocabularies  []string
+	SheetsColumns []string
+}
+
+// dataDictionaryCmd represents the data-dictionary command
+var dataDictionaryCmd = &cobra.Command{
+	Use:   "data-dictionary",
+	Short: "Generates a data dictionary from a Drupal config sync directory",
+	Long: `Generates Markdown or HTML documentation listing every field per bundle,
+along with the Google Sheets column(s) that populate the field.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		nodeCexYaml, _ := cmd.Flags().GetString("node-cex-yaml")
+		configDir, _ := cmd.Flags().GetString("config-dir")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+
+		structData, err := loadStructData(nodeCexYaml, configDir)
+		if err != nil {
+			slog.Error("Error reading config", "err", err)
+			os.Exit(1)
+		}
+
+		columns, err := sheetsFields(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		doc, err := generateDataDictionary(buildDataDictionary(structData, columns), format)
+		if err != nil {
+			slog.Error("Error generating data dictionary", "err", err)
+			os.Exit(1)
+		}
+
+		err = os.WriteFile(output, []byte(doc), 0644)
+		if err != nil {
+			slog.Error("Error writing output file", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("Data dictionary generated and written", "file", output)
+	},
+}
+
+func init() {
+	generateCmd.AddCommand(dataDictionaryCmd)
+
+	dataDictionaryCmd.Flags().String("node-cex-yaml", "", "Path to the node config export YAML file")
+	dataDictionaryCmd.Flags().String("config-dir", "", "Path to a config sync directory")
+	dataDictionaryCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+	dataDictionaryCmd.Flags().String("format", "markdown", "Output format (markdown or html)")
+	dataDictionaryCmd.Flags().String("output", "./data-dictionary.md", "Output file for the data dictionary")
+}
+
+func buildDataDictionary(data StructData, columns []CsvColumn) DataDictionary {
+	// the spreadsheet columns that populate each field
+	// e.g. field_note => Archival Box (attr0=box), Archival Folder (attr0=folder)
+	fieldColumns := map[string][]string{}
+	for _, column := range columns {
+		tag := workbench.ParseTag(column.Tag)
+		if tag.Property != "" {
+			attr := strings.TrimPrefix(tag.String(), tag.Field+".")
+			fieldColumns[tag.Field] = append(fieldColumns[tag.Field], fmt.Sprintf("%s (%s)", column.ColumnName, attr))
+			continue
+		}
+		fieldColumns[tag.Field] = append(fieldColumns[tag.Field], column.ColumnName)
+	}
+
+	primary := data
+	primary.StructName = "IslandoraObject"
+	dictionary := DataDictionary{}
+	for _, schema := range append([]StructData{primary}, data.Schemas...) {
+		bundle := DictionaryBundle{
+			Name:       schema.StructName,
+			EntityType: schema.EntityType,
+			Bundle:     schema.Bundle,
+		}
+		for _, field := range schema.DrupalFields {
+			f := DictionaryField{
+				MachineName:  field.MachineName,
+				Label:        field.Title,
+				HelpText:     strings.ReplaceAll(field.Description, `\"`, `"`),
+				Type:         field.Type,
+				Required:     field.Required,
+				Cardinality:  cardinalityLabel(field.Cardinality),
+				Vocabularies: field.TargetBundles,
+			}
+			// the spreadsheet only creates nodes
+			if schema.EntityType == "node" {
+				f.SheetsColumns = fieldColumns[field.MachineName]
+			}
+			bundle.Fields = append(bundle.Fields, f)
+		}
+		dictionary.Bundles = append(dictionary.Bundles, bundle)
+	}
+
+	return dictionary
+}
+
+func cardinalityLabel(cardinality int) string {
+	switch cardinality {
+	case 0:
+		return ""
+	case -1:
+		return "Unlimited"
+	case 1:
+		return "Single"
+	}
+
+	return strconv.Itoa(cardinality)
+}
+
+func generateDataDictionary(dictionary DataDictionary, format string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "markdown", "md":
+		tmpl, err := template.New("data-dictionary.md.tmpl").Funcs(template.FuncMap{
+			"cell": markdownCell,
+			"join": strings.Join,
+		}).ParseFS(templates.FS, "data-dictionary.md.tmpl")
+		if err != nil {
+			return "", err
+		}
+		err = tmpl.Execute(&buf, dictionary)
+		if err != nil {
+			return "", err
+		}
+	case "html":
+		tmpl, err := htmlTemplate.New("data-dictionary.html.tmpl").Funcs(htmlTemplate.FuncMap{
+			// help text is authored by site admins in config and may contain markup
+			"trusted": func(s string) htmlTemplate.HTML { return htmlTemplate.HTML(s) },
+			"join":    strings.Join,
+		}).ParseFS(templates.FS, "data-dictionary.html.tmpl")
+		if err != nil {
+			return "", err
+		}
+		err = tmpl.Execute(&buf, dictionary)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported format %s", format)
+	}
+
+	return buf.String(), nil
+}
+
+// markdownCell makes a string safe to place in a markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
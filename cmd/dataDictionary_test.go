@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDataDictionary(t *testing.T) {
+	data, err := configDirStructData(fixtureConfigDir)
+	require.NoError(t, err)
+
+	columns := []CsvColumn{
+		{ColumnName: "Object Model", Tag: "field_model"},
+		{ColumnName: "Archival Box", Tag: "field_note.attr0=box"},
+	}
+	dictionary := buildDataDictionary(data, columns)
+	require.NotEmpty(t, dictionary.Bundles)
+	assert.Equal(t, "IslandoraObject", dictionary.Bundles[0].Name)
+
+	fields := map[string]DictionaryField{}
+	for _, f := range dictionary.Bundles[0].Fields {
+		fields[f.MachineName] = f
+	}
+	assert.Equal(t, []string{"Object Model"}, fields["field_model"].SheetsColumns)
+	assert.Equal(t, "Single", fields["field_model"].Cardinality)
+	assert.True(t, fields["field_model"].Required)
+	assert.Equal(t, []string{"genre"}, fields["field_genre"].Vocabularies)
+	assert.Equal(t, "Unlimited", fields["field_genre"].Cardinality)
+
+	md, err := generateDataDictionary(dictionary, "markdown")
+	require.NoError(t, err)
+	assert.Contains(t, md, "| `field_model` | Model |")
+
+	html, err := generateDataDictionary(dictionary, "html")
+	require.NoError(t, err)
+	assert.Contains(t, html, "<td><code>field_model</code></td>")
+
+	_, err = generateDataDictionary(dictionary, "pdf")
+	assert.Error(t, err)
+}
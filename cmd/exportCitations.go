@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/citation"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/spf13/cobra"
+)
+
+var citationExtensions = map[string]string{
+	"bibtex":  "bib",
+	"ris":     "ris",
+	"csljson": "json",
+}
+
+// exportCitationsCmd represents the export citations command
+var exportCitationsCmd = &cobra.Command{
+	Use:   "citations",
+	Short: "Export citations of an Islandora node for reference managers",
+	Long: `Export citations of an Islandora node, or with --recursive its subtree,
+as BibTeX, RIS or CSL-JSON.
+
+The entry type comes from the node's genre, or its model when the genre isn't
+recognized. Linked agents with relators:aut or relators:cre are authors and
+relators:edt editors, the EDTF date issued is the issued date, and part details
+are the volume, issue and pages.
+
+BibTeX is written with LaTeX accents e.g. {\"o} so it works with classic BibTeX,
+--bibtex-utf8 keeps accented letters as they are for biber and biblatex.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		publisher, _ := cmd.Flags().GetString("publisher")
+		utf8, _ := cmd.Flags().GetBool("bibtex-utf8")
+
+		if baseUrl == "" || nid == 0 {
+			slog.Error("--baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+		extension, ok := citationExtensions[format]
+		if !ok {
+			slog.Error("--format must be bibtex, ris or csljson", "format", format)
+			os.Exit(1)
+		}
+		if output == "" {
+			output = "citations." + extension
+		}
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		nodes, err := fetchExportNodes(baseUrl, nid, recursive)
+		if err != nil {
+			slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		opts := citation.Options{
+			Mapping:   mapping,
+			Terms:     islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl:   baseUrl,
+			Publisher: publisher,
+		}
+		items := make([]*citation.Item, len(nodes))
+		for i, node := range nodes {
+			items[i], err = citation.FromNode(node, opts)
+			if err != nil {
+				slog.Error("Unable to crosswalk node", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+		}
+
+		var data []byte
+		switch format {
+		case "bibtex":
+			data = citation.Bibtex(items, utf8)
+		case "ris":
+			data = citation.Ris(items)
+		case "csljson":
+			data, err = citation.CslJson(items)
+			if err != nil {
+				slog.Error("Unable to encode CSL-JSON", "err", err)
+				os.Exit(1)
+			}
+		}
+
+		err = os.WriteFile(output, data, 0644)
+		if err != nil {
+			slog.Error("Error writing output file", "file", output, "err", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d citations into %s\n", len(items), output)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportCitationsCmd)
+
+	exportCitationsCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportCitationsCmd.Flags().Bool("recursive", false, "Also export the node's descendants")
+	exportCitationsCmd.Flags().String("format", "bibtex", "bibtex, ris or csljson")
+	exportCitationsCmd.Flags().String("output", "", "The file to save the citations to (default: citations.bib, citations.ris or citations.json)")
+	exportCitationsCmd.Flags().String("publisher", "", "Publisher for nodes without one")
+	exportCitationsCmd.Flags().Bool("bibtex-utf8", false, "Keep accented letters as UTF-8 in BibTeX instead of LaTeX accents")
+	exportCitationsCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+}
@@ -47,10 +47,37 @@ var exportCrossref = &cobra.Command{
 			os.Exit(1)
 		}
 
-		nodes, err := islandora.FetchNodes(baseUrl, nid)
+		run, err := newIncrementalExport(cmd, baseUrl, nid)
+		if err != nil {
+			slog.Error("Unable to determine what to export", "err", err)
+			os.Exit(1)
+		}
+
+		// every node is needed for the journal's structure
+		// but only changed volumes and articles are deposited
+		var (
+			nodes   []*api.IslandoraObject
+			changed map[int]bool
+		)
+		if run.Incremental() {
+			nodes, changed, err = islandora.FetchNodesChanged(baseUrl, nid, run.Since)
+		} else {
+			nodes, err = islandora.FetchNodes(baseUrl, nid)
+		}
 		if err != nil {
 			log.Fatal(err)
 		}
+		include := func(nids ...int) bool {
+			if !run.Incremental() {
+				return true
+			}
+			for _, n := range nids {
+				if changed[n] {
+					return true
+				}
+			}
+			return false
+		}
 
 		var (
 			volumes     []crossref.JournalVolume
@@ -137,6 +164,10 @@ var exportCrossref = &cobra.Command{
 
 			// If volume has no children AND has article-like content, treat as direct article
 			if !hasChildren && node.FieldFullTitle != nil && node.FieldFullTitle.String() != "" {
+				if !include(currentNidInt) {
+					continue
+				}
+
 				// Extract year from volume node's date
 				var articleYear int
 				if node.FieldEdtfDateIssued != nil {
@@ -268,6 +299,10 @@ var exportCrossref = &cobra.Command{
 				if !isArticleInVolume {
 					continue
 				}
+				childNid, _ := strconv.Atoi(childNidStr)
+				if !include(currentNidInt, childNid) {
+					continue
+				}
 
 				// Extract article metadata
 				article := crossref.Article{
@@ -380,6 +415,12 @@ var exportCrossref = &cobra.Command{
 			os.Exit(1)
 		}
 
+		err = run.Done()
+		if err != nil {
+			slog.Error("Unable to record the export", "err", err)
+			os.Exit(1)
+		}
+
 		slog.Info("Crossref journal written", "file", target, "volumes", len(volumes), "direct_articles", len(articles))
 	},
 }
@@ -395,4 +436,5 @@ func init() {
 	exportCrossref.Flags().StringVar(&journalDoi, "journal-doi", "", "Journal's DOI")
 	exportCrossref.Flags().StringVar(&journalUrl, "journal-url", "", "Journal's URL")
 	exportCrossref.Flags().StringVar(&target, "target", "", "Where to save target file")
+	addSinceFlags(exportCrossref)
 }
@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
 	"github.com/spf13/cobra"
 )
 
@@ -41,13 +47,109 @@ func (a ByFieldMemberOfAndWeight) Less(i, j int) bool {
 var exportCsvCmd = &cobra.Command{
 	Use:   "csv",
 	Short: "Recursively export a workbench CSV for an Islandora node",
-	Long:  `Recursively export a workbench CSV for an Islandora node.`,
+	Long: `Recursively export a workbench CSV for an Islandora node.
+
+The node and all of its descendants are fetched, flattened into a single CSV
+with the columns populated on any of them, and sorted by parent and field_weight.
+
+With --since only the nodes changed since then are exported. "last run" uses the
+start of the last successful export of the same site and node from --state-file.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		fields, _ := cmd.Flags().GetStringSlice("fields")
+		exclude, _ := cmd.Flags().GetStringSlice("exclude-fields")
+		termLabels, _ := cmd.Flags().GetBool("term-labels")
+
 		if baseUrl == "" || nid == 0 {
 			slog.Error("--baseUrl and --nid flags are required")
 			os.Exit(1)
 		}
-		fmt.Println("CSV files merged successfully into", csvFile)
+
+		run, err := newIncrementalExport(cmd, baseUrl, nid)
+		if err != nil {
+			slog.Error("Unable to determine what to export", "err", err)
+			os.Exit(1)
+		}
+
+		var nodes []*api.IslandoraObject
+		if run.Incremental() {
+			all, changed, err := islandora.FetchNodesChanged(baseUrl, nid, run.Since)
+			if err != nil {
+				slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+				os.Exit(1)
+			}
+			for _, node := range all {
+				id, _ := node.Nid.MarshalCSV()
+				if n, _ := strconv.Atoi(id); changed[n] {
+					nodes = append(nodes, node)
+				}
+			}
+			slog.Info("Exporting changed nodes", "since", run.Since, "changed", len(nodes), "nodes", len(all))
+		} else {
+			nodes, err = islandora.FetchNodes(baseUrl, nid)
+			if err != nil {
+				slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+				os.Exit(1)
+			}
+		}
+
+		opts := workbench.NodeOptions{}
+		if termLabels {
+			opts.Labeler = termLabeler(baseUrl)
+		}
+		records, err := workbench.NodeRecords(nodes, opts)
+		if err != nil {
+			slog.Error("Unable to flatten nodes", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		header := records[0]
+		rows := make([]Row, len(records)-1)
+		for r, record := range records[1:] {
+			rows[r] = Row{}
+			for i, column := range header {
+				rows[r][column] = record[i]
+			}
+		}
+		sort.Stable(ByFieldMemberOfAndWeight(rows))
+
+		file, err := os.Create(csvFile)
+		if err != nil {
+			slog.Error("Unable to create CSV", "file", csvFile, "err", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		columns := selectColumns(header, fields, exclude)
+		writer := csv.NewWriter(file)
+		err = writer.Write(columns)
+		if err != nil {
+			slog.Error("Unable to write CSV", "file", csvFile, "err", err)
+			os.Exit(1)
+		}
+		for _, row := range rows {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				record[i] = row[column]
+			}
+			err = writer.Write(record)
+			if err != nil {
+				slog.Error("Unable to write CSV", "file", csvFile, "err", err)
+				os.Exit(1)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			slog.Error("Unable to write CSV", "file", csvFile, "err", err)
+			os.Exit(1)
+		}
+
+		err = run.Done()
+		if err != nil {
+			slog.Error("Unable to record the export", "err", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Exported %d nodes into %s\n", len(rows), csvFile)
 	},
 }
 
@@ -55,4 +157,35 @@ func init() {
 	exportCmd.AddCommand(exportCsvCmd)
 	exportCsvCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export a CSV")
 	exportCsvCmd.Flags().StringVar(&csvFile, "output", "merged.csv", "The CSV file name to save the export to")
+	exportCsvCmd.Flags().StringSlice("fields", []string{}, "Only export these fields, in this order (node_id is always included)")
+	exportCsvCmd.Flags().StringSlice("exclude-fields", []string{}, "Fields to leave out of the export")
+	exportCsvCmd.Flags().Bool("term-labels", false, "Export taxonomy terms as vocabulary_id:label instead of term IDs")
+	addSinceFlags(exportCsvCmd)
+}
+
+// selectColumns limits the exported columns to fields, in the order given,
+// leaving out the excluded columns
+func selectColumns(header, fields, exclude []string) []string {
+	columns := header
+	if len(fields) > 0 {
+		columns = []string{}
+		if slices.Contains(header, "node_id") && !slices.Contains(fields, "node_id") {
+			columns = append(columns, "node_id")
+		}
+		for _, field := range fields {
+			if slices.Contains(header, field) && !slices.Contains(columns, field) {
+				columns = append(columns, field)
+			}
+		}
+	}
+
+	selected := []string{}
+	for _, column := range columns {
+		if column != "node_id" && slices.Contains(exclude, column) {
+			continue
+		}
+		selected = append(selected, column)
+	}
+
+	return selected
 }
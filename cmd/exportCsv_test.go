@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectColumns(t *testing.T) {
+	header := []string{"field_member_of", "field_weight", "node_id", "title"}
+	assert.Equal(t, header, selectColumns(header, nil, nil))
+	assert.Equal(t, []string{"node_id", "title", "field_weight"}, selectColumns(header, []string{"title", "bogus", "field_weight"}, nil))
+	assert.Equal(t, []string{"field_member_of", "node_id"}, selectColumns(header, nil, []string{"title", "field_weight", "node_id"}))
+}
+
+func TestByFieldMemberOfAndWeight(t *testing.T) {
+	rows := []Row{
+		{"node_id": "3", "field_member_of": "2", "field_weight": "10"},
+		{"node_id": "4", "field_member_of": "2", "field_weight": "2"},
+		{"node_id": "2", "field_member_of": "1"},
+	}
+	sort.Stable(ByFieldMemberOfAndWeight(rows))
+
+	nids := []string{}
+	for _, row := range rows {
+		nids = append(nids, row["node_id"])
+	}
+	assert.Equal(t, []string{"2", "4", "3"}, nids)
+}
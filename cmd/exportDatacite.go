@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/datacite"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/spf13/cobra"
+)
+
+// exportDataciteCmd represents the export datacite command
+var exportDataciteCmd = &cobra.Command{
+	Use:   "datacite",
+	Short: "Export DataCite metadata for an Islandora node",
+	Long: `Export DataCite Metadata Schema 4.x for an Islandora node and optionally its descendants.
+
+Each node is written to --output-dir as NID.xml and/or NID.json, the JSON being
+a DataCite REST API request body that can be sent to POST /dois or PUT /dois/DOI.
+
+Required properties (DOI, creator, title, publisher, publicationYear and
+resourceTypeGeneral) are checked before anything is written. Nodes missing
+them are reported and skipped, and the command exits non-zero.
+
+With --prefix, nodes without a DOI are exported as JSON with the prefix
+instead, and DataCite generates the DOI when the request is sent. A DOI is
+still required for XML and for --event publish.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		formats, _ := cmd.Flags().GetStringSlice("format")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		publisher, _ := cmd.Flags().GetString("publisher")
+		event, _ := cmd.Flags().GetString("event")
+		prefix, _ := cmd.Flags().GetString("prefix")
+
+		if baseUrl == "" || nid == 0 {
+			slog.Error("--baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+		for _, format := range formats {
+			if format != "xml" && format != "json" {
+				slog.Error("--format must be xml or json", "format", format)
+				os.Exit(1)
+			}
+		}
+		if event != "" && !slices.Contains([]string{"publish", "register", "hide"}, event) {
+			slog.Error("--event must be publish, register or hide", "event", event)
+			os.Exit(1)
+		}
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		nodes, err := fetchExportNodes(baseUrl, nid, recursive)
+		if err != nil {
+			slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		err = os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			slog.Error("Unable to create output directory", "dir", outputDir, "err", err)
+			os.Exit(1)
+		}
+
+		opts := datacite.Options{
+			Mapping:   mapping,
+			Terms:     islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl:   baseUrl,
+			Publisher: publisher,
+			Prefix:    prefix,
+		}
+		requireDoi := slices.Contains(formats, "xml") || event == "publish"
+		exported, invalid := 0, 0
+		for _, node := range nodes {
+			resource, err := datacite.FromNode(node, opts)
+			if err != nil {
+				slog.Error("Unable to crosswalk node", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+
+			problems := datacite.Validate(resource, requireDoi)
+			if len(problems) > 0 {
+				invalid++
+				fmt.Printf("node %s is missing required DataCite properties\n", node.Nid.String())
+				for _, problem := range problems {
+					fmt.Printf("  %s\n", problem)
+				}
+				continue
+			}
+
+			for _, format := range formats {
+				var data []byte
+				if format == "xml" {
+					data, err = datacite.Marshal(resource)
+				} else {
+					data, err = datacite.MarshalJSON(resource, event)
+				}
+				if err != nil {
+					slog.Error("Unable to encode DataCite metadata", "nid", node.Nid.String(), "format", format, "err", err)
+					os.Exit(1)
+				}
+				file := filepath.Join(outputDir, node.Nid.String()+"."+format)
+				err = os.WriteFile(file, data, 0644)
+				if err != nil {
+					slog.Error("Error writing output file", "file", file, "err", err)
+					os.Exit(1)
+				}
+			}
+			exported++
+		}
+
+		fmt.Printf("Exported %d DataCite records into %s\n", exported, outputDir)
+		if invalid > 0 {
+			slog.Error("Some nodes were not exported", "invalid", invalid)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportDataciteCmd)
+
+	exportDataciteCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportDataciteCmd.Flags().Bool("recursive", false, "Also export the node's descendants")
+	exportDataciteCmd.Flags().StringSlice("format", []string{"xml", "json"}, "Formats to write, xml and/or json (REST API)")
+	exportDataciteCmd.Flags().String("output-dir", "datacite", "The directory to save the records to")
+	exportDataciteCmd.Flags().String("publisher", "", "Publisher for nodes without a publisher")
+	exportDataciteCmd.Flags().String("event", "", "REST API event to include in the JSON: publish, register or hide (default: create a draft)")
+	exportDataciteCmd.Flags().String("prefix", "", "DOI prefix to send in the JSON for nodes without a DOI, so DataCite generates one (e.g. 10.1234)")
+	exportDataciteCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements, which DataCite properties are derived from (default: the built-in mapping)")
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/iiif"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/spf13/cobra"
+)
+
+// exportIiifCmd represents the export iiif command
+var exportIiifCmd = &cobra.Command{
+	Use:   "iiif",
+	Short: "Export IIIF Presentation 3.0 manifests for an Islandora node",
+	Long: `Export a IIIF Presentation 3.0 manifest for an Islandora node.
+
+Paged content gets a canvas per child page, ordered by field_weight, and
+other objects a single canvas. Canvases paint the node's service file
+through the IIIF image server given by --image-server. A collection node
+produces a IIIF Collection, and with --recursive its members' manifests
+and collections are exported too. Members without a service file image,
+like documents and audio, are left out of the collection.
+
+Manifest metadata is crosswalked from the node's fields through the MODS
+mapping, which can be overridden with --mapping.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		imageServer, _ := cmd.Flags().GetString("image-server")
+		imageApiVersion, _ := cmd.Flags().GetInt("image-api-version")
+		manifestUrl, _ := cmd.Flags().GetString("manifest-url")
+		serviceFileUse, _ := cmd.Flags().GetString("service-file-use")
+		collectionModels, _ := cmd.Flags().GetStringSlice("collection-models")
+		pagedModels, _ := cmd.Flags().GetStringSlice("paged-models")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+
+		if baseUrl == "" || nid == 0 || imageServer == "" {
+			slog.Error("--baseUrl, --nid and --image-server flags are required")
+			os.Exit(1)
+		}
+		if imageApiVersion != 2 && imageApiVersion != 3 {
+			slog.Error("--image-api-version must be 2 or 3")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		builder := iiif.NewBuilder(iiif.Options{
+			Source:           islandora.NewSite(baseUrl),
+			BaseUrl:          baseUrl,
+			ManifestUrl:      manifestUrl,
+			ImageServer:      imageServer,
+			ImageApiVersion:  imageApiVersion,
+			ServiceFileUse:   serviceFileUse,
+			CollectionModels: collectionModels,
+			PagedModels:      pagedModels,
+			Mapping:          mapping,
+		})
+
+		node, err := islandora.FetchNode(fmt.Sprintf("%s/node/%d?_format=json", baseUrl, nid))
+		if err != nil {
+			slog.Error("Unable to fetch node", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		err = os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			slog.Error("Unable to create output directory", "dir", outputDir, "err", err)
+			os.Exit(1)
+		}
+
+		exported := 0
+		root := node
+		queue := []*api.IslandoraObject{root}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+
+			doc, err := builder.Build(node)
+			if errors.Is(err, iiif.ErrNoImage) && node != root {
+				slog.Warn("Skipping member without an image", "nid", node.Nid.String(), "err", err)
+				continue
+			}
+			if err != nil {
+				slog.Error("Unable to build IIIF document", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+			if c, ok := doc.(*iiif.Collection); ok && recursive {
+				queue = append(queue, c.Members()...)
+			}
+
+			data, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				slog.Error("Unable to encode IIIF document", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+			file := filepath.Join(outputDir, node.Nid.String()+".json")
+			err = os.WriteFile(file, data, 0644)
+			if err != nil {
+				slog.Error("Error writing output file", "file", file, "err", err)
+				os.Exit(1)
+			}
+			exported++
+		}
+		fmt.Printf("Exported %d IIIF documents into %s\n", exported, outputDir)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportIiifCmd)
+
+	exportIiifCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportIiifCmd.Flags().Bool("recursive", false, "Also export the manifests of a collection's members")
+	exportIiifCmd.Flags().String("output-dir", "iiif", "The directory to save a file per node to")
+	exportIiifCmd.Flags().String("image-server", "", "Base URL of the IIIF image server (e.g. https://iiif.google.com/iiif/3)")
+	exportIiifCmd.Flags().Int("image-api-version", 3, "IIIF Image API version of the image server, 2 or 3")
+	exportIiifCmd.Flags().String("manifest-url", "", "URL manifests are published at, {nid} is replaced with the node ID (default: {baseUrl}/node/{nid}/manifest)")
+	exportIiifCmd.Flags().String("service-file-use", "Service File", "Media use of the images painted on canvases")
+	exportIiifCmd.Flags().StringSlice("collection-models", []string{"Collection"}, "Models exported as IIIF Collections")
+	exportIiifCmd.Flags().StringSlice("paged-models", []string{"Paged Content", "Publication Issue"}, "Models whose member pages are a manifest's canvases")
+	exportIiifCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+}
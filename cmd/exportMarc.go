@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/marc"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/proquest"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/html/charset"
+)
+
+// exportMarcCmd represents the export marc command
+var exportMarcCmd = &cobra.Command{
+	Use:   "marc",
+	Short: "Export MARCXML records for ETDs",
+	Long: `Export MARC 21 bibliographic records for electronic theses and dissertations
+as a MARCXML collection, for loading into the library catalog or OCLC.
+
+Records are built from an ETD node, or with --recursive a collection of them,
+or from a directory of ProQuest submission ZIPs (or their _DATA.xml files)
+with --source, so records can be made before the ETDs are ingested.
+
+Each record has the fixed fields for an online thesis, the author and advisors
+with relator terms, the title split into title and remainder, the 264
+publication statement, the page count, a 502 dissertation note, the abstract,
+subjects and keywords, an embargo note and a link to the repository.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		output, _ := cmd.Flags().GetString("output")
+		submissions, _ := cmd.Flags().GetString("source")
+
+		if submissions == "" && (baseUrl == "" || nid == 0) {
+			slog.Error("--source or the --baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+		opts := marc.Options{
+			Mapping: mapping,
+			Terms:   islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl: baseUrl,
+		}
+		opts.Institution, _ = cmd.Flags().GetString("institution")
+		opts.Place, _ = cmd.Flags().GetString("place")
+		opts.PlaceCode, _ = cmd.Flags().GetString("place-code")
+		opts.Agency, _ = cmd.Flags().GetString("agency")
+
+		var etds []*marc.Etd
+		if submissions != "" {
+			etds, err = readSubmissions(submissions)
+			if err != nil {
+				slog.Error("Unable to read ProQuest submissions", "source", submissions, "err", err)
+				os.Exit(1)
+			}
+		} else {
+			nodes, err := fetchExportNodes(baseUrl, nid, recursive)
+			if err != nil {
+				slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+				os.Exit(1)
+			}
+			for _, node := range nodes {
+				etd, err := marc.FromNode(node, opts)
+				if err != nil {
+					slog.Error("Unable to crosswalk node", "nid", node.Nid.String(), "err", err)
+					os.Exit(1)
+				}
+				etds = append(etds, etd)
+			}
+		}
+
+		records := make([]*marc.Record, len(etds))
+		for i, etd := range etds {
+			records[i] = etd.Record(opts)
+		}
+		data, err := marc.MarshalCollection(records)
+		if err != nil {
+			slog.Error("Unable to encode MARCXML", "err", err)
+			os.Exit(1)
+		}
+		err = os.WriteFile(output, data, 0644)
+		if err != nil {
+			slog.Error("Error writing output file", "file", output, "err", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d records into %s\n", len(records), output)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportMarcCmd)
+
+	exportMarcCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportMarcCmd.Flags().Bool("recursive", false, "Also export the node's descendants")
+	exportMarcCmd.Flags().String("source", "", "Directory of ProQuest submission ZIPs or _DATA.xml files to export instead of nodes")
+	exportMarcCmd.Flags().String("output", "marc.xml", "The file to save the MARCXML collection to")
+	exportMarcCmd.Flags().String("institution", "", "Degree granting institution for ETDs without one")
+	exportMarcCmd.Flags().String("place", "", "Place of publication in 264 e.g. Bethlehem, Pennsylvania")
+	exportMarcCmd.Flags().String("place-code", "xx", "MARC country code for the 008 e.g. pau")
+	exportMarcCmd.Flags().String("agency", "", "MARC organization code of the cataloging agency for the 040")
+	exportMarcCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+}
+
+// readSubmissions decodes the ProQuest submissions in a directory, sorted by file name
+func readSubmissions(dir string) ([]*marc.Etd, error) {
+	isDir, err := isDirectory(dir)
+	if !isDir || err != nil {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var etds []*marc.Etd
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		var submission *proquest.DISSSubmission
+		switch {
+		case strings.HasSuffix(d.Name(), ".zip"):
+			submission, err = zipSubmission(path)
+		case strings.HasSuffix(d.Name(), "_DATA.xml"):
+			var f *os.File
+			f, err = os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			submission, err = decodeSubmission(f)
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		etds = append(etds, marc.FromSubmission(*submission))
+		return nil
+	})
+
+	return etds, err
+}
+
+func zipSubmission(path string) (*proquest.DISSSubmission, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if !strings.HasSuffix(file.Name, "_DATA.xml") {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open XML file in ZIP: %w", err)
+		}
+		defer f.Close()
+		return decodeSubmission(f)
+	}
+
+	return nil, fmt.Errorf("no _DATA.xml file found")
+}
+
+func decodeSubmission(r io.Reader) (*proquest.DISSSubmission, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var submission proquest.DISSSubmission
+	if err := decoder.Decode(&submission); err != nil {
+		return nil, fmt.Errorf("failed to decode XML: %w", err)
+	}
+
+	return &submission, nil
+}
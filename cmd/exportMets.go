@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mets"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/spf13/cobra"
+)
+
+// exportMetsCmd represents the export mets command
+var exportMetsCmd = &cobra.Command{
+	Use:   "mets",
+	Short: "Export a METS document for a complex or paged Islandora object",
+	Long: `Export a METS document describing an Islandora node and its members,
+for exchange with partners like HathiTrust.
+
+The physical structMap has a div for the node with a div per member, ordered
+by field_weight, recursively. Members are pages for paged content, and
+chapters when they have a chapter part detail. Each div points to its media
+files in the fileSec, grouped by media use, with their MIME type, size and
+a checksum calculated by streaming the file from the site.
+
+Each node's descriptive metadata is wrapped in a dmdSec as MODS, or Dublin
+Core with --dmd=dc, and its field_rights in an amdSec rightsMD as METSRights.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		dmd, _ := cmd.Flags().GetString("dmd")
+		agent, _ := cmd.Flags().GetString("agent")
+		fileUses, _ := cmd.Flags().GetStringSlice("file-use")
+		checksumType, _ := cmd.Flags().GetString("checksum-type")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+
+		if baseUrl == "" || nid == 0 {
+			slog.Error("--baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+		if output == "" {
+			output = fmt.Sprintf("%d.mets.xml", nid)
+		}
+		if len(fileUses) == 0 {
+			fileUses = nil
+		}
+		if checksumType == "none" {
+			checksumType = ""
+		}
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		node, err := islandora.FetchNode(fmt.Sprintf("%s/node/%d?_format=json", baseUrl, nid))
+		if err != nil {
+			slog.Error("Unable to fetch node", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		builder := mets.NewBuilder(mets.Options{
+			Source:       islandora.NewSite(baseUrl),
+			BaseUrl:      baseUrl,
+			Mapping:      mapping,
+			Dmd:          dmd,
+			Agent:        agent,
+			FileUses:     fileUses,
+			ChecksumType: checksumType,
+			Checksum:     islandora.HashFile,
+		})
+		doc, err := builder.Build(node)
+		if err != nil {
+			slog.Error("Unable to build METS document", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+		data, err := mets.Marshal(doc)
+		if err != nil {
+			slog.Error("Unable to encode METS document", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+		err = os.WriteFile(output, data, 0644)
+		if err != nil {
+			slog.Error("Error writing output file", "file", output, "err", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported METS for node %d into %s\n", nid, output)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportMetsCmd)
+
+	exportMetsCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportMetsCmd.Flags().String("output", "", "The file to save the METS document to (default: NID.mets.xml)")
+	exportMetsCmd.Flags().String("dmd", "mods", "Descriptive metadata to wrap in dmdSecs, mods or dc")
+	exportMetsCmd.Flags().String("agent", "", "Organization named as the METS document's creator")
+	exportMetsCmd.Flags().StringSlice("file-use", []string{}, "Media uses to include in the fileSec, in order (default: all)")
+	exportMetsCmd.Flags().String("checksum-type", "MD5", "File checksums, MD5, SHA-1, SHA-256, SHA-512 or none")
+	exportMetsCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+}
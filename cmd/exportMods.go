@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/spf13/cobra"
+)
+
+// exportModsCmd represents the export mods command
+var exportModsCmd = &cobra.Command{
+	Use:   "mods",
+	Short: "Export MODS XML for an Islandora node and its descendants",
+	Long: `Export MODS 3.x XML for an Islandora node and its descendants.
+
+Each node is written to its own file in --output-dir, or with --collection
+all of them are written to a single modsCollection in --output.
+
+Which fields populate each MODS element can be overridden with --mapping,
+see pkg/mods/mapping.yaml for the default mapping.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		collection, _ := cmd.Flags().GetBool("collection")
+		output, _ := cmd.Flags().GetString("output")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+
+		if baseUrl == "" || nid == 0 {
+			slog.Error("--baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		nodes, err := fetchExportNodes(baseUrl, nid, recursive)
+		if err != nil {
+			slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		opts := mods.Options{
+			Mapping: mapping,
+			Terms:   islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl: baseUrl,
+		}
+		records := make([]*mods.Mods, len(nodes))
+		for i, node := range nodes {
+			records[i], err = mods.FromNode(node, opts)
+			if err != nil {
+				slog.Error("Unable to crosswalk node", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+		}
+
+		if collection {
+			data, err := mods.MarshalCollection(records)
+			if err != nil {
+				slog.Error("Unable to encode MODS", "err", err)
+				os.Exit(1)
+			}
+			err = os.WriteFile(output, data, 0644)
+			if err != nil {
+				slog.Error("Error writing output file", "file", output, "err", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Exported %d MODS records into %s\n", len(records), output)
+			return
+		}
+
+		err = os.MkdirAll(outputDir, 0755)
+		if err != nil {
+			slog.Error("Unable to create output directory", "dir", outputDir, "err", err)
+			os.Exit(1)
+		}
+		for i, record := range records {
+			data, err := mods.Marshal(record)
+			if err != nil {
+				slog.Error("Unable to encode MODS", "nid", nodes[i].Nid.String(), "err", err)
+				os.Exit(1)
+			}
+			file := filepath.Join(outputDir, nodes[i].Nid.String()+".xml")
+			err = os.WriteFile(file, data, 0644)
+			if err != nil {
+				slog.Error("Error writing output file", "file", file, "err", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("Exported %d MODS records into %s\n", len(records), outputDir)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportModsCmd)
+
+	exportModsCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportModsCmd.Flags().Bool("recursive", true, "Also export the node's descendants")
+	exportModsCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+	exportModsCmd.Flags().Bool("collection", false, "Write a single modsCollection to --output instead of a file per node")
+	exportModsCmd.Flags().String("output", "mods.xml", "The file to save the modsCollection to")
+	exportModsCmd.Flags().String("output-dir", "mods", "The directory to save a file per node to")
+}
+
+// fetchExportNodes fetches a node, and its descendants when recursive
+func fetchExportNodes(baseUrl string, nid int, recursive bool) ([]*api.IslandoraObject, error) {
+	if recursive {
+		return islandora.FetchNodes(baseUrl, nid)
+	}
+
+	node, err := islandora.FetchNode(fmt.Sprintf("%s/node/%d?_format=json", baseUrl, nid))
+	if err != nil {
+		return nil, err
+	}
+
+	return []*api.IslandoraObject{node}, nil
+}
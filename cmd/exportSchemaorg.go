@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/schemaorg"
+	"github.com/spf13/cobra"
+)
+
+// exportSchemaorgCmd represents the export schemaorg command
+var exportSchemaorgCmd = &cobra.Command{
+	Use:   "schemaorg",
+	Short: "Export schema.org JSON-LD and Google Scholar meta tags",
+	Long: `Export schema.org JSON-LD and the equivalent Highwire Press citation_* meta tags
+for an Islandora node and optionally its descendants.
+
+The JSON-LD type is ScholarlyArticle, Thesis, Dataset, ImageObject or CreativeWork
+depending on the node's genre and model. Each node is written to --output-dir
+as NID.jsonld and NID.html, the latter holding the <meta> tags.
+
+Nodes Google Scholar would not index, because they are missing a title, author,
+publication date or PDF, or have field_hide_gscholar_metatags set, are reported.
+With --check nothing is written and the command exits non-zero when any are found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		publisher, _ := cmd.Flags().GetString("publisher")
+		fileUse, _ := cmd.Flags().GetString("file-use")
+		check, _ := cmd.Flags().GetBool("check")
+
+		if baseUrl == "" || nid == 0 {
+			slog.Error("--baseUrl and --nid flags are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		nodes, err := fetchExportNodes(baseUrl, nid, recursive)
+		if err != nil {
+			slog.Error("Unable to fetch nodes", "nid", nid, "err", err)
+			os.Exit(1)
+		}
+
+		if !check {
+			err = os.MkdirAll(outputDir, 0755)
+			if err != nil {
+				slog.Error("Unable to create output directory", "dir", outputDir, "err", err)
+				os.Exit(1)
+			}
+		}
+
+		opts := schemaorg.Options{
+			Mapping:   mapping,
+			Terms:     islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl:   baseUrl,
+			Publisher: publisher,
+			FileUse:   fileUse,
+		}
+		flagged := 0
+		for _, node := range nodes {
+			media, err := islandora.FetchMedia(baseUrl, (*node.Nid)[0].Value)
+			if err != nil {
+				slog.Error("Unable to fetch media", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+			m, err := schemaorg.FromNode(node, media, opts)
+			if err != nil {
+				slog.Error("Unable to crosswalk node", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+
+			if problems := schemaorg.Check(m); len(problems) > 0 {
+				flagged++
+				fmt.Printf("node %s (%s) would not be indexed by Google Scholar\n", node.Nid.String(), m.JsonLd.Type)
+				for _, problem := range problems {
+					fmt.Printf("  %s\n", problem)
+				}
+			}
+			if check {
+				continue
+			}
+
+			data, err := json.MarshalIndent(m.JsonLd, "", "  ")
+			if err != nil {
+				slog.Error("Unable to encode JSON-LD", "nid", node.Nid.String(), "err", err)
+				os.Exit(1)
+			}
+			files := map[string][]byte{
+				node.Nid.String() + ".jsonld": append(data, '\n'),
+				node.Nid.String() + ".html":   []byte(schemaorg.HTML(m.Tags)),
+			}
+			for name, data := range files {
+				file := filepath.Join(outputDir, name)
+				err = os.WriteFile(file, data, 0644)
+				if err != nil {
+					slog.Error("Error writing output file", "file", file, "err", err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		if check {
+			fmt.Printf("%d of %d nodes would not be indexed by Google Scholar\n", flagged, len(nodes))
+			if flagged > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+		fmt.Printf("Exported %d schema.org records into %s\n", len(nodes), outputDir)
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportSchemaorgCmd)
+
+	exportSchemaorgCmd.Flags().IntVar(&nid, "nid", 0, "The node ID to export")
+	exportSchemaorgCmd.Flags().Bool("recursive", false, "Also export the node's descendants")
+	exportSchemaorgCmd.Flags().String("output-dir", "schemaorg", "The directory to save the JSON-LD and meta tags to")
+	exportSchemaorgCmd.Flags().String("publisher", "", "Publisher, and thesis institution, for nodes without one")
+	exportSchemaorgCmd.Flags().String("file-use", "Original File", "Media use of the file linked as citation_pdf_url")
+	exportSchemaorgCmd.Flags().Bool("check", false, "Only report nodes Google Scholar would not index, exiting non-zero when there are any")
+	exportSchemaorgCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+}
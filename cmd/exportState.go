@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// exportState records the start of the last successful export
+// keyed by export, site and root nid
+type exportState map[string]time.Time
+
+// addSinceFlags adds the flags for incremental exports
+func addSinceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("since", "", `Only export nodes changed since an RFC3339 timestamp or the "last run"`)
+	cmd.Flags().String("state-file", defaultStateFile(), "JSON file recording the last successful run of each export")
+}
+
+func defaultStateFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "go-islandora-export-state.json"
+	}
+
+	return filepath.Join(dir, "go-islandora", "export-state.json")
+}
+
+func exportStateKey(export, baseUrl string, nid int) string {
+	return fmt.Sprintf("%s %s %d", export, strings.TrimSuffix(baseUrl, "/"), nid)
+}
+
+func loadExportState(path string) (exportState, error) {
+	state := exportState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+
+	return state, nil
+}
+
+// save writes the state file, replacing it only once it's fully written
+func (s exportState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// resolveSince parses --since, looking up "last run" in the state
+// the zero time is returned when everything should be exported
+func resolveSince(since string, state exportState, key string) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(since)) {
+	case "":
+		return time.Time{}, nil
+	case "last run", "last-run", "lastrun":
+		last, ok := state[key]
+		if !ok {
+			slog.Warn("No previous run recorded, exporting everything", "export", key)
+			return time.Time{}, nil
+		}
+		return last, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`--since must be an RFC3339 timestamp or "last run": %v`, err)
+	}
+
+	return t, nil
+}
+
+// incrementalExport tracks an export run so the next run can export what changed since it started
+type incrementalExport struct {
+	stateFile string
+	state     exportState
+	key       string
+	started   time.Time
+	// Since is the zero time when everything is exported
+	Since time.Time
+}
+
+func newIncrementalExport(cmd *cobra.Command, baseUrl string, nid int) (*incrementalExport, error) {
+	since, _ := cmd.Flags().GetString("since")
+	stateFile, _ := cmd.Flags().GetString("state-file")
+
+	state, err := loadExportState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &incrementalExport{
+		stateFile: stateFile,
+		state:     state,
+		key:       exportStateKey(cmd.Name(), baseUrl, nid),
+		started:   time.Now().UTC(),
+	}
+	e.Since, err = resolveSince(since, state, e.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Incremental reports whether only changed nodes should be exported
+func (e *incrementalExport) Incremental() bool {
+	return !e.Since.IsZero()
+}
+
+// Done records a successful run
+func (e *incrementalExport) Done() error {
+	e.state[e.key] = e.started
+	return e.state.save(e.stateFile)
+}
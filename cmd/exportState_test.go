@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "export-state.json")
+	state, err := loadExportState(path)
+	require.NoError(t, err)
+	assert.Empty(t, state)
+
+	key := exportStateKey("csv", "https://example.com/", 1)
+	assert.Equal(t, "csv https://example.com 1", key)
+
+	// no previous run exports everything
+	since, err := resolveSince("last run", state, key)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	last := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state[key] = last
+	require.NoError(t, state.save(path))
+
+	state, err = loadExportState(path)
+	require.NoError(t, err)
+	since, err = resolveSince("last run", state, key)
+	require.NoError(t, err)
+	assert.True(t, last.Equal(since))
+
+	since, err = resolveSince("2024-01-02T03:04:05-05:00", state, key)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 8, 4, 5, 0, time.UTC), since.UTC())
+
+	since, err = resolveSince("", state, key)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	_, err = resolveSince("yesterday", state, key)
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/spf13/cobra"
+)
+
+// exportVocabulariesCmd represents the export vocabularies command
+var exportVocabulariesCmd = &cobra.Command{
+	Use:   "vocabularies",
+	Short: "Export a snapshot of vocabulary terms for offline validation",
+	Run: func(cmd *cobra.Command, args []string) {
+		vids, _ := cmd.Flags().GetStringSlice("vid")
+		configDir, _ := cmd.Flags().GetString("config-dir")
+		output, _ := cmd.Flags().GetString("output")
+
+		if baseUrl == "" {
+			slog.Error("--baseUrl flag is required")
+			os.Exit(1)
+		}
+		if len(vids) == 0 && configDir != "" {
+			files, err := filepath.Glob(filepath.Join(configDir, "taxonomy.vocabulary.*.yml"))
+			if err != nil {
+				slog.Error("Unable to read config dir", "dir", configDir, "err", err)
+				os.Exit(1)
+			}
+			for _, file := range files {
+				vids = append(vids, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "taxonomy.vocabulary."), ".yml"))
+			}
+		}
+		if len(vids) == 0 {
+			slog.Error("--vid or --config-dir flag is required")
+			os.Exit(1)
+		}
+
+		snapshot, err := validate.Snapshot(validate.NewSiteVocabularies(baseUrl), vids)
+		if err != nil {
+			slog.Error("Unable to export vocabularies", "err", err)
+			os.Exit(1)
+		}
+
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			slog.Error("Unable to marshal vocabularies", "err", err)
+			os.Exit(1)
+		}
+		err = os.WriteFile(output, data, 0644)
+		if err != nil {
+			slog.Error("Error writing output file", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("Vocabularies exported", "file", output, "vocabularies", len(snapshot))
+	},
+}
+
+func init() {
+	exportCmd.AddCommand(exportVocabulariesCmd)
+
+	exportVocabulariesCmd.Flags().StringSlice("vid", []string{}, "Vocabulary IDs to export")
+	exportVocabulariesCmd.Flags().String("config-dir", "", "Config sync directory to export every vocabulary from")
+	exportVocabulariesCmd.Flags().String("output", "vocabularies.json", "The file to save the snapshot to")
+}
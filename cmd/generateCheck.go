@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// schemaTypes maps a schema name to its fields' machine names and Go types
+type schemaTypes map[string]map[string]string
+
+type FieldDrift struct {
+	Schema string `json:"schema"`
+	Field  string `json:"field"`
+	Type   string `json:"type,omitempty"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type DataModelDrift struct {
+	Added   []FieldDrift `json:"added"`
+	Removed []FieldDrift `json:"removed"`
+	Retyped []FieldDrift `json:"retyped"`
+}
+
+func (d DataModelDrift) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retyped) == 0
+}
+
+// generateCheckCmd represents the generate check command
+var generateCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check a config sync directory for changes not reflected in the generated spec or structs",
+	Long: `Compares the data model in a Drupal config sync directory against a committed
+Open API spec (YAML) or generated Go structs (.go) and reports added, removed, and retyped fields.
+
+Exits non-zero when the data model has drifted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		nodeCexYaml, _ := cmd.Flags().GetString("node-cex-yaml")
+		configDir, _ := cmd.Flags().GetString("config-dir")
+		spec, _ := cmd.Flags().GetString("spec")
+		format, _ := cmd.Flags().GetString("format")
+
+		structData, err := loadStructData(nodeCexYaml, configDir)
+		if err != nil {
+			slog.Error("Error reading config", "err", err)
+			os.Exit(1)
+		}
+
+		var committed schemaTypes
+		if strings.HasSuffix(spec, ".go") {
+			committed, err = goStructTypes(spec)
+		} else {
+			committed, err = specTypes(spec)
+		}
+		if err != nil {
+			slog.Error("Error reading committed data model", "file", spec, "err", err)
+			os.Exit(1)
+		}
+
+		drift := compareDataModel(structDataTypes(structData), committed)
+		switch format {
+		case "json":
+			err = json.NewEncoder(os.Stdout).Encode(drift)
+			if err != nil {
+				slog.Error("Unable to encode drift", "err", err)
+				os.Exit(1)
+			}
+		case "text":
+			for _, f := range drift.Added {
+				fmt.Printf("added\t%s.%s\t%s\n", f.Schema, f.Field, f.Type)
+			}
+			for _, f := range drift.Removed {
+				fmt.Printf("removed\t%s.%s\t%s\n", f.Schema, f.Field, f.Type)
+			}
+			for _, f := range drift.Retyped {
+				fmt.Printf("retyped\t%s.%s\t%s -> %s\n", f.Schema, f.Field, f.From, f.To)
+			}
+		default:
+			slog.Error("Unsupported format", "format", format)
+			os.Exit(1)
+		}
+
+		if !drift.Empty() {
+			slog.Error("Data model has drifted from the generated code", "file", spec)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	generateCmd.AddCommand(generateCheckCmd)
+
+	generateCheckCmd.Flags().String("node-cex-yaml", "", "Path to the node config export YAML file")
+	generateCheckCmd.Flags().String("config-dir", "", "Path to a config sync directory")
+	generateCheckCmd.Flags().String("spec", "./api.yaml", "The committed Open API spec, or generated .go file, to compare against")
+	generateCheckCmd.Flags().String("format", "text", "Output format (text or json)")
+}
+
+func structDataTypes(data StructData) schemaTypes {
+	types := schemaTypes{}
+	// the template always names the primary schema IslandoraObject
+	schemas := append([]StructData{{StructName: "IslandoraObject", DrupalFields: data.DrupalFields}}, data.Schemas...)
+	for _, schema := range schemas {
+		fields := map[string]string{}
+		for _, field := range schema.DrupalFields {
+			fields[field.MachineName] = field.GoType
+		}
+		types[schema.StructName] = fields
+	}
+
+	return types
+}
+
+// specTypes reads the x-go-type of every property in an Open API spec
+func specTypes(file string) (schemaTypes, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					GoType string `yaml:"x-go-type"`
+				} `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	err = yaml.Unmarshal(data, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	types := schemaTypes{}
+	for name, schema := range spec.Components.Schemas {
+		fields := map[string]string{}
+		for property, p := range schema.Properties {
+			fields[property] = p.GoType
+		}
+		types[name] = fields
+	}
+
+	return types, nil
+}
+
+// goStructTypes reads the field types of every struct in a generated Go file
+// keyed by the field's json tag
+func goStructTypes(file string) (schemaTypes, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	types := schemaTypes{}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			fields := map[string]string{}
+			for _, field := range st.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+				name := strings.Split(tag.Get("json"), ",")[0]
+				if name == "" || name == "-" {
+					continue
+				}
+				fields[name] = typeString(field.Type)
+			}
+			types[ts.Name.Name] = fields
+		}
+	}
+
+	return types, nil
+}
+
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return typeString(t.X)
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+// compareDataModel reports the differences between the current config and the committed code
+// only schemas present in both are compared field by field
+func compareDataModel(current, committed schemaTypes) DataModelDrift {
+	drift := DataModelDrift{
+		Added:   []FieldDrift{},
+		Removed: []FieldDrift{},
+		Retyped: []FieldDrift{},
+	}
+
+	for _, schema := range sortedKeys(current) {
+		committedFields, ok := committed[schema]
+		if !ok {
+			for _, field := range sortedKeys(current[schema]) {
+				drift.Added = append(drift.Added, FieldDrift{Schema: schema, Field: field, Type: current[schema][field]})
+			}
+			continue
+		}
+		for _, field := range sortedKeys(current[schema]) {
+			goType := current[schema][field]
+			committedType, ok := committedFields[field]
+			if !ok {
+				drift.Added = append(drift.Added, FieldDrift{Schema: schema, Field: field, Type: goType})
+				continue
+			}
+			if committedType != goType {
+				drift.Retyped = append(drift.Retyped, FieldDrift{Schema: schema, Field: field, From: committedType, To: goType})
+			}
+		}
+	}
+
+	for _, schema := range sortedKeys(committed) {
+		for _, field := range sortedKeys(committed[schema]) {
+			if _, ok := current[schema][field]; !ok {
+				drift.Removed = append(drift.Removed, FieldDrift{Schema: schema, Field: field, Type: committed[schema][field]})
+			}
+		}
+	}
+
+	return drift
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
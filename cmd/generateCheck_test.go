@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareDataModel(t *testing.T) {
+	current := schemaTypes{
+		"IslandoraObject": {
+			"title":           "islandoraModel.GenericField",
+			"field_new":       "islandoraModel.EdtfField",
+			"field_retyped":   "islandoraModel.EdtfField",
+			"field_unchanged": "islandoraModel.TypedTextField",
+		},
+	}
+	committed := schemaTypes{
+		"IslandoraObject": {
+			"title":           "islandoraModel.GenericField",
+			"field_retyped":   "islandoraModel.GenericField",
+			"field_unchanged": "islandoraModel.TypedTextField",
+			"field_gone":      "islandoraModel.BoolField",
+		},
+	}
+
+	drift := compareDataModel(current, committed)
+	assert.False(t, drift.Empty())
+	assert.Equal(t, []FieldDrift{{Schema: "IslandoraObject", Field: "field_new", Type: "islandoraModel.EdtfField"}}, drift.Added)
+	assert.Equal(t, []FieldDrift{{Schema: "IslandoraObject", Field: "field_gone", Type: "islandoraModel.BoolField"}}, drift.Removed)
+	assert.Equal(t, []FieldDrift{{Schema: "IslandoraObject", Field: "field_retyped", From: "islandoraModel.GenericField", To: "islandoraModel.EdtfField"}}, drift.Retyped)
+
+	assert.True(t, compareDataModel(current, current).Empty())
+}
+
+func TestGoStructTypes(t *testing.T) {
+	types, err := goStructTypes("../api/islandora.gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, "islandoraModel.EdtfField", types["IslandoraObject"]["field_edtf_date_issued"])
+	assert.Equal(t, "islandoraModel.IntField", types["IslandoraObject"]["nid"])
+}
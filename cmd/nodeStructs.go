@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/lehigh-university-libraries/go-islandora/templates"
+	"github.com/oapi-codegen/oapi-codegen/v2/pkg/codegen"
+	"github.com/oapi-codegen/oapi-codegen/v2/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 )
@@ -23,6 +26,16 @@ type DrupalField struct {
 	Required       bool
 	OapiProperties map[string]string
 
+	// from the field's field.storage config
+	// a cardinality of -1 is unlimited, 0 means no storage config was found
+	Cardinality   int
+	TargetType    string
+	AllowedValues []string
+	MaxLength     int
+
+	// the bundles an entity reference field can target e.g. vocabularies
+	TargetBundles []string
+
 	// see https://github.com/oapi-codegen/oapi-codegen?tab=readme-ov-file#openapi-extensions
 	GoType     string
 	TypeImport TypeImport
@@ -30,8 +43,14 @@ type DrupalField struct {
 
 type StructData struct {
 	StructName   string
+	EntityType   string
+	Bundle       string
 	DrupalFields []DrupalField
 	CsvColumns   []CsvColumn
+
+	// additional component schemas emitted alongside the main struct
+	// e.g. the paragraph bundles referenced by entity_reference_revisions fields
+	Schemas []StructData
 }
 
 type TypeImport struct {
@@ -44,105 +63,338 @@ var nodeStructsCmd = &cobra.Command{
 	Use:   "node-structs",
 	Short: "Generates Go structs from a node config export YAML",
 	Long: `Generates Go structs from a node config export YAML and associated field definitions,
-used to produce Open API specs and related Go code.`,
+used to produce Open API specs and related Go code.
+
+Passing --config-dir instead generates schemas for every node bundle, media type (Media*),
+taxonomy vocabulary (Term*), and paragraph type (Paragraph*) found in a config sync directory.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		nodeCexYaml, _ := cmd.Flags().GetString("node-cex-yaml")
+		configDir, _ := cmd.Flags().GetString("config-dir")
 		output, _ := cmd.Flags().GetString("output")
+		packageName, _ := cmd.Flags().GetString("package")
+		outDir, _ := cmd.Flags().GetString("out-dir")
 
-		if nodeCexYaml == "" {
-			slog.Error("The --node-cex-yaml flag is required")
+		structData, err := loadStructData(nodeCexYaml, configDir)
+		if err != nil {
+			slog.Error("Error reading config", "err", err)
 			os.Exit(1)
 		}
 
-		dir := filepath.Dir(nodeCexYaml)
-		baseName := filepath.Base(nodeCexYaml)
-		nodeType := strings.TrimSuffix(strings.TrimPrefix(baseName, "node.type."), ".yml")
-
-		pattern := fmt.Sprintf("field.field.node.%s.*", nodeType)
+		structCode, err := generateOapiSpec(structData, "api.yaml.tmpl")
+		if err != nil {
+			slog.Error("Error generating Go struct", "err", err)
+			os.Exit(1)
+		}
 
-		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		err = os.WriteFile(output, []byte(structCode), 0644)
 		if err != nil {
-			slog.Error("Error scanning directory", "err", err)
+			slog.Error("Error writing output file", "err", err)
 			os.Exit(1)
 		}
 
-		if len(files) == 0 {
-			slog.Error("No files found matching pattern", "pattern", pattern)
+		slog.Info("Open API Spec generated and written", "file", output)
+		goFile := filepath.Join(outDir, "islandora.gen.go")
+		err = generateGoCode(output, packageName, goFile)
+		if err != nil {
+			slog.Error("Unable to generate Go code", "err", err)
 			os.Exit(1)
 		}
+		slog.Info("Structs generated", "file", goFile)
+	},
+}
 
-		fields := nodeFields()
+func init() {
+	generateCmd.AddCommand(nodeStructsCmd)
 
-		for _, file := range files {
-			yamlFile, err := os.ReadFile(file)
-			if err != nil {
-				slog.Error("Error reading YAML file", "err", err)
-				os.Exit(1)
-			}
+	nodeStructsCmd.Flags().String("node-cex-yaml", "", "Path to the node config export YAML file")
+	nodeStructsCmd.Flags().String("config-dir", "", "Path to a config sync directory. Generates schemas for every node bundle, media type, and vocabulary")
+	nodeStructsCmd.Flags().String("output", "./api.yaml", "Output file for generated Open API spec")
+	nodeStructsCmd.Flags().String("package", "api", "Go package name for the generated code")
+	nodeStructsCmd.Flags().String("out-dir", "./api", "Directory to write the generated Go code to")
+}
 
-			var data map[string]interface{}
-			err = yaml.Unmarshal(yamlFile, &data)
-			if err != nil {
-				slog.Error("Error unmarshalling YAML", "err", err)
-				os.Exit(1)
-			}
+// loadStructData reads either a whole config sync directory or a single node bundle
+func loadStructData(nodeCexYaml, configDir string) (StructData, error) {
+	switch {
+	case configDir != "":
+		return configDirStructData(configDir)
+	case nodeCexYaml != "":
+		return nodeStructData(nodeCexYaml)
+	}
+
+	return StructData{}, fmt.Errorf("the --node-cex-yaml or --config-dir flag is required")
+}
+
+// nodeStructData builds the spec data for a single node bundle
+// along with any paragraph bundles in the same directory
+func nodeStructData(nodeCexYaml string) (StructData, error) {
+	dir := filepath.Dir(nodeCexYaml)
+	baseName := filepath.Base(nodeCexYaml)
+	nodeType := strings.TrimSuffix(strings.TrimPrefix(baseName, "node.type."), ".yml")
+
+	fields, err := bundleFields(dir, "node", nodeType)
+	if err != nil {
+		return StructData{}, fmt.Errorf("error reading field definitions for %s: %v", nodeType, err)
+	}
+
+	paragraphs, err := paragraphSchemas(dir)
+	if err != nil {
+		return StructData{}, fmt.Errorf("error reading paragraph definitions: %v", err)
+	}
+
+	return StructData{
+		StructName:   toCamelCase(nodeType),
+		EntityType:   "node",
+		Bundle:       nodeType,
+		DrupalFields: append(nodeFields(), fields...),
+		Schemas:      paragraphs,
+	}, nil
+}
+
+// configDirStructData builds the spec data for every node bundle, media type,
+// vocabulary, and paragraph type in a config sync directory.
+// The islandora_object bundle (or the first node bundle found) becomes the IslandoraObject schema
+func configDirStructData(dir string) (StructData, error) {
+	nodes, err := entitySchemas(dir, "node", "node.type", "", nodeFields)
+	if err != nil {
+		return StructData{}, err
+	}
+	if len(nodes) == 0 {
+		return StructData{}, fmt.Errorf("no node.type.*.yml files found in %s", dir)
+	}
 
-			fieldName := data["field_name"].(string)
-			fieldType := data["field_type"].(string)
-			desc := strings.ReplaceAll(data["description"].(string), `"`, `\"`)
-			desc = strings.ReplaceAll(desc, `\\"`, `\"`)
-			fields = append(fields, DrupalField{
-				Name:           toCamelCase(fieldName),
-				OapiProperties: mapFieldTypeToOapiProperties(fieldType),
-				Title:          data["label"].(string),
-				Description:    desc,
-				MachineName:    fieldName,
-				Required:       data["required"].(bool),
-				GoType:         mapFieldTypeToGoType(fieldType),
-				TypeImport: TypeImport{
-					Path: "github.com/lehigh-university-libraries/go-islandora/model",
-					Name: "islandoraModel",
-				},
-			})
+	primary := 0
+	for i, node := range nodes {
+		if node.StructName == "IslandoraObject" {
+			primary = i
+			break
 		}
+	}
+	structData := nodes[primary]
+	structData.Schemas = append(nodes[:primary:primary], nodes[primary+1:]...)
+
+	media, err := entitySchemas(dir, "media", "media.type", "Media", mediaFields)
+	if err != nil {
+		return StructData{}, err
+	}
+	terms, err := entitySchemas(dir, "taxonomy_term", "taxonomy.vocabulary", "Term", termFields)
+	if err != nil {
+		return StructData{}, err
+	}
+	paragraphs, err := paragraphSchemas(dir)
+	if err != nil {
+		return StructData{}, err
+	}
+	structData.Schemas = append(structData.Schemas, media...)
+	structData.Schemas = append(structData.Schemas, terms...)
+	structData.Schemas = append(structData.Schemas, paragraphs...)
+
+	return structData, nil
+}
 
-		structName := toCamelCase(nodeType)
-		structData := StructData{
-			StructName:   structName,
-			DrupalFields: fields,
+// bundleFields reads the field.field.<entityType>.<bundle>.* config files in dir
+func bundleFields(dir, entityType, bundle string) ([]DrupalField, error) {
+	pattern := fmt.Sprintf("field.field.%s.%s.*", entityType, bundle)
+	files, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("error scanning directory: %v", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found matching pattern %s", pattern)
+	}
+
+	fields := []DrupalField{}
+	for _, file := range files {
+		yamlFile, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading YAML file %s: %v", file, err)
 		}
 
-		structCode, err := generateOapiSpec(structData, "api.yaml.tmpl")
+		var data map[string]interface{}
+		err = yaml.Unmarshal(yamlFile, &data)
 		if err != nil {
-			slog.Error("Error generating Go struct", "err", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("error unmarshalling YAML %s: %v", file, err)
 		}
 
-		err = os.WriteFile(output, []byte(structCode), 0644)
+		fieldName := data["field_name"].(string)
+		fieldType := data["field_type"].(string)
+		desc := strings.ReplaceAll(data["description"].(string), `"`, `\"`)
+		desc = strings.ReplaceAll(desc, `\\"`, `\"`)
+		field := DrupalField{
+			Name:           toCamelCase(fieldName),
+			Type:           fieldType,
+			OapiProperties: mapFieldTypeToOapiProperties(fieldType),
+			Title:          data["label"].(string),
+			Description:    desc,
+			MachineName:    fieldName,
+			Required:       data["required"].(bool),
+			GoType:         mapFieldTypeToGoType(fieldType),
+			TypeImport: TypeImport{
+				Path: "github.com/lehigh-university-libraries/go-islandora/model",
+				Name: "islandoraModel",
+			},
+		}
+		field.TargetBundles = targetBundles(data)
+		err = applyFieldStorage(dir, entityType, &field)
 		if err != nil {
-			slog.Error("Error writing output file", "err", err)
-			os.Exit(1)
+			return nil, err
 		}
+		fields = append(fields, field)
+	}
 
-		slog.Info("Open API Spec generated and written", "file", output)
-		genCmd := exec.Command("go", "generate", "./api")
-		if err := genCmd.Run(); err != nil {
-			slog.Error("Unable to run go generate", "err", err)
-			os.Exit(1)
+	return fields, nil
+}
+
+// targetBundles reads settings.handler_settings.target_bundles from a field config
+func targetBundles(data map[string]interface{}) []string {
+	settings, ok := data["settings"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	handlerSettings, ok := settings["handler_settings"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	bundles, ok := handlerSettings["target_bundles"].(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	targets := []string{}
+	for bundle := range bundles {
+		targets = append(targets, fmt.Sprint(bundle))
+	}
+	sort.Strings(targets)
+
+	return targets
+}
+
+// applyFieldStorage reads the field.storage.<entityType>.<field_name>.yml config
+// to constrain the field's schema by cardinality, allowed values, max length, and target type
+func applyFieldStorage(dir, entityType string, field *DrupalField) error {
+	file := filepath.Join(dir, fmt.Sprintf("field.storage.%s.%s.yml", entityType, field.MachineName))
+	yamlFile, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			slog.Warn("No field storage config found", "field", field.MachineName)
+			return nil
 		}
-		slog.Info("Structs generated in api/islandora.gen.go")
-	},
+		return fmt.Errorf("error reading YAML file %s: %v", file, err)
+	}
+
+	var storage struct {
+		Cardinality int `yaml:"cardinality"`
+		Settings    struct {
+			TargetType    string      `yaml:"target_type"`
+			MaxLength     int         `yaml:"max_length"`
+			AllowedValues interface{} `yaml:"allowed_values"`
+		} `yaml:"settings"`
+	}
+	err = yaml.Unmarshal(yamlFile, &storage)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling YAML %s: %v", file, err)
+	}
+
+	field.Cardinality = storage.Cardinality
+	field.TargetType = storage.Settings.TargetType
+	field.MaxLength = storage.Settings.MaxLength
+
+	switch values := storage.Settings.AllowedValues.(type) {
+	// Drupal >= 10.2 exports a list of value/label pairs
+	case []interface{}:
+		for _, v := range values {
+			if m, ok := v.(map[interface{}]interface{}); ok {
+				field.AllowedValues = append(field.AllowedValues, escapeYamlString(fmt.Sprint(m["value"])))
+			}
+		}
+	// older versions export a map of value => label
+	case map[interface{}]interface{}:
+		for k := range values {
+			field.AllowedValues = append(field.AllowedValues, escapeYamlString(fmt.Sprint(k)))
+		}
+		sort.Strings(field.AllowedValues)
+	}
+
+	// config entities are keyed by a string machine name, not an integer ID
+	if field.Type == "entity_reference" && isConfigEntityType(field.TargetType) {
+		field.OapiProperties = mapFieldTypeToOapiProperties("config_reference")
+		field.GoType = mapFieldTypeToGoType("config_reference")
+	}
+
+	return nil
 }
 
-func init() {
-	generateCmd.AddCommand(nodeStructsCmd)
+func isConfigEntityType(entityType string) bool {
+	switch entityType {
+	case "node_type",
+		"media_type",
+		"taxonomy_vocabulary",
+		"paragraphs_type",
+		"comment_type",
+		"block_content_type",
+		"user_role",
+		"filter_format",
+		"image_style",
+		"entity_view_mode",
+		"entity_form_mode",
+		"view",
+		"block",
+		"menu",
+		"contact_form",
+		"webform",
+		"workflow",
+		"date_format",
+		"configurable_language",
+		"search_api_index",
+		"context":
+		return true
+	}
 
-	nodeStructsCmd.Flags().String("node-cex-yaml", "", "Path to the node config export YAML file")
-	nodeStructsCmd.Flags().String("output", "./api.yaml", "Output file for generated Open API spec")
+	return false
+}
+
+func escapeYamlString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// paragraphSchemas builds a schema for every paragraph bundle in the config sync directory
+func paragraphSchemas(dir string) ([]StructData, error) {
+	return entitySchemas(dir, "paragraph", "paragraphs.paragraphs_type", "Paragraph", paragraphFields)
+}
+
+// entitySchemas builds a schema for every bundle of an entity type in the config sync directory
+// bundles are found by their bundle config entity e.g. media.type.*.yml
+func entitySchemas(dir, entityType, bundleConfigPrefix, structPrefix string, base func() []DrupalField) ([]StructData, error) {
+	files, err := filepath.Glob(filepath.Join(dir, bundleConfigPrefix+".*.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("error scanning directory: %v", err)
+	}
+
+	schemas := []StructData{}
+	for _, file := range files {
+		bundle := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), bundleConfigPrefix+"."), ".yml")
+		fields, err := bundleFields(dir, entityType, bundle)
+		if err != nil {
+			// a bundle without any fields is valid
+			slog.Warn("No fields found for bundle", "entityType", entityType, "bundle", bundle, "err", err)
+			fields = []DrupalField{}
+		}
+		schemas = append(schemas, StructData{
+			StructName:   structPrefix + toCamelCase(bundle),
+			EntityType:   entityType,
+			Bundle:       bundle,
+			DrupalFields: append(base(), fields...),
+		})
+	}
+
+	return schemas, nil
 }
 
+// generateOapiSpec renders one of the embedded spec templates
 func generateOapiSpec(data StructData, tmplFile string) (string, error) {
-	tmpl, err := template.ParseFiles(tmplFile)
+	tmpl, err := template.ParseFS(templates.FS, tmplFile)
 	if err != nil {
 		return "", err
 	}
@@ -156,6 +408,42 @@ func generateOapiSpec(data StructData, tmplFile string) (string, error) {
 	return buf.String(), nil
 }
 
+// generateGoCode runs oapi-codegen against an Open API spec
+// writing the models and std http server interface to goFile
+func generateGoCode(specFile, packageName, goFile string) error {
+	spec, err := util.LoadSwagger(specFile)
+	if err != nil {
+		return fmt.Errorf("unable to load spec %s: %v", specFile, err)
+	}
+
+	cfg := codegen.Configuration{
+		PackageName: packageName,
+		Generate: codegen.GenerateOptions{
+			StdHTTPServer: true,
+			Models:        true,
+		},
+		// keep the media, term, and paragraph schemas the /upload path doesn't reference
+		OutputOptions: codegen.OutputOptions{
+			SkipPrune: true,
+		},
+	}.UpdateDefaults()
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid codegen configuration: %v", err)
+	}
+
+	code, err := codegen.Generate(spec, cfg)
+	if err != nil {
+		return fmt.Errorf("unable to generate code: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(goFile), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(goFile, []byte(code), 0644)
+}
+
 func mapFieldTypeToOapiProperties(fieldType string) map[string]string {
 	properties := map[string]string{}
 	switch fieldType {
@@ -163,6 +451,7 @@ func mapFieldTypeToOapiProperties(fieldType string) map[string]string {
 		properties["value"] = "boolean"
 	case "entity_reference":
 		properties["target_id"] = "integer"
+		properties["target_type"] = "string"
 	case "integer":
 		properties["value"] = "integer"
 	case "geolocation":
@@ -202,6 +491,49 @@ func mapFieldTypeToOapiProperties(fieldType string) map[string]string {
 		properties["title"] = "string"
 	case "config_reference":
 		properties["target_id"] = "string"
+		properties["target_type"] = "string"
+	case "entity_reference_revisions":
+		properties["target_id"] = "integer"
+		properties["target_revision_id"] = "integer"
+	case "link":
+		properties["uri"] = "string"
+		properties["title"] = "string"
+	case "datetime":
+		properties["value"] = "string"
+	case "daterange":
+		properties["value"] = "string"
+		properties["end_value"] = "string"
+	case "timestamp", "created", "changed":
+		properties["value"] = "string"
+		properties["format"] = "string"
+	case "decimal":
+		properties["value"] = "string"
+	case "float", "list_float":
+		properties["value"] = "number"
+	case "list_integer":
+		properties["value"] = "integer"
+	case "list_string":
+		properties["value"] = "string"
+	case "text_with_summary":
+		properties["value"] = "string"
+		properties["format"] = "string"
+		properties["summary"] = "string"
+	case "file":
+		properties["target_id"] = "integer"
+		properties["display"] = "boolean"
+		properties["description"] = "string"
+		properties["url"] = "string"
+	case "image":
+		properties["target_id"] = "integer"
+		properties["alt"] = "string"
+		properties["title"] = "string"
+		properties["width"] = "integer"
+		properties["height"] = "integer"
+		properties["url"] = "string"
+	case "path":
+		properties["alias"] = "string"
+		properties["pid"] = "integer"
+		properties["langcode"] = "string"
 	default:
 		properties["value"] = "string"
 	}
@@ -219,7 +551,7 @@ func mapFieldTypeToGoType(fieldType string) string {
 		return "islandoraModel.EdtfField"
 	case "email":
 		return "islandoraModel.EmailField"
-	case "integer":
+	case "integer", "list_integer":
 		return "islandoraModel.IntField"
 	case "geolocation":
 		return "islandoraModel.GeoLocationField"
@@ -235,6 +567,30 @@ func mapFieldTypeToGoType(fieldType string) string {
 		return "islandoraModel.PartDetailField"
 	case "config_reference":
 		return "islandoraModel.ConfigReferenceField"
+	case "entity_reference_revisions":
+		return "islandoraModel.EntityReferenceRevisionsField"
+	case "link":
+		return "islandoraModel.LinkField"
+	case "datetime":
+		return "islandoraModel.DateTimeField"
+	case "daterange":
+		return "islandoraModel.DateRangeField"
+	case "timestamp", "created", "changed":
+		return "islandoraModel.TimestampField"
+	case "decimal":
+		return "islandoraModel.DecimalField"
+	case "float", "list_float":
+		return "islandoraModel.FloatField"
+	case "list_string":
+		return "islandoraModel.ListStringField"
+	case "text_with_summary":
+		return "islandoraModel.TextWithSummaryField"
+	case "file":
+		return "islandoraModel.FileField"
+	case "image":
+		return "islandoraModel.ImageField"
+	case "path":
+		return "islandoraModel.PathField"
 	default:
 		return "islandoraModel.GenericField"
 	}
@@ -258,8 +614,7 @@ func toCamelCase(input string) string {
 
 // some base properties for the node entity
 func nodeFields() []DrupalField {
-	fields := []DrupalField{}
-	f := map[string]string{
+	return baseFields(map[string]string{
 		"nid":                "integer",
 		"vid":                "integer",
 		"uuid":               "string",
@@ -274,10 +629,73 @@ func nodeFields() []DrupalField {
 		"created":            "string",
 		"changed":            "string",
 		"url":                "string",
+	})
+}
+
+// some base properties for the media entity
+func mediaFields() []DrupalField {
+	return baseFields(map[string]string{
+		"mid":       "integer",
+		"uuid":      "string",
+		"vid":       "integer",
+		"langcode":  "string",
+		"bundle":    "config_reference",
+		"name":      "string",
+		"thumbnail": "image",
+		"uid":       "entity_reference",
+		"status":    "boolean",
+		"created":   "string",
+		"changed":   "string",
+	})
+}
+
+// some base properties for the taxonomy term entity
+func termFields() []DrupalField {
+	return baseFields(map[string]string{
+		"tid":         "integer",
+		"uuid":        "string",
+		"revision_id": "integer",
+		"langcode":    "string",
+		"vid":         "config_reference",
+		"name":        "string",
+		"description": "string",
+		"weight":      "integer",
+		"parent":      "entity_reference",
+		"status":      "boolean",
+		"changed":     "string",
+	})
+}
+
+// some base properties for the paragraph entity
+func paragraphFields() []DrupalField {
+	return baseFields(map[string]string{
+		"id":                "integer",
+		"uuid":              "string",
+		"revision_id":       "integer",
+		"langcode":          "string",
+		"type":              "config_reference",
+		"status":            "boolean",
+		"created":           "string",
+		"parent_id":         "string",
+		"parent_type":       "string",
+		"parent_field_name": "string",
+	})
+}
+
+func baseFields(f map[string]string) []DrupalField {
+	// sort the field names so generated specs are deterministic
+	fieldNames := make([]string, 0, len(f))
+	for fieldName := range f {
+		fieldNames = append(fieldNames, fieldName)
 	}
-	for fieldName, fieldType := range f {
+	sort.Strings(fieldNames)
+
+	fields := []DrupalField{}
+	for _, fieldName := range fieldNames {
+		fieldType := f[fieldName]
 		fields = append(fields, DrupalField{
 			Name:           toCamelCase(fieldName),
+			Type:           fieldType,
 			OapiProperties: mapFieldTypeToOapiProperties(fieldType),
 			Title:          toCamelCase(fieldName),
 			Description:    "",
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const fixtureConfigDir = "../fixtures/config/sync"
+
+func TestBundleFieldsStorage(t *testing.T) {
+	fields, err := bundleFields(fixtureConfigDir, "node", "islandora_object")
+	require.NoError(t, err)
+
+	byName := map[string]DrupalField{}
+	for _, f := range fields {
+		byName[f.MachineName] = f
+	}
+
+	assert.Equal(t, -1, byName["field_genre"].Cardinality)
+	assert.Equal(t, "taxonomy_term", byName["field_genre"].TargetType)
+	assert.Equal(t, "islandoraModel.EntityReferenceField", byName["field_genre"].GoType)
+
+	assert.Equal(t, 1, byName["field_model"].Cardinality)
+	assert.True(t, byName["field_model"].Required)
+
+	assert.Equal(t, []string{"public", "restricted"}, byName["field_rights_type"].AllowedValues)
+	assert.Equal(t, 255, byName["field_alt_title"].MaxLength)
+
+	assert.Equal(t, "islandoraModel.ConfigReferenceField", byName["field_view_mode"].GoType)
+	assert.Equal(t, "string", byName["field_view_mode"].OapiProperties["target_id"])
+}
+
+func TestGenerateOapiSpecConstraints(t *testing.T) {
+	fields, err := bundleFields(fixtureConfigDir, "node", "islandora_object")
+	require.NoError(t, err)
+	paragraphs, err := paragraphSchemas(fixtureConfigDir)
+	require.NoError(t, err)
+
+	spec, err := generateOapiSpec(StructData{
+		StructName:   "IslandoraObject",
+		DrupalFields: append(nodeFields(), fields...),
+		Schemas:      paragraphs,
+	}, "api.yaml.tmpl")
+	require.NoError(t, err)
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					MaxItems int `yaml:"maxItems"`
+					MinItems int `yaml:"minItems"`
+					Items    struct {
+						Properties map[string]struct {
+							Enum      []string `yaml:"enum"`
+							MaxLength int      `yaml:"maxLength"`
+						} `yaml:"properties"`
+					} `yaml:"items"`
+				} `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(spec), &doc))
+
+	obj := doc.Components.Schemas["IslandoraObject"].Properties
+	assert.Equal(t, 1, obj["field_model"].MaxItems)
+	assert.Equal(t, 1, obj["field_model"].MinItems)
+	assert.Equal(t, 0, obj["field_genre"].MaxItems)
+	assert.Equal(t, []string{"public", "restricted"}, obj["field_rights_type"].Items.Properties["value"].Enum)
+	assert.Equal(t, 255, obj["field_alt_title"].Items.Properties["value"].MaxLength)
+	assert.Equal(t, []string{"taxonomy_term"}, obj["field_genre"].Items.Properties["target_type"].Enum)
+
+	note := doc.Components.Schemas["ParagraphNote"].Properties
+	assert.Contains(t, note, "field_note_text")
+	assert.Equal(t, []string{"general", "provenance"}, note["field_note_type"].Items.Properties["value"].Enum)
+}
+
+func TestGenerateOapiSpecNumericEnum(t *testing.T) {
+	spec, err := generateOapiSpec(StructData{
+		StructName: "IslandoraObject",
+		DrupalFields: []DrupalField{
+			{MachineName: "field_rating", Type: "list_integer", OapiProperties: mapFieldTypeToOapiProperties("list_integer"), AllowedValues: []string{"1", "2"}},
+			{MachineName: "field_scale", Type: "list_float", OapiProperties: mapFieldTypeToOapiProperties("list_float"), AllowedValues: []string{"0.5", "1.5"}},
+		},
+	}, "api.yaml.tmpl")
+	require.NoError(t, err)
+
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Items struct {
+						Properties map[string]struct {
+							Type string        `yaml:"type"`
+							Enum []interface{} `yaml:"enum"`
+						} `yaml:"properties"`
+					} `yaml:"items"`
+				} `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(spec), &doc))
+
+	obj := doc.Components.Schemas["IslandoraObject"].Properties
+	assert.Equal(t, "integer", obj["field_rating"].Items.Properties["value"].Type)
+	assert.Equal(t, []interface{}{1, 2}, obj["field_rating"].Items.Properties["value"].Enum)
+	assert.Equal(t, []interface{}{0.5, 1.5}, obj["field_scale"].Items.Properties["value"].Enum)
+}
+
+func TestConfigDirStructData(t *testing.T) {
+	structData, err := configDirStructData(fixtureConfigDir)
+	require.NoError(t, err)
+	assert.Equal(t, "IslandoraObject", structData.StructName)
+
+	schemas := map[string]StructData{}
+	for _, s := range structData.Schemas {
+		schemas[s.StructName] = s
+	}
+	assert.Contains(t, schemas, "Page")
+	assert.Contains(t, schemas, "MediaDocument")
+	assert.Contains(t, schemas, "TermGenre")
+	assert.Contains(t, schemas, "TermPerson")
+	assert.Contains(t, schemas, "ParagraphNote")
+	assert.NotContains(t, schemas, "IslandoraObject")
+
+	fields := map[string]DrupalField{}
+	for _, f := range schemas["MediaDocument"].DrupalFields {
+		fields[f.MachineName] = f
+	}
+	assert.Equal(t, "islandoraModel.FileField", fields["field_media_document"].GoType)
+	assert.Equal(t, "islandoraModel.IntField", fields["mid"].GoType)
+}
+
+func TestConfigFieldVocabularies(t *testing.T) {
+	vocabularies, err := configFieldVocabularies(fixtureConfigDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"genre"}, vocabularies["field_genre"])
+	assert.Equal(t, []string{"islandora_models"}, vocabularies["field_model"])
+	assert.NotContains(t, vocabularies, "field_view_mode")
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+// preflightCmd represents the preflight command
+var preflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Check a CSV against the site before a big ingest",
+	Long: `Validate a Google Sheets or Workbench CSV (see validate csv) and check it
+against the Islandora site it will be ingested into:
+
+  - every Parent Collection/field_member_of node exists and is a collection
+  - every Node ID being updated exists
+  - files exist locally and their content matches their extension,
+    the File Format (MIME Type) column, or the Object Model
+  - Upload IDs are unique
+
+The storage the ingest needs is estimated from the size of the files.
+
+Exits non-zero when there are problems so it can gate the ingest.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		csvPath, _ := cmd.Flags().GetString("csv")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		filesDir, _ := cmd.Flags().GetString("files-dir")
+		collectionModels, _ := cmd.Flags().GetStringSlice("collection-models")
+		derivativeFactor, _ := cmd.Flags().GetFloat64("derivative-factor")
+		format, _ := cmd.Flags().GetString("format")
+
+		if csvPath == "" || baseUrl == "" {
+			slog.Error("--csv and --baseUrl flags are required")
+			os.Exit(1)
+		}
+		mapping, err := workbench.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+		if filesDir == "" {
+			filesDir = filepath.Dir(csvPath)
+		}
+
+		f, err := os.Open(csvPath)
+		if err != nil {
+			slog.Error("Unable to open CSV", "csv", csvPath, "err", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		vocabularies, fieldVocabularies, err := vocabularyOptions(cmd)
+		if err != nil {
+			slog.Error("Unable to load vocabularies", "err", err)
+			os.Exit(1)
+		}
+
+		report, err := validate.Preflight(f, validate.PreflightOptions{
+			Options: validate.Options{
+				Mapping:           mapping,
+				FilesDir:          filesDir,
+				Vocabularies:      vocabularies,
+				FieldVocabularies: fieldVocabularies,
+			},
+			Site:             validate.IslandoraSite{BaseUrl: strings.TrimSuffix(baseUrl, "/")},
+			CollectionModels: collectionModels,
+			DerivativeFactor: derivativeFactor,
+		})
+		if err != nil {
+			slog.Error("Unable to check CSV", "csv", csvPath, "err", err)
+			os.Exit(1)
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(report)
+		} else {
+			err = printValidationReport(&report.ValidationReport, format)
+			if err == nil {
+				fmt.Printf("%d files, %s (estimated %s with derivatives)\n", report.Files, byteSize(report.FileBytes), byteSize(report.EstimatedBytes))
+			}
+		}
+		if err != nil {
+			slog.Error("Unable to print report", "err", err)
+			os.Exit(1)
+		}
+		if !report.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(preflightCmd)
+
+	preflightCmd.Flags().String("csv", "", "Path to the CSV to check")
+	preflightCmd.Flags().StringVar(&baseUrl, "baseUrl", "", "The Islandora site being ingested into (e.g. https://google.com)")
+	preflightCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+	preflightCmd.Flags().String("files-dir", "", "Directory relative file paths are resolved against (default: the CSV's directory)")
+	preflightCmd.Flags().StringSlice("collection-models", []string{"Collection"}, "Models a Parent Collection may have")
+	preflightCmd.Flags().Float64("derivative-factor", 1, "Multiplier applied to the size of the files to estimate storage including derivatives")
+	preflightCmd.Flags().String("format", "text", "Report format (text or json)")
+	addVocabularyFlags(preflightCmd)
+}
+
+// byteSize formats a number of bytes for people e.g. 1.5 GiB
+func byteSize(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+type serveConfig struct {
+	ApiPrefix       string
+	WorkbenchPrefix string
+	MaxBodyBytes    int64
+	// basic auth is enabled when both are set
+	Username string
+	Password string
+
+	Validator api.Validator
+	Mapping   *workbench.Mapping
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the CSV validation and transformation APIs",
+	Long: `Serve the CSV validation (api) and Google Sheets to Workbench
+transformation (workbench) APIs over HTTP.
+
+POST {api-prefix}/upload        validates a CSV and responds with a JSON report
+POST {workbench-prefix}/upload  transforms a Google Sheets CSV into a Workbench CSV
+GET  /healthz                   liveness
+GET  /readyz                    readiness
+
+Basic auth is required on the APIs when GO_ISLANDORA_SERVE_USERNAME
+and GO_ISLANDORA_SERVE_PASSWORD are set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		apiPrefix, _ := cmd.Flags().GetString("api-prefix")
+		workbenchPrefix, _ := cmd.Flags().GetString("workbench-prefix")
+		maxBody, _ := cmd.Flags().GetInt64("max-body-bytes")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		filesDir, _ := cmd.Flags().GetString("files-dir")
+		skipFiles, _ := cmd.Flags().GetBool("skip-files")
+
+		if strings.TrimSuffix(apiPrefix, "/") == strings.TrimSuffix(workbenchPrefix, "/") {
+			slog.Error("--api-prefix and --workbench-prefix must be different")
+			os.Exit(1)
+		}
+
+		mapping, err := workbench.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		vocabularies, fieldVocabularies, err := vocabularyOptions(cmd)
+		if err != nil {
+			slog.Error("Unable to load vocabularies", "err", err)
+			os.Exit(1)
+		}
+
+		cfg := serveConfig{
+			ApiPrefix:       apiPrefix,
+			WorkbenchPrefix: workbenchPrefix,
+			MaxBodyBytes:    maxBody,
+			Username:        os.Getenv("GO_ISLANDORA_SERVE_USERNAME"),
+			Password:        os.Getenv("GO_ISLANDORA_SERVE_PASSWORD"),
+			Mapping:         mapping,
+			Validator: validate.New(validate.Options{
+				Mapping:           mapping,
+				FilesDir:          filesDir,
+				SkipFiles:         skipFiles,
+				Vocabularies:      vocabularies,
+				FieldVocabularies: fieldVocabularies,
+			}),
+		}
+		if cfg.Username == "" || cfg.Password == "" {
+			slog.Warn("Basic auth is disabled")
+		}
+
+		var ready atomic.Bool
+		slog.Info("Serving", "addr", addr, "api", apiPrefix, "workbench", workbenchPrefix)
+		err = listenAndServe(addr, newServeHandler(cfg, &ready), shutdownTimeout, &ready)
+		if err != nil {
+			slog.Error("Server failed", "addr", addr, "err", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveCmd.Flags().String("api-prefix", "/api", "Path prefix for the validation API")
+	serveCmd.Flags().String("workbench-prefix", "/workbench", "Path prefix for the transformation API")
+	serveCmd.Flags().Int64("max-body-bytes", 32<<20, "Maximum request body size in bytes")
+	serveCmd.Flags().Duration("shutdown-timeout", 30*time.Second, "How long to wait for in flight requests when shutting down")
+	serveCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+	serveCmd.Flags().String("files-dir", "", "Directory relative file paths in uploaded CSVs are resolved against")
+	serveCmd.Flags().Bool("skip-files", false, "Do not check that files in uploaded CSVs exist")
+	addVocabularyFlags(serveCmd)
+}
+
+func newServeHandler(cfg serveConfig, ready *atomic.Bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	apis := http.NewServeMux()
+	api.HandlerWithOptions(api.NewValidationServer(cfg.Validator), api.StdHTTPServerOptions{
+		BaseURL:    strings.TrimSuffix(cfg.ApiPrefix, "/"),
+		BaseRouter: apis,
+	})
+	workbench.HandlerWithOptions(workbench.Server{Mapping: cfg.Mapping}, workbench.StdHTTPServerOptions{
+		BaseURL:    strings.TrimSuffix(cfg.WorkbenchPrefix, "/"),
+		BaseRouter: apis,
+	})
+	mux.Handle("/", basicAuth(cfg.Username, cfg.Password, limitBody(cfg.MaxBodyBytes, apis)))
+
+	return accessLog(mux)
+}
+
+func limitBody(max int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if max > 0 {
+			if r.ContentLength > max {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func basicAuth(username, password string, next http.Handler) http.Handler {
+	if username == "" || password == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(u), []byte(username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(p), []byte(password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="go-islandora"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		user, _, _ := r.BasicAuth()
+		slog.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+			"user", user,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// listenAndServe serves until SIGINT or SIGTERM, then shuts down gracefully.
+// ready is true while the server is accepting requests.
+func listenAndServe(addr string, handler http.Handler, shutdownTimeout time.Duration, ready *atomic.Bool) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down", "timeout", shutdownTimeout)
+		ready.Store(false)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			slog.Error("Unable to shut down cleanly", "err", err)
+		}
+	}()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	ready.Store(true)
+	err = server.Serve(ln)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/oai"
+	"github.com/spf13/cobra"
+)
+
+// serveOaiCmd represents the serve oai command
+var serveOaiCmd = &cobra.Command{
+	Use:   "oai",
+	Short: "Serve an OAI-PMH 2.0 data provider",
+	Long: `Serve an OAI-PMH 2.0 data provider for Islandora nodes.
+
+Nodes are either fetched from --baseUrl (each --nid and its descendants)
+or read from --nodes, a JSON file or a directory of saved node JSON.
+Taxonomy terms are always looked up on --baseUrl, so it is required with
+--nodes too.
+Records are disseminated as oai_dc and mods, collections are served as
+sets and a node's changed date is its datestamp. Unpublished nodes are
+not served.
+
+With --refresh the nodes are periodically fetched again, re-fetching only
+the nodes that changed since the last load.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		path, _ := cmd.Flags().GetString("path")
+		nids, _ := cmd.Flags().GetIntSlice("nid")
+		nodesPath, _ := cmd.Flags().GetString("nodes")
+		publicUrl, _ := cmd.Flags().GetString("public-url")
+		repositoryName, _ := cmd.Flags().GetString("repository-name")
+		repositoryId, _ := cmd.Flags().GetString("repository-identifier")
+		adminEmails, _ := cmd.Flags().GetStringSlice("admin-email")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+		setModels, _ := cmd.Flags().GetStringSlice("set-models")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		refresh, _ := cmd.Flags().GetDuration("refresh")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+
+		if baseUrl == "" || (nodesPath == "") == (len(nids) == 0) {
+			slog.Error("--baseUrl and either --nodes or --nid are required")
+			os.Exit(1)
+		}
+		baseUrl = strings.TrimSuffix(baseUrl, "/")
+		if publicUrl == "" {
+			publicUrl = "http://localhost" + addr + path
+			if !strings.HasPrefix(addr, ":") {
+				publicUrl = "http://" + addr + path
+			}
+		}
+		if repositoryId == "" {
+			u, err := url.Parse(baseUrl)
+			if err != nil || u.Hostname() == "" {
+				slog.Error("Unable to determine the repository identifier, set --repository-identifier", "url", baseUrl)
+				os.Exit(1)
+			}
+			repositoryId = u.Hostname()
+		}
+
+		mapping, err := mods.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading MODS mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+		modsOpts := mods.Options{
+			Mapping: mapping,
+			Terms:   islandora.SiteTerms{BaseUrl: baseUrl},
+			BaseUrl: baseUrl,
+		}
+
+		provider := oai.NewProvider(oai.Options{
+			RepositoryName:       repositoryName,
+			BaseURL:              publicUrl,
+			AdminEmails:          adminEmails,
+			RepositoryIdentifier: repositoryId,
+			PageSize:             pageSize,
+			Mods:                 modsOpts,
+			SetModels:            setModels,
+		})
+
+		loaded := time.Now()
+		nodes, err := loadOaiNodes(nodesPath, nids, time.Time{})
+		if err == nil {
+			err = provider.SetNodes(nodes)
+		}
+		if err != nil {
+			slog.Error("Unable to load nodes", "err", err)
+			os.Exit(1)
+		}
+		slog.Info("Loaded nodes", "count", len(nodes))
+
+		if refresh > 0 {
+			go func() {
+				for range time.Tick(refresh) {
+					start := time.Now()
+					nodes, err := loadOaiNodes(nodesPath, nids, loaded)
+					if err == nil {
+						err = provider.SetNodes(nodes)
+					}
+					if err != nil {
+						slog.Error("Unable to refresh nodes, still serving the previous nodes", "err", err)
+						continue
+					}
+					loaded = start
+					slog.Info("Refreshed nodes", "count", len(nodes))
+				}
+			}()
+		}
+
+		var ready atomic.Bool
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		})
+		mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+			if !ready.Load() {
+				http.Error(w, "not ready", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		})
+		mux.Handle(path, provider)
+
+		slog.Info("Serving OAI-PMH", "addr", addr, "path", path, "baseURL", publicUrl)
+		err = listenAndServe(addr, accessLog(mux), shutdownTimeout, &ready)
+		if err != nil {
+			slog.Error("Server failed", "addr", addr, "err", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.AddCommand(serveOaiCmd)
+
+	serveOaiCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveOaiCmd.Flags().String("path", "/oai", "Path the OAI-PMH endpoint is served on")
+	serveOaiCmd.Flags().StringVar(&baseUrl, "baseUrl", "", "The Islandora site to fetch terms, and nodes without --nodes, from (e.g. https://google.com)")
+	serveOaiCmd.Flags().IntSlice("nid", []int{}, "Node IDs to serve along with their descendants, can be repeated")
+	serveOaiCmd.Flags().String("nodes", "", "JSON file, or directory of JSON files, of saved nodes to serve instead of fetching from --baseUrl")
+	serveOaiCmd.Flags().String("public-url", "", "The public URL of the endpoint reported to harvesters (default: http://localhost{addr}{path})")
+	serveOaiCmd.Flags().String("repository-name", "Islandora", "The repository name reported by Identify")
+	serveOaiCmd.Flags().String("repository-identifier", "", "Namespace of record identifiers e.g. oai:{identifier}:node/1 (default: the host of --baseUrl)")
+	serveOaiCmd.Flags().StringSlice("admin-email", []string{}, "Administrator email reported by Identify, can be repeated")
+	serveOaiCmd.Flags().Int("page-size", 100, "Headers or records per response before a resumption token")
+	serveOaiCmd.Flags().StringSlice("set-models", []string{"Collection"}, "Models of the nodes served as sets")
+	serveOaiCmd.Flags().String("mapping", "", "YAML file mapping node fields to MODS elements (default: the built-in mapping)")
+	serveOaiCmd.Flags().Duration("refresh", 0, "How often to reload the nodes, 0 to never reload")
+	serveOaiCmd.Flags().Duration("shutdown-timeout", 30*time.Second, "How long to wait for in flight requests when shutting down")
+}
+
+// loadOaiNodes reads the saved nodes, or fetches each nid's tree.
+// When since is set only nodes changed since then are fetched fresh.
+func loadOaiNodes(nodesPath string, nids []int, since time.Time) ([]*api.IslandoraObject, error) {
+	if nodesPath != "" {
+		return islandora.ReadNodes(nodesPath)
+	}
+
+	nodes := []*api.IslandoraObject{}
+	for _, nid := range nids {
+		var tree []*api.IslandoraObject
+		var err error
+		if since.IsZero() {
+			tree, err = islandora.FetchNodes(baseUrl, nid)
+		} else {
+			tree, _, err = islandora.FetchNodesChanged(baseUrl, nid, since)
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, tree...)
+	}
+
+	return nodes, nil
+}
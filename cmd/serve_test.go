@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeHandler(t *testing.T) {
+	var ready atomic.Bool
+	cfg := serveConfig{
+		ApiPrefix:       "/api",
+		WorkbenchPrefix: "/workbench/",
+		MaxBodyBytes:    64,
+		Username:        "user",
+		Password:        "pass",
+		Mapping:         workbench.DefaultMapping(),
+		Validator:       validate.New(validate.Options{SkipFiles: true}),
+	}
+	handler := newServeHandler(cfg, &ready)
+
+	request := func(method, path, body string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if auth {
+			r.SetBasicAuth("user", "pass")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/healthz", "", false).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodGet, "/readyz", "", false).Code)
+	ready.Store(true)
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/readyz", "", false).Code)
+
+	csv := "Upload ID,Title,Object Model\n1,Foo,Image\n"
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodPost, "/api/upload", csv, false).Code)
+
+	w := request(http.MethodPost, "/api/upload", csv, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid":true`)
+
+	w = request(http.MethodPost, "/workbench/upload", csv, true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,title,field_model\n1,Foo,Image\n", w.Body.String())
+
+	w = request(http.MethodPost, "/workbench/upload", csv+strings.Repeat("2,Foo,Image\n", 10), true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// a chunked body has no Content-Length, so the limit is only hit while reading it
+	for _, path := range []string{"/api/upload", "/workbench/upload"} {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(csv+strings.Repeat("2,Foo,Image\n", 10)))
+		r.ContentLength = -1
+		r.SetBasicAuth("user", "pass")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, path)
+	}
+}
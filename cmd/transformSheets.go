@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+// transformSheetsCmd represents the transform sheets command
+var transformSheetsCmd = &cobra.Command{
+	Use:   "sheets",
+	Short: "Transform a Workbench CSV or node JSON into the Google Sheets template",
+	Long: `Transform a Workbench CSV, or a JSON file of exported nodes, into the
+Google Sheets template so catalogers can bulk edit existing items.
+
+Values that do not fit any column in the template are kept in the
+"` + workbench.OverflowColumn + `" column.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		keepIds, _ := cmd.Flags().GetStringSlice("keep-ids")
+		mapping, err := workbench.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		if source == "" {
+			slog.Error("source flag is required")
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(source)
+		if err != nil {
+			slog.Error("Unable to read source file", "source", source, "err", err)
+			os.Exit(1)
+		}
+
+		var labeler workbench.Labeler
+		if baseUrl != "" {
+			labeler = termLabeler(baseUrl)
+		}
+
+		var records [][]string
+		if strings.HasSuffix(source, ".json") {
+			records, err = nodeJsonRecords(data, workbench.NodeOptions{Labeler: labeler})
+		} else {
+			records, err = csv.NewReader(bytes.NewReader(data)).ReadAll()
+		}
+		if err != nil {
+			slog.Error("Unable to read source file", "source", source, "err", err)
+			os.Exit(1)
+		}
+
+		var out io.Writer = os.Stdout
+		if target != "" {
+			f, err := os.Create(target)
+			if err != nil {
+				slog.Error("Unable to create target file", "target", target, "err", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		opts := workbench.ReverseOptions{
+			Labeler: labeler,
+			KeepIDs: keepIds,
+		}
+		records, err = workbench.ReverseRecords(records, mapping, opts)
+		if err != nil {
+			slog.Error("Unable to transform CSV", "source", source, "err", err)
+			os.Exit(1)
+		}
+
+		err = csv.NewWriter(out).WriteAll(records)
+		if err != nil {
+			slog.Error("Unable to write CSV", "err", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	transformCmd.AddCommand(transformSheetsCmd)
+	transformSheetsCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+	transformSheetsCmd.Flags().StringVar(&baseUrl, "baseUrl", "", "The Islandora site to look up term labels from (e.g. https://google.com)")
+	transformSheetsCmd.Flags().StringSlice("keep-ids", []string{"field_member_of"}, "Entity reference fields to leave as IDs")
+}
+
+// nodeJsonRecords converts a JSON node, or an array of nodes, into workbench CSV rows
+func nodeJsonRecords(data []byte, opts workbench.NodeOptions) ([][]string, error) {
+	nodes, err := islandora.ParseNodes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return workbench.NodeRecords(nodes, opts)
+}
+
+func termLabeler(baseUrl string) workbench.Labeler {
+	return func(field string, id int) (string, error) {
+		url := fmt.Sprintf("%s/taxonomy/term/%d?_format=json", baseUrl, id)
+		term, err := islandora.FetchTerm(url)
+		if err != nil {
+			return "", err
+		}
+		if len(term.Name) == 0 {
+			return "", fmt.Errorf("term %d has no name", id)
+		}
+		if len(term.Vid) == 0 {
+			return term.Name[0].Value, nil
+		}
+
+		return term.Vid[0].TargetId + ":" + term.Name[0].Value, nil
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+// transformWorkbenchCmd represents the transform workbench command
+var transformWorkbenchCmd = &cobra.Command{
+	Use:   "workbench",
+	Short: "Transform a Google Sheets CSV into an Islandora Workbench CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		mapping, err := workbench.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+
+		if source == "" {
+			slog.Error("source flag is required")
+			os.Exit(1)
+		}
+		in, err := os.Open(source)
+		if err != nil {
+			slog.Error("Unable to open source file", "source", source, "err", err)
+			os.Exit(1)
+		}
+		defer in.Close()
+
+		var out io.Writer = os.Stdout
+		if target != "" {
+			f, err := os.Create(target)
+			if err != nil {
+				slog.Error("Unable to create target file", "target", target, "err", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		err = workbench.Transform(in, out, mapping)
+		if err != nil {
+			slog.Error("Unable to transform CSV", "source", source, "err", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	transformCmd.AddCommand(transformWorkbenchCmd)
+	transformWorkbenchCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/bagit"
+	"github.com/spf13/cobra"
+)
+
+// validateBagCmd represents the validate bag command
+var validateBagCmd = &cobra.Command{
+	Use:   "bag",
+	Short: "Validate BagIt bags",
+	Long: `Validate BagIt bags, checking the bag declaration, that every payload
+file is listed in every manifest, that every file in the payload and tag
+manifests exists with a matching checksum, and the Payload-Oxum.
+
+Exits non-zero when a bag is invalid.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		bags, _ := cmd.Flags().GetStringArray("bag")
+		if len(bags) == 0 {
+			slog.Error("--bag flag is required")
+			os.Exit(1)
+		}
+
+		valid := true
+		for _, dir := range bags {
+			problems, err := bagit.Validate(dir)
+			if err != nil {
+				slog.Error("Unable to read bag", "bag", dir, "err", err)
+				os.Exit(1)
+			}
+			if len(problems) == 0 {
+				fmt.Printf("%s is valid\n", dir)
+				continue
+			}
+			valid = false
+			fmt.Printf("%s is invalid\n", dir)
+			for _, problem := range problems {
+				fmt.Printf("  %s\n", problem)
+			}
+		}
+		if !valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateBagCmd)
+
+	validateBagCmd.Flags().StringArray("bag", []string{}, "The bag directory to validate, can be repeated")
+}
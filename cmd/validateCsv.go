@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+	"github.com/spf13/cobra"
+)
+
+// validateCsvCmd represents the validate csv command
+var validateCsvCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Validate a Google Sheets or Workbench CSV",
+	Long: `Validate a Google Sheets or Workbench CSV, checking required fields,
+EDTF dates, email addresses, Y/N columns, parent/child sort order and that files exist.
+
+When --vocabulary-site or --vocabulary-snapshot is set, term backed columns are
+checked against their vocabularies. Values close to an existing term are errors,
+other values are warned about since they will create a new term. Term
+columns whose vocabulary is neither in the mapping nor found with
+--config-dir are warned about rather than skipped.
+
+Exits non-zero when the CSV has errors.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		csvPath, _ := cmd.Flags().GetString("csv")
+		mappingFile, _ := cmd.Flags().GetString("mapping")
+		filesDir, _ := cmd.Flags().GetString("files-dir")
+		skipFiles, _ := cmd.Flags().GetBool("skip-files")
+		format, _ := cmd.Flags().GetString("format")
+
+		if csvPath == "" {
+			slog.Error("--csv flag is required")
+			os.Exit(1)
+		}
+		mapping, err := workbench.LoadMapping(mappingFile)
+		if err != nil {
+			slog.Error("Error loading column mapping", "mapping", mappingFile, "err", err)
+			os.Exit(1)
+		}
+		if filesDir == "" {
+			filesDir = filepath.Dir(csvPath)
+		}
+
+		f, err := os.Open(csvPath)
+		if err != nil {
+			slog.Error("Unable to open CSV", "csv", csvPath, "err", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		vocabularies, fieldVocabularies, err := vocabularyOptions(cmd)
+		if err != nil {
+			slog.Error("Unable to load vocabularies", "err", err)
+			os.Exit(1)
+		}
+
+		v := validate.New(validate.Options{
+			Mapping:           mapping,
+			FilesDir:          filesDir,
+			SkipFiles:         skipFiles,
+			Vocabularies:      vocabularies,
+			FieldVocabularies: fieldVocabularies,
+		})
+		report, err := v.Validate(f)
+		if err != nil {
+			slog.Error("Unable to validate CSV", "csv", csvPath, "err", err)
+			os.Exit(1)
+		}
+
+		err = printValidationReport(report, format)
+		if err != nil {
+			slog.Error("Unable to print report", "err", err)
+			os.Exit(1)
+		}
+		if !report.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateCsvCmd)
+
+	validateCsvCmd.Flags().String("csv", "", "Path to the CSV to validate")
+	validateCsvCmd.Flags().String("mapping", "", "YAML file mapping spreadsheet columns to workbench fields (default: the built-in mapping)")
+	validateCsvCmd.Flags().String("files-dir", "", "Directory relative file paths are resolved against (default: the CSV's directory)")
+	validateCsvCmd.Flags().Bool("skip-files", false, "Do not check that files exist")
+	validateCsvCmd.Flags().String("format", "text", "Report format (text or json)")
+	addVocabularyFlags(validateCsvCmd)
+}
+
+func printValidationReport(report *api.ValidationReport, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case "text":
+		for _, w := range report.Warnings {
+			fmt.Printf("warning row %d %s: %s (%q)\n", w.Row, w.Column, w.Message, w.Value)
+		}
+		if report.Valid {
+			fmt.Printf("%d rows valid\n", report.Rows)
+			return nil
+		}
+		byRow := report.ByRow()
+		for _, row := range slices.Sorted(maps.Keys(byRow)) {
+			fmt.Printf("row %d\n", row)
+			for _, e := range byRow[row] {
+				if e.Value != "" {
+					fmt.Printf("  %s: %s (%q)\n", e.Column, e.Message, e.Value)
+					continue
+				}
+				fmt.Printf("  %s: %s\n", e.Column, e.Message)
+			}
+		}
+		fmt.Printf("%d errors in %d rows\n", len(report.Errors), len(byRow))
+		return nil
+	}
+
+	return fmt.Errorf("unsupported format %s", format)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/validate"
+	"github.com/spf13/cobra"
+)
+
+// addVocabularyFlags adds the flags used to check term backed columns
+func addVocabularyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("vocabulary-snapshot", "", "JSON file of vocabulary terms to check term backed columns against (see export vocabularies)")
+	cmd.Flags().String("vocabulary-site", "", "Islandora site to check term backed columns against (e.g. https://google.com)")
+	cmd.Flags().String("config-dir", "", "Config sync directory used to find the vocabularies each field references")
+}
+
+// vocabularyOptions returns the vocabularies to validate terms against
+// nil if term validation is not enabled
+func vocabularyOptions(cmd *cobra.Command) (validate.Vocabularies, map[string][]string, error) {
+	snapshot, _ := cmd.Flags().GetString("vocabulary-snapshot")
+	site, _ := cmd.Flags().GetString("vocabulary-site")
+	configDir, _ := cmd.Flags().GetString("config-dir")
+
+	var fieldVocabularies map[string][]string
+	if configDir != "" {
+		var err error
+		fieldVocabularies, err = configFieldVocabularies(configDir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case snapshot != "" && site != "":
+		return nil, nil, fmt.Errorf("only one of --vocabulary-snapshot and --vocabulary-site can be set")
+	case snapshot != "":
+		s, err := validate.LoadVocabularySnapshot(snapshot)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, fieldVocabularies, nil
+	case site != "":
+		return validate.NewSiteVocabularies(site), fieldVocabularies, nil
+	}
+
+	return nil, fieldVocabularies, nil
+}
+
+// configFieldVocabularies returns the vocabularies each node field references
+// e.g. field_genre => genre
+func configFieldVocabularies(dir string) (map[string][]string, error) {
+	nodes, err := entitySchemas(dir, "node", "node.type", "", nodeFields)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabularies := map[string][]string{}
+	for _, node := range nodes {
+		for _, field := range node.DrupalFields {
+			if field.TargetType != "taxonomy_term" {
+				continue
+			}
+			for _, vid := range field.TargetBundles {
+				if !slices.Contains(vocabularies[field.MachineName], vid) {
+					vocabularies[field.MachineName] = append(vocabularies[field.MachineName], vid)
+				}
+			}
+		}
+	}
+
+	return vocabularies, nil
+}
@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 type ConfigReferenceField []ConfigReference
 
 type ConfigReference struct {
@@ -7,3 +9,23 @@ type ConfigReference struct {
 	TargetType string `json:"target_type"`
 	TargetUuid string `json:"target_uuid"`
 }
+
+func (field ConfigReferenceField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.TargetId
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *ConfigReferenceField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]ConfigReference, len(values))
+	for i, value := range values {
+		s[i] = ConfigReference{
+			TargetId: value,
+		}
+	}
+	*field = s
+	return nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvRoundTrip(t *testing.T) {
+	link := LinkField{{Uri: "https://example.com", Title: "Example"}, {Uri: "https://example.org"}}
+	csv, err := link.MarshalCSV()
+	assert.NoError(t, err)
+	var gotLink LinkField
+	assert.NoError(t, gotLink.UnmarshalCSV(csv))
+	assert.Equal(t, link, gotLink)
+
+	dates := DateRangeField{{Value: "2020-01-01", EndValue: "2020-12-31"}}
+	csv, err = dates.MarshalCSV()
+	assert.NoError(t, err)
+	var gotDates DateRangeField
+	assert.NoError(t, gotDates.UnmarshalCSV(csv))
+	assert.Equal(t, dates, gotDates)
+
+	floats := FloatField{{Value: 1.5}, {Value: 2}}
+	csv, err = floats.MarshalCSV()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.5|2", csv)
+	var gotFloats FloatField
+	assert.NoError(t, gotFloats.UnmarshalCSV(csv))
+	assert.Equal(t, floats, gotFloats)
+
+	images := ImageField{{TargetId: 4, Alt: "A portrait", Width: 10, Height: 20}}
+	csv, err = images.MarshalCSV()
+	assert.NoError(t, err)
+	var gotImages ImageField
+	assert.NoError(t, gotImages.UnmarshalCSV(csv))
+	assert.Equal(t, images[0].Alt, gotImages[0].Alt)
+}
+
+func TestTimestampJson(t *testing.T) {
+	var field TimestampField
+	err := json.Unmarshal([]byte(`[{"value": "2024-06-27T04:05:47+00:00", "format": "Y-m-d\\TH:i:sP"}]`), &field)
+	assert.NoError(t, err)
+	ts, err := field[0].Time()
+	assert.NoError(t, err)
+	assert.Equal(t, 2024, ts.Year())
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type DateRangeField []DateRange
+type DateRange struct {
+	Value    string `json:"value"`
+	EndValue string `json:"end_value,omitempty"`
+}
+
+func (field *DateRange) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal DateRange string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field DateRangeField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *DateRangeField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]DateRange, len(values))
+	for i, value := range values {
+		var f DateRange
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import "strings"
+
+// DateTimeField holds a datetime field value, which is either
+// a date (2006-01-02) or a date and time (2006-01-02T15:04:05)
+type DateTimeField []DateTime
+
+type DateTime struct {
+	Value string `json:"value"`
+}
+
+func (field DateTimeField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *DateTimeField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]DateTime, len(values))
+	for i, value := range values {
+		s[i] = DateTime{
+			Value: value,
+		}
+	}
+	*field = s
+	return nil
+}
+
+func (field *DateTime) String() string {
+	return field.Value
+}
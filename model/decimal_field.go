@@ -0,0 +1,35 @@
+package model
+
+import "strings"
+
+// DecimalField keeps the value as a string
+// since Drupal serializes decimals as strings to preserve their precision
+type DecimalField []Decimal
+
+type Decimal struct {
+	Value string `json:"value"`
+}
+
+func (field DecimalField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *DecimalField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Decimal, len(values))
+	for i, value := range values {
+		s[i] = Decimal{
+			Value: value,
+		}
+	}
+	*field = s
+	return nil
+}
+
+func (field *Decimal) String() string {
+	return field.Value
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+// EntityReferenceRevisionsField references a specific revision of an entity
+// most commonly a paragraph
+type EntityReferenceRevisionsField []EntityReferenceRevisions
+type EntityReferenceRevisions struct {
+	TargetId         int    `json:"target_id"`
+	TargetRevisionId int    `json:"target_revision_id"`
+	TargetType       string `json:"target_type,omitempty"`
+	TargetUuid       string `json:"target_uuid,omitempty"`
+}
+
+func (field *EntityReferenceRevisions) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal EntityReferenceRevisions string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field EntityReferenceRevisionsField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *EntityReferenceRevisionsField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]EntityReferenceRevisions, len(values))
+	for i, value := range values {
+		var f EntityReferenceRevisions
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
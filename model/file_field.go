@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type FileField []File
+type File struct {
+	TargetId    int    `json:"target_id"`
+	TargetType  string `json:"target_type,omitempty"`
+	TargetUuid  string `json:"target_uuid,omitempty"`
+	Display     bool   `json:"display,omitempty"`
+	Description string `json:"description,omitempty"`
+	Url         string `json:"url,omitempty"`
+}
+
+func (field *File) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal File string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field FileField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *FileField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]File, len(values))
+	for i, value := range values {
+		var f File
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+type FloatField []Float
+type Float struct {
+	Value float64 `json:"value"`
+}
+
+func (field FloatField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *FloatField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Float, len(values))
+	for i, value := range values {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		s[i] = Float{
+			Value: f,
+		}
+	}
+	*field = s
+	return nil
+}
+
+func (field *Float) String() string {
+	return strconv.FormatFloat(field.Value, 'f', -1, 64)
+}
@@ -11,12 +11,12 @@ type Generic struct {
 	Value     string `json:"value"`
 }
 
-func (field GenericField) MarshalCSV() string {
+func (field GenericField) MarshalCSV() (string, error) {
 	values := make([]string, len(field))
 	for i, field := range field {
 		values[i] = field.String()
 	}
-	return strings.Join(values, "|")
+	return strings.Join(values, "|"), nil
 }
 
 func (field *GenericField) String() string {
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type ImageField []Image
+type Image struct {
+	TargetId   int    `json:"target_id"`
+	TargetType string `json:"target_type,omitempty"`
+	TargetUuid string `json:"target_uuid,omitempty"`
+	Alt        string `json:"alt,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+	Url        string `json:"url,omitempty"`
+}
+
+func (field *Image) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal Image string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field ImageField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *ImageField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Image, len(values))
+	for i, value := range values {
+		var f Image
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
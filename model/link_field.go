@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type LinkField []Link
+type Link struct {
+	Uri   string `json:"uri"`
+	Title string `json:"title,omitempty"`
+}
+
+func (field *Link) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal Link string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field LinkField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *LinkField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Link, len(values))
+	for i, value := range values {
+		var f Link
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
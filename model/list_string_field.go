@@ -0,0 +1,34 @@
+package model
+
+import "strings"
+
+// ListStringField holds the key of the selected allowed value
+type ListStringField []ListString
+
+type ListString struct {
+	Value string `json:"value"`
+}
+
+func (field ListStringField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *ListStringField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]ListString, len(values))
+	for i, value := range values {
+		s[i] = ListString{
+			Value: value,
+		}
+	}
+	*field = s
+	return nil
+}
+
+func (field *ListString) String() string {
+	return field.Value
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type PathField []Path
+type Path struct {
+	Alias    string `json:"alias"`
+	Pid      int    `json:"pid,omitempty"`
+	Langcode string `json:"langcode,omitempty"`
+}
+
+func (field *Path) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal Path string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field PathField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *PathField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Path, len(values))
+	for i, value := range values {
+		var f Path
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
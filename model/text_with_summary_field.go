@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+type TextWithSummaryField []TextWithSummary
+type TextWithSummary struct {
+	Value     string `json:"value"`
+	Format    string `json:"format,omitempty"`
+	Processed string `json:"processed,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+func (field *TextWithSummary) String() string {
+	data, err := json.Marshal(field)
+	if err != nil {
+		slog.Error("Unable to marshal TextWithSummary string", "err", err)
+		return ""
+	}
+
+	return string(data)
+}
+
+func (field TextWithSummaryField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *TextWithSummaryField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]TextWithSummary, len(values))
+	for i, value := range values {
+		var f TextWithSummary
+		err := json.Unmarshal([]byte(value), &f)
+		if err != nil {
+			return err
+		}
+		s[i] = f
+	}
+	*field = s
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TimestampField holds the serialized form of timestamp/created/changed fields
+// e.g. {"value": "2024-06-27T04:05:47+00:00", "format": "Y-m-d\\TH:i:sP"}
+type TimestampField []Timestamp
+
+type Timestamp struct {
+	Value  string `json:"value"`
+	Format string `json:"format,omitempty"`
+}
+
+func (field TimestampField) MarshalCSV() (string, error) {
+	values := make([]string, len(field))
+	for i, field := range field {
+		values[i] = field.String()
+	}
+	return strings.Join(values, "|"), nil
+}
+
+func (field *TimestampField) UnmarshalCSV(csv string) error {
+	values := strings.Split(csv, "|")
+	s := make([]Timestamp, len(values))
+	for i, value := range values {
+		s[i] = Timestamp{
+			Value: value,
+		}
+	}
+	*field = s
+	return nil
+}
+
+func (field *Timestamp) String() string {
+	return field.Value
+}
+
+func (field *Timestamp) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339, field.Value)
+}
@@ -0,0 +1,311 @@
+// Package bagit writes and validates BagIt 1.0 bags (RFC 8493)
+package bagit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const Version = "1.0"
+
+// Algorithms are the checksums written to the payload and tag manifests
+var Algorithms = []string{"sha256", "sha512"}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported algorithm %s", algorithm)
+}
+
+// Tag is a bag-info.txt metadata element
+type Tag struct {
+	Label string
+	Value string
+}
+
+// Bag is a bag being written.
+// Payload checksums are appended to the manifests as files are added,
+// so a bag interrupted part way is resumed by opening it again.
+type Bag struct {
+	Dir string
+	// manifests are the recorded payload checksums by algorithm then path
+	manifests map[string]map[string]string
+	// current are the payload files added, or found complete, since the bag was opened
+	current map[string]bool
+}
+
+// Create starts a bag in dir, or reopens an unfinished one
+func Create(dir string) (*Bag, error) {
+	err := os.MkdirAll(filepath.Join(dir, "data"), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bag{Dir: dir, manifests: map[string]map[string]string{}, current: map[string]bool{}}
+	for _, algorithm := range Algorithms {
+		b.manifests[algorithm], err = readManifest(filepath.Join(dir, "manifest-"+algorithm+".txt"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if b.manifests[algorithm] == nil {
+			b.manifests[algorithm] = map[string]string{}
+		}
+	}
+
+	return b, nil
+}
+
+// Path returns the path on disk of a payload file, name is relative to data/
+func (b *Bag) Path(name string) string {
+	return filepath.Join(b.Dir, "data", filepath.FromSlash(name))
+}
+
+// Complete reports whether a payload file was already added and still matches its checksums
+func (b *Bag) Complete(name string) bool {
+	key := "data/" + name
+	for _, algorithm := range Algorithms {
+		if _, ok := b.manifests[algorithm][key]; !ok {
+			return false
+		}
+	}
+	sums, err := checksums(b.Path(name))
+	if err != nil {
+		return false
+	}
+	for _, algorithm := range Algorithms {
+		if sums[algorithm] != b.manifests[algorithm][key] {
+			return false
+		}
+	}
+	b.current[key] = true
+
+	return true
+}
+
+// WriteFile adds a payload file with the given contents
+func (b *Bag) WriteFile(name string, data []byte) error {
+	path := b.Path(name)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return b.Add(name)
+}
+
+// Add records the checksums of a payload file already written to Path(name)
+func (b *Bag) Add(name string) error {
+	sums, err := checksums(b.Path(name))
+	if err != nil {
+		return err
+	}
+
+	key := "data/" + name
+	b.current[key] = true
+	for _, algorithm := range Algorithms {
+		b.manifests[algorithm][key] = sums[algorithm]
+		f, err := os.OpenFile(filepath.Join(b.Dir, "manifest-"+algorithm+".txt"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(f, "%s  %s\n", sums[algorithm], encodePath(key))
+		if err != nil {
+			f.Close()
+			return err
+		}
+		err = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Finish writes bagit.txt, bag-info.txt with the Payload-Oxum and Bagging-Date,
+// sorted payload manifests and the tag manifests.
+// Payload files left from an earlier run that were not added again are removed.
+func (b *Bag) Finish(info []Tag) error {
+	err := os.WriteFile(filepath.Join(b.Dir, "bagit.txt"), []byte("BagIt-Version: "+Version+"\nTag-File-Character-Encoding: UTF-8\n"), 0644)
+	if err != nil {
+		return err
+	}
+
+	var bytes, files int64
+	err = filepath.WalkDir(filepath.Join(b.Dir, "data"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(b.Dir, path)
+		if err != nil {
+			return err
+		}
+		if !b.current[filepath.ToSlash(rel)] {
+			return os.Remove(path)
+		}
+		stat, err := d.Info()
+		if err != nil {
+			return err
+		}
+		bytes += stat.Size()
+		files++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, algorithm := range Algorithms {
+		for key := range b.manifests[algorithm] {
+			if !b.current[key] {
+				delete(b.manifests[algorithm], key)
+			}
+		}
+		err = writeManifest(filepath.Join(b.Dir, "manifest-"+algorithm+".txt"), b.manifests[algorithm])
+		if err != nil {
+			return err
+		}
+	}
+
+	info = append(info,
+		Tag{Label: "Bagging-Date", Value: time.Now().Format("2006-01-02")},
+		Tag{Label: "Payload-Oxum", Value: fmt.Sprintf("%d.%d", bytes, files)},
+	)
+	var buf strings.Builder
+	for _, tag := range info {
+		if strings.TrimSpace(tag.Value) == "" {
+			continue
+		}
+		// continuation lines are indented
+		value := strings.ReplaceAll(strings.TrimSpace(tag.Value), "\n", "\n  ")
+		fmt.Fprintf(&buf, "%s: %s\n", tag.Label, value)
+	}
+	err = os.WriteFile(filepath.Join(b.Dir, "bag-info.txt"), []byte(buf.String()), 0644)
+	if err != nil {
+		return err
+	}
+
+	tagFiles := []string{"bagit.txt", "bag-info.txt"}
+	for _, algorithm := range Algorithms {
+		tagFiles = append(tagFiles, "manifest-"+algorithm+".txt")
+	}
+	for _, algorithm := range Algorithms {
+		tags := map[string]string{}
+		for _, name := range tagFiles {
+			sums, err := checksums(filepath.Join(b.Dir, name))
+			if err != nil {
+				return err
+			}
+			tags[name] = sums[algorithm]
+		}
+		err = writeManifest(filepath.Join(b.Dir, "tagmanifest-"+algorithm+".txt"), tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checksums(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hashes := map[string]hash.Hash{}
+	writers := []io.Writer{}
+	for _, algorithm := range Algorithms {
+		h, err := newHash(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		hashes[algorithm] = h
+		writers = append(writers, h)
+	}
+	_, err = io.Copy(io.MultiWriter(writers...), f)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := map[string]string{}
+	for algorithm, h := range hashes {
+		sums[algorithm] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return sums, nil
+}
+
+// readManifest parses a manifest, later lines for the same path win
+func readManifest(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	manifest := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: expected a checksum and a path", filepath.Base(path), line)
+		}
+		manifest[decodePath(strings.TrimLeft(name, " *"))] = strings.ToLower(sum)
+	}
+
+	return manifest, scanner.Err()
+}
+
+func writeManifest(path string, manifest map[string]string) error {
+	keys := make([]string, 0, len(manifest))
+	for key := range manifest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s  %s\n", manifest[key], encodePath(key))
+	}
+
+	return os.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+// manifest paths percent encode line breaks and percent signs
+var pathEncoder = strings.NewReplacer("%", "%25", "\n", "%0A", "\r", "%0D")
+var pathDecoder = strings.NewReplacer("%25", "%", "%0A", "\n", "%0a", "\n", "%0D", "\r", "%0d", "\r")
+
+func encodePath(path string) string {
+	return pathEncoder.Replace(path)
+}
+
+func decodePath(path string) string {
+	return pathDecoder.Replace(path)
+}
@@ -0,0 +1,50 @@
+package bagit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBag(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bag")
+
+	b, err := Create(dir)
+	require.NoError(t, err)
+	require.NoError(t, b.WriteFile("node.json", []byte(`{"nid":[{"value":1}]}`)))
+	require.NoError(t, b.WriteFile("media/1-page.jpg", []byte("image")))
+	require.NoError(t, b.WriteFile("media/2-old.jpg", []byte("stale")))
+	// interrupted here, the manifests already list the files
+
+	b, err = Create(dir)
+	require.NoError(t, err)
+	assert.True(t, b.Complete("media/1-page.jpg"))
+	assert.False(t, b.Complete("media/3-new.jpg"))
+	require.NoError(t, b.WriteFile("node.json", []byte(`{"nid":[{"value":1}],"title":[]}`)))
+	require.NoError(t, b.Finish([]Tag{{Label: "Source-Organization", Value: "Lehigh University"}}))
+
+	assert.NoFileExists(t, b.Path("media/2-old.jpg"))
+	info, err := os.ReadFile(filepath.Join(dir, "bag-info.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(info), "Source-Organization: Lehigh University\n")
+	assert.Contains(t, string(info), "Payload-Oxum: 37.2\n")
+	manifest, err := os.ReadFile(filepath.Join(dir, "manifest-sha256.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d  data/media/1-page.jpg\n"+
+		"5dce9087bc8de8af65e26c078dcb508317a2a974ddd8ad37aa3679fa83bd9fc4  data/node.json\n", string(manifest))
+
+	problems, err := Validate(dir)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	require.NoError(t, os.WriteFile(b.Path("media/1-page.jpg"), []byte("changed"), 0644))
+	require.NoError(t, os.WriteFile(b.Path("extra.txt"), []byte("extra"), 0644))
+	problems, err = Validate(dir)
+	require.NoError(t, err)
+	assert.Contains(t, problems, "manifest-sha256.txt: data/extra.txt is not listed")
+	assert.Contains(t, problems, "bag-info.txt: Payload-Oxum 37.2 does not match the payload, 44 bytes in 3 files")
+	assert.Len(t, problems, 5)
+}
@@ -0,0 +1,203 @@
+package bagit
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Validate checks a bag is complete and every file matches its checksums.
+// Problems with the bag are returned as messages, the error is for bags that cannot be read.
+func Validate(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	problems := []string{}
+	declaration, err := readTags(filepath.Join(dir, "bagit.txt"))
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("bagit.txt: %v", err))
+	} else {
+		if declaration["BagIt-Version"] == "" {
+			problems = append(problems, "bagit.txt: missing BagIt-Version")
+		}
+		if declaration["Tag-File-Character-Encoding"] == "" {
+			problems = append(problems, "bagit.txt: missing Tag-File-Character-Encoding")
+		}
+	}
+	if stat, err := os.Stat(filepath.Join(dir, "data")); err != nil || !stat.IsDir() {
+		problems = append(problems, "missing payload directory data/")
+		return problems, nil
+	}
+
+	manifests, err := filepath.Glob(filepath.Join(dir, "manifest-*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		problems = append(problems, "no payload manifest")
+	}
+
+	payload := map[string]int64{}
+	err = filepath.WalkDir(filepath.Join(dir, "data"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		stat, err := d.Info()
+		if err != nil {
+			return err
+		}
+		payload[filepath.ToSlash(rel)] = stat.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manifest := range manifests {
+		name := filepath.Base(manifest)
+		algorithm := strings.TrimSuffix(strings.TrimPrefix(name, "manifest-"), ".txt")
+		entries, err := readManifest(manifest)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		problems = append(problems, checkManifest(dir, name, algorithm, entries)...)
+		for _, path := range sortedPaths(payload) {
+			if _, ok := entries[path]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: %s is not listed", name, path))
+			}
+		}
+	}
+
+	tagManifests, err := filepath.Glob(filepath.Join(dir, "tagmanifest-*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, manifest := range tagManifests {
+		name := filepath.Base(manifest)
+		algorithm := strings.TrimSuffix(strings.TrimPrefix(name, "tagmanifest-"), ".txt")
+		entries, err := readManifest(manifest)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		problems = append(problems, checkManifest(dir, name, algorithm, entries)...)
+	}
+
+	bagInfo, err := readTags(filepath.Join(dir, "bag-info.txt"))
+	if err != nil && !os.IsNotExist(err) {
+		problems = append(problems, fmt.Sprintf("bag-info.txt: %v", err))
+	}
+	if oxum := bagInfo["Payload-Oxum"]; oxum != "" {
+		var bytes int64
+		for _, size := range payload {
+			bytes += size
+		}
+		if oxum != strconv.FormatInt(bytes, 10)+"."+strconv.Itoa(len(payload)) {
+			problems = append(problems, fmt.Sprintf("bag-info.txt: Payload-Oxum %s does not match the payload, %d bytes in %d files", oxum, bytes, len(payload)))
+		}
+	}
+
+	return problems, nil
+}
+
+func checkManifest(dir, name, algorithm string, entries map[string]string) []string {
+	if _, err := newHash(algorithm); err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+
+	problems := []string{}
+	for _, path := range sortedPaths(entries) {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if rel, err := filepath.Rel(dir, full); err != nil || strings.HasPrefix(rel, "..") {
+			problems = append(problems, fmt.Sprintf("%s: %s is outside the bag", name, path))
+			continue
+		}
+		sums, err := checksumsWith(full, algorithm)
+		if os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("%s: %s does not exist", name, path))
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s: %v", name, path, err))
+			continue
+		}
+		if sums != entries[path] {
+			problems = append(problems, fmt.Sprintf("%s: %s checksum %s does not match %s", name, path, sums, entries[path]))
+		}
+	}
+
+	return problems
+}
+
+func checksumsWith(path, algorithm string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	_, err = bufio.NewReader(f).WriteTo(h)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// readTags parses a tag file's labels, continuation lines are joined to their label's value
+func readTags(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]string{}, err
+	}
+
+	tags := map[string]string{}
+	label := ""
+	for i, line := range strings.Split(strings.TrimRight(string(data), "\r\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if label == "" {
+				return tags, fmt.Errorf("line %d: continuation without a label", i+1)
+			}
+			tags[label] += " " + strings.TrimSpace(line)
+			continue
+		}
+		l, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return tags, fmt.Errorf("line %d: expected a label and value", i+1)
+		}
+		label = strings.TrimSpace(l)
+		tags[label] = strings.TrimSpace(value)
+	}
+
+	return tags, nil
+}
+
+func sortedPaths[T any](m map[string]T) []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,256 @@
+package citation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// BibtexTypes maps CSL item types to BibTeX entry types, others are misc
+var BibtexTypes = map[string]string{
+	"article-journal":  "article",
+	"book":             "book",
+	"chapter":          "incollection",
+	"paper-conference": "inproceedings",
+	"report":           "techreport",
+	"thesis":           "phdthesis",
+	"manuscript":       "unpublished",
+}
+
+// accents are the LaTeX accent commands for Unicode combining marks
+var accents = map[rune]string{
+	'\u0300': "`",
+	'\u0301': "'",
+	'\u0302': "^",
+	'\u0303': "~",
+	'\u0304': "=",
+	'\u0306': "u",
+	'\u0307': ".",
+	'\u0308': `"`,
+	'\u030A': "r",
+	'\u030B': "H",
+	'\u030C': "v",
+	'\u0323': "d",
+	'\u0327': "c",
+	'\u0328': "k",
+}
+
+// letters are the LaTeX commands for letters without a decomposition
+var letters = map[rune]string{
+	'ß': `{\ss}`,
+	'æ': `{\ae}`,
+	'Æ': `{\AE}`,
+	'œ': `{\oe}`,
+	'Œ': `{\OE}`,
+	'ø': `{\o}`,
+	'Ø': `{\O}`,
+	'ł': `{\l}`,
+	'Ł': `{\L}`,
+	'ı': `{\i}`,
+	'–': "--",
+	'—': "---",
+	'‘': "`",
+	'’': "'",
+	'“': "``",
+	'”': "''",
+}
+
+var specials = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// BibtexEscape escapes LaTeX special characters.
+// Unless utf8 is set, for biber and biblatex, accented letters
+// are also written as LaTeX accents e.g. é as {\'e}.
+func BibtexEscape(s string, utf8 bool) string {
+	s = specials.Replace(s)
+	if utf8 {
+		return s
+	}
+
+	var b strings.Builder
+	runes := []rune(norm.NFD.String(s))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if letter, ok := letters[r]; ok {
+			b.WriteString(letter)
+			continue
+		}
+		marks := []rune{}
+		for i+1 < len(runes) && unicode.Is(unicode.Mn, runes[i+1]) {
+			i++
+			marks = append(marks, runes[i])
+		}
+		if len(marks) == 0 {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteString(accent(r, marks))
+	}
+
+	return b.String()
+}
+
+// accent writes a letter and its combining marks as LaTeX, or as
+// the composed character when a mark has no LaTeX equivalent
+func accent(base rune, marks []rune) string {
+	s := string(base)
+	switch base {
+	case 'i':
+		s = `\i`
+	case 'j':
+		s = `\j`
+	}
+	for _, mark := range marks {
+		cmd, ok := accents[mark]
+		if !ok {
+			return norm.NFC.String(string(base) + string(marks))
+		}
+		if unicode.IsLetter(rune(cmd[0])) {
+			s = `\` + cmd + "{" + s + "}"
+		} else {
+			s = `\` + cmd + s
+		}
+	}
+
+	return "{" + s + "}"
+}
+
+// Key returns a citation key from the first author's family name, the year
+// and the first word of the title e.g. doe2020rolling
+func Key(item *Item) string {
+	key := ""
+	if len(item.Author) > 0 {
+		name := item.Author[0].Family
+		if name == "" {
+			name, _, _ = strings.Cut(item.Author[0].Literal, " ")
+		}
+		key = keyPart(name)
+	}
+	key += item.Year()
+	for _, word := range strings.Fields(item.Title) {
+		word = keyPart(word)
+		if word != "" && word != "a" && word != "an" && word != "the" {
+			key += word
+			break
+		}
+	}
+	if key == "" || unicode.IsDigit(rune(key[0])) {
+		key = "node" + item.Id + key
+	}
+
+	return key
+}
+
+// keyPart lower cases s and drops everything but ASCII letters and digits
+func keyPart(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// Bibtex writes items as BibTeX entries, suffixing duplicate keys with a, b, c...
+func Bibtex(items []*Item, utf8 bool) []byte {
+	var b strings.Builder
+	keys := map[string]int{}
+	for _, item := range items {
+		key := Key(item)
+		keys[key]++
+		if n := keys[key]; n > 1 {
+			key += string(rune('a' + n - 2))
+		}
+
+		entryType, ok := BibtexTypes[item.Type]
+		if !ok {
+			entryType = "misc"
+		}
+		if entryType == "phdthesis" && strings.Contains(strings.ToLower(item.Genre), "master") {
+			entryType = "mastersthesis"
+		}
+
+		fields := [][2]string{
+			{"author", names(item.Author, utf8)},
+			{"editor", names(item.Editor, utf8)},
+			{"title", BibtexEscape(item.Title, utf8)},
+		}
+		container := BibtexEscape(item.ContainerTitle, utf8)
+		switch entryType {
+		case "article":
+			fields = append(fields, [2]string{"journal", container})
+		case "incollection", "inproceedings":
+			fields = append(fields, [2]string{"booktitle", container})
+		case "misc":
+			fields = append(fields, [2]string{"howpublished", container})
+		}
+		if item.Issued != nil && len(item.Issued.DateParts) > 0 {
+			parts := item.Issued.DateParts[0]
+			fields = append(fields, [2]string{"year", fmt.Sprint(parts[0])})
+			if len(parts) > 1 {
+				fields = append(fields, [2]string{"month", fmt.Sprint(parts[1])})
+			}
+		} else if item.Issued != nil {
+			fields = append(fields, [2]string{"year", BibtexEscape(item.Issued.Literal, utf8)})
+		}
+		publisher := BibtexEscape(item.Publisher, utf8)
+		switch entryType {
+		case "phdthesis", "mastersthesis":
+			fields = append(fields, [2]string{"school", publisher})
+		case "techreport":
+			fields = append(fields, [2]string{"institution", publisher})
+		default:
+			fields = append(fields, [2]string{"publisher", publisher})
+		}
+		fields = append(fields,
+			[2]string{"address", BibtexEscape(item.PublisherPlace, utf8)},
+			[2]string{"volume", BibtexEscape(item.Volume, utf8)},
+			[2]string{"number", BibtexEscape(item.Issue, utf8)},
+			[2]string{"pages", strings.ReplaceAll(BibtexEscape(item.Page, utf8), "-", "--")},
+			[2]string{"issn", item.Issn},
+			[2]string{"isbn", item.Isbn},
+			[2]string{"doi", item.Doi},
+			[2]string{"url", item.Url},
+			[2]string{"abstract", BibtexEscape(item.Abstract, utf8)},
+			[2]string{"keywords", BibtexEscape(item.Keyword, utf8)},
+			[2]string{"language", item.Language},
+		)
+
+		fmt.Fprintf(&b, "@%s{%s,\n", entryType, key)
+		for _, field := range fields {
+			if field[1] != "" {
+				fmt.Fprintf(&b, "  %s = {%s},\n", field[0], field[1])
+			}
+		}
+		b.WriteString("}\n\n")
+	}
+
+	return []byte(b.String())
+}
+
+// names joins names with "and", organizations are braced so they aren't split
+func names(list []Name, utf8 bool) string {
+	escaped := make([]string, len(list))
+	for i, n := range list {
+		escaped[i] = BibtexEscape(n.String(), utf8)
+		if n.Literal != "" {
+			escaped[i] = "{" + escaped[i] + "}"
+		}
+	}
+
+	return strings.Join(escaped, " and ")
+}
@@ -0,0 +1,364 @@
+// Package citation builds citations of Islandora nodes for reference managers.
+// Nodes are crosswalked into CSL-JSON items, which are also written as BibTeX and RIS.
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+type Options struct {
+	Mapping *mods.Mapping
+	// Terms resolves taxonomy terms and linked agents
+	Terms islandora.TermLookup
+	// BaseUrl is used for the node's URL
+	BaseUrl string
+	// Publisher is used when the node has no publisher
+	Publisher string
+}
+
+// Item is a CSL-JSON item, see https://citeproc-js.readthedocs.io/en/latest/csl-json/markup.html
+type Item struct {
+	Id             string `json:"id"`
+	Type           string `json:"type"`
+	Title          string `json:"title,omitempty"`
+	Author         []Name `json:"author,omitempty"`
+	Editor         []Name `json:"editor,omitempty"`
+	Issued         *Date  `json:"issued,omitempty"`
+	ContainerTitle string `json:"container-title,omitempty"`
+	Volume         string `json:"volume,omitempty"`
+	Issue          string `json:"issue,omitempty"`
+	Page           string `json:"page,omitempty"`
+	Publisher      string `json:"publisher,omitempty"`
+	PublisherPlace string `json:"publisher-place,omitempty"`
+	Genre          string `json:"genre,omitempty"`
+	Doi            string `json:"DOI,omitempty"`
+	Url            string `json:"URL,omitempty"`
+	Issn           string `json:"ISSN,omitempty"`
+	Isbn           string `json:"ISBN,omitempty"`
+	Abstract       string `json:"abstract,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Keyword        string `json:"keyword,omitempty"`
+	// Keywords are the subject headings Keyword joins, kept apart for formats with a tag per keyword
+	Keywords []string `json:"-"`
+}
+
+// Name is a person with family and given names, or an organization as a literal
+type Name struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// Date is a CSL date, an interval has two date-parts
+type Date struct {
+	DateParts [][]int `json:"date-parts,omitempty"`
+	Circa     bool    `json:"circa,omitempty"`
+	Literal   string  `json:"literal,omitempty"`
+}
+
+// GenreTypes maps words in genre term names to CSL item types, the first match is used
+var GenreTypes = []struct {
+	Word string
+	Type string
+}{
+	{"thesis", "thesis"},
+	{"theses", "thesis"},
+	{"dissertation", "thesis"},
+	{"chapter", "chapter"},
+	{"conference", "paper-conference"},
+	{"proceeding", "paper-conference"},
+	{"article", "article-journal"},
+	{"report", "report"},
+	{"dataset", "dataset"},
+	{"book", "book"},
+	{"map", "map"},
+	{"photograph", "graphic"},
+	{"poster", "graphic"},
+	{"drawing", "graphic"},
+	{"film", "motion_picture"},
+	{"video", "motion_picture"},
+	{"sound", "song"},
+	{"music", "song"},
+	{"interview", "interview"},
+	{"oral histor", "interview"},
+	{"letter", "manuscript"},
+	{"correspondence", "manuscript"},
+	{"manuscript", "manuscript"},
+	{"diar", "manuscript"},
+}
+
+// ModelTypes are the CSL item types of nodes whose genre isn't in GenreTypes
+var ModelTypes = map[string]string{
+	"Digital Document":  "document",
+	"Image":             "graphic",
+	"Paged Content":     "book",
+	"Publication Issue": "periodical",
+	"Video":             "motion_picture",
+	"Audio":             "song",
+}
+
+var (
+	issnRegex = regexp.MustCompile(`^[0-9]{4}-?[0-9]{3}[0-9Xx]$`)
+	isbnRegex = regexp.MustCompile(`^(97[89][- ]?)?[0-9][0-9- ]{8,}[0-9Xx]$`)
+)
+
+// Type returns the CSL item type for a node's model and genre names
+func Type(model string, genres []string) string {
+	for _, genre := range genres {
+		g := strings.ToLower(genre)
+		for _, t := range GenreTypes {
+			if strings.Contains(g, t.Word) {
+				return t.Type
+			}
+		}
+	}
+	if t, ok := ModelTypes[model]; ok {
+		return t
+	}
+
+	return "document"
+}
+
+// FromNode crosswalks a node into a CSL-JSON item.
+// Linked agents with relators:aut or relators:cre are authors and relators:edt editors.
+func FromNode(node *api.IslandoraObject, opts Options) (*Item, error) {
+	record, err := mods.FromNode(node, mods.Options{Mapping: opts.Mapping, Terms: opts.Terms, BaseUrl: opts.BaseUrl})
+	if err != nil {
+		return nil, err
+	}
+	agents, err := islandora.LinkedAgents(node, opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+	models, err := islandora.FieldStrings(node, "field_model", opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []string{}
+	for _, g := range record.Genre {
+		genres = append(genres, g.Value)
+	}
+	model := ""
+	if len(models) > 0 {
+		model = models[0]
+	}
+
+	item := &Item{
+		Id:     node.Nid.String(),
+		Type:   Type(model, genres),
+		Doi:    islandora.Doi(node),
+		Volume: islandora.PartNumber(node, "volume"),
+		Issue:  islandora.PartNumber(node, "issue", "number"),
+	}
+	if item.Type == "thesis" && len(genres) > 0 {
+		item.Genre = genres[0]
+	}
+	if first, last := islandora.Pages(node); last != "" {
+		item.Page = first + "-" + last
+	} else {
+		item.Page = first
+	}
+
+	for _, title := range record.TitleInfo {
+		if title.Type == "" {
+			item.Title = title.Title
+			if title.SubTitle != "" {
+				item.Title += ": " + title.SubTitle
+			}
+			break
+		}
+	}
+
+	publisher, institution := "", ""
+	for _, agent := range agents {
+		switch agent.Relator {
+		case "aut", "cre":
+			item.Author = append(item.Author, name(agent))
+		case "edt":
+			item.Editor = append(item.Editor, name(agent))
+		case "pbl":
+			if publisher == "" {
+				publisher = agent.Name
+			}
+		case "dgg":
+			if institution == "" {
+				institution = agent.Name
+			}
+		}
+	}
+
+	var dates []mods.Date
+	for _, origin := range record.OriginInfo {
+		if item.Publisher == "" && len(origin.Publisher) > 0 {
+			item.Publisher = origin.Publisher[0].Value
+		}
+		for _, place := range origin.Place {
+			for _, term := range place.PlaceTerm {
+				if item.PublisherPlace == "" && term.Type == "text" {
+					item.PublisherPlace = term.Value
+				}
+			}
+		}
+		for _, list := range [][]mods.Date{origin.DateIssued, origin.DateCreated, origin.CopyrightDate} {
+			if len(dates) == 0 {
+				dates = list
+			}
+		}
+	}
+	item.Issued = issued(dates)
+	for _, p := range []string{publisher, institution, opts.Publisher} {
+		if item.Publisher == "" {
+			item.Publisher = p
+		}
+	}
+	if item.Type == "thesis" && institution != "" {
+		item.Publisher = institution
+	}
+
+	for _, related := range record.RelatedItem {
+		if related.Type != "host" {
+			continue
+		}
+		if len(related.TitleInfo) > 0 {
+			item.ContainerTitle = related.TitleInfo[0].Title
+		}
+		for _, id := range related.Identifier {
+			switch {
+			case issnRegex.MatchString(id.Value):
+				item.Issn = id.Value
+			case isbnRegex.MatchString(id.Value):
+				item.Isbn = id.Value
+			}
+		}
+		break
+	}
+	for _, id := range record.Identifier {
+		if strings.EqualFold(id.Type, "isbn") && item.Isbn == "" {
+			item.Isbn = id.Value
+		}
+	}
+
+	if opts.BaseUrl != "" {
+		item.Url = fmt.Sprintf("%s/node/%s", strings.TrimSuffix(opts.BaseUrl, "/"), item.Id)
+	}
+	if len(record.Abstract) > 0 {
+		item.Abstract = record.Abstract[0].Value
+	}
+	for _, l := range record.Language {
+		for _, t := range l.LanguageTerm {
+			if item.Language == "" && t.Type == "code" {
+				item.Language = t.Value
+			}
+		}
+	}
+	for _, s := range record.Subject {
+		for _, t := range s.Topic {
+			item.Keywords = append(item.Keywords, t.Value)
+		}
+	}
+	item.Keyword = strings.Join(item.Keywords, ", ")
+
+	return item, nil
+}
+
+func name(agent islandora.Agent) Name {
+	if !agent.Personal() {
+		return Name{Literal: agent.Name}
+	}
+	family, given := agent.FamilyGiven()
+
+	return Name{Family: family, Given: given}
+}
+
+// issued converts the first of a node's MODS dates into a CSL date.
+// Start and end points become an interval, qualified dates are circa,
+// and dates without a known year are a literal.
+func issued(dates []mods.Date) *Date {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	values := []string{dates[0].Value}
+	if dates[0].Point == "start" && len(dates) > 1 && dates[1].Point == "end" {
+		values = append(values, dates[1].Value)
+	}
+	d := &Date{}
+	for _, value := range values {
+		parsed, err := edtf.Parse(value)
+		if err != nil {
+			return &Date{Literal: dates[0].Value}
+		}
+		date := parsed.Start
+		if parsed.Kind == edtf.KindSet && len(parsed.Dates) > 0 {
+			date = parsed.Dates[0]
+		}
+		parts := dateParts(date)
+		if parts == nil {
+			return &Date{Literal: value}
+		}
+		d.DateParts = append(d.DateParts, parts)
+		d.Circa = d.Circa || date.Approximate || date.Uncertain
+	}
+
+	return d
+}
+
+func dateParts(d edtf.Date) []int {
+	year, ok := d.YearInt()
+	if !ok {
+		return nil
+	}
+	parts := []int{year}
+	month, err := strconv.Atoi(d.Month)
+	if err != nil || month > 12 {
+		return parts
+	}
+	parts = append(parts, month)
+	day, err := strconv.Atoi(d.Day)
+	if err != nil {
+		return parts
+	}
+
+	return append(parts, day)
+}
+
+// Year returns the year the item was issued, empty when unknown
+func (i *Item) Year() string {
+	if i.Issued == nil || len(i.Issued.DateParts) == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(i.Issued.DateParts[0][0])
+}
+
+// String writes a name as "Family, Given"
+func (n Name) String() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	if n.Given == "" {
+		return n.Family
+	}
+
+	return n.Family + ", " + n.Given
+}
+
+// CslJson writes items as a CSL-JSON array
+func CslJson(items []*Item) ([]byte, error) {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
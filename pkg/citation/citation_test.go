@@ -0,0 +1,144 @@
+package citation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var terms = islandora.MapTerms{
+	1: islandora.NewTerm("person", "Gödel, Kurt"),
+	2: islandora.NewTerm("person", "Smith, Ann"),
+	3: islandora.NewTerm("corporate_body", "Lehigh University"),
+	4: islandora.NewTerm("genre", "Journal articles"),
+	5: islandora.NewTerm("subject_lcsh", "Bethlehem (Pa.), History"),
+	6: islandora.NewTerm("subject_lcsh", "Steel industry"),
+}
+
+const testNode = `{
+	"nid": [{"value": 7}],
+	"title": [{"value": "Über formal unentscheidbare Sätze & more"}],
+	"field_genre": [{"target_id": 4, "target_type": "taxonomy_term"}],
+	"field_linked_agent": [
+		{"target_id": 1, "rel_type": "relators:aut"},
+		{"target_id": 3, "rel_type": "relators:aut"},
+		{"target_id": 2, "rel_type": "relators:edt"}
+	],
+	"field_edtf_date_issued": [{"value": "1931-03~"}],
+	"field_identifier": [{"attr0": "doi", "value": "10.1007/BF01700692"}],
+	"field_related_item": [{"title": "Monatshefte für Mathematik", "identifier": "0026-9255"}],
+	"field_part_detail": [{"type": "volume", "number": "38"}, {"type": "issue", "number": "1"}, {"type": "page", "number": "173-198"}]
+}`
+
+func testItem(t *testing.T) *Item {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+
+	item, err := FromNode(&node, Options{Terms: terms, BaseUrl: "https://example.com"})
+	require.NoError(t, err)
+
+	return item
+}
+
+func TestFromNode(t *testing.T) {
+	item := testItem(t)
+	assert.Equal(t, &Item{
+		Id:             "7",
+		Type:           "article-journal",
+		Title:          "Über formal unentscheidbare Sätze & more",
+		Author:         []Name{{Family: "Gödel", Given: "Kurt"}, {Literal: "Lehigh University"}},
+		Editor:         []Name{{Family: "Smith", Given: "Ann"}},
+		Issued:         &Date{DateParts: [][]int{{1931, 3}}, Circa: true},
+		ContainerTitle: "Monatshefte für Mathematik",
+		Volume:         "38",
+		Issue:          "1",
+		Page:           "173-198",
+		Doi:            "10.1007/BF01700692",
+		Url:            "https://example.com/node/7",
+		Issn:           "0026-9255",
+	}, item)
+
+	assert.Equal(t, &Date{DateParts: [][]int{{1950}, {1960, 5, 1}}}, issued([]mods.Date{{Point: "start", Value: "1950"}, {Point: "end", Value: "1960-05-01"}}))
+	assert.Equal(t, &Date{Literal: "19XX"}, issued([]mods.Date{{Value: "19XX"}}))
+}
+
+func TestBibtex(t *testing.T) {
+	item := testItem(t)
+	assert.Equal(t, `@article{godel1931uber,
+  author = {G{\"o}del, Kurt and {Lehigh University}},
+  editor = {Smith, Ann},
+  title = {{\"U}ber formal unentscheidbare S{\"a}tze \& more},
+  journal = {Monatshefte f{\"u}r Mathematik},
+  year = {1931},
+  month = {3},
+  volume = {38},
+  number = {1},
+  pages = {173--198},
+  issn = {0026-9255},
+  doi = {10.1007/BF01700692},
+  url = {https://example.com/node/7},
+}
+
+`, string(Bibtex([]*Item{item}, false)))
+	assert.Contains(t, string(Bibtex([]*Item{item, item}, false)), "@article{godel1931ubera,")
+
+	assert.Equal(t, `G{\"o}del {\ss} {\v{c}}{\'\i} \_ -- x`, BibtexEscape("Gödel ß čí _ – x", false))
+	assert.Equal(t, `Gödel ß \_ – x`, BibtexEscape("Gödel ß _ – x", true))
+}
+
+func TestRis(t *testing.T) {
+	assert.Equal(t, "TY  - JOUR\r\n"+
+		"AU  - Gödel, Kurt\r\n"+
+		"AU  - Lehigh University\r\n"+
+		"ED  - Smith, Ann\r\n"+
+		"TI  - Über formal unentscheidbare Sätze & more\r\n"+
+		"T2  - Monatshefte für Mathematik\r\n"+
+		"PY  - 1931\r\n"+
+		"DA  - 1931/03//circa\r\n"+
+		"VL  - 38\r\n"+
+		"IS  - 1\r\n"+
+		"SP  - 173\r\n"+
+		"EP  - 198\r\n"+
+		"SN  - 0026-9255\r\n"+
+		"DO  - 10.1007/BF01700692\r\n"+
+		"UR  - https://example.com/node/7\r\n"+
+		"ER  - \r\n\r\n", string(Ris([]*Item{testItem(t)})))
+}
+
+func TestKeywords(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"nid": [{"value": 8}],
+		"title": [{"value": "Bethlehem Steel"}],
+		"field_lcsh_topic": [{"target_id": 5, "target_type": "taxonomy_term"}, {"target_id": 6, "target_type": "taxonomy_term"}]
+	}`), &node))
+
+	item, err := FromNode(&node, Options{Terms: terms})
+	require.NoError(t, err)
+	assert.Equal(t, "Bethlehem (Pa.), History, Steel industry", item.Keyword)
+	assert.Contains(t, string(Bibtex([]*Item{item}, false)), "keywords = {Bethlehem (Pa.), History, Steel industry},")
+
+	// a heading with a comma stays one keyword
+	ris := string(Ris([]*Item{item}))
+	assert.Contains(t, ris, "KW  - Bethlehem (Pa.), History\r\nKW  - Steel industry\r\n")
+
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Keywords")
+}
+
+func TestFromNodeWithoutTerms(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+
+	item, err := FromNode(&node, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "Über formal unentscheidbare Sätze & more", item.Title)
+	assert.Empty(t, item.Author)
+	assert.Empty(t, item.Editor)
+}
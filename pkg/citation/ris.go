@@ -0,0 +1,100 @@
+package citation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RisTypes maps CSL item types to RIS reference types, others are GEN
+var RisTypes = map[string]string{
+	"article-journal":  "JOUR",
+	"book":             "BOOK",
+	"chapter":          "CHAP",
+	"dataset":          "DATA",
+	"graphic":          "ART",
+	"interview":        "PCOMM",
+	"manuscript":       "MANSCPT",
+	"map":              "MAP",
+	"motion_picture":   "VIDEO",
+	"paper-conference": "CPAPER",
+	"periodical":       "JFULL",
+	"report":           "RPRT",
+	"song":             "SOUND",
+	"thesis":           "THES",
+}
+
+// Ris writes items as RIS records
+func Ris(items []*Item) []byte {
+	var b strings.Builder
+	for _, item := range items {
+		risType, ok := RisTypes[item.Type]
+		if !ok {
+			risType = "GEN"
+		}
+
+		tags := [][2]string{{"TY", risType}}
+		for _, author := range item.Author {
+			tags = append(tags, [2]string{"AU", author.String()})
+		}
+		for _, editor := range item.Editor {
+			tags = append(tags, [2]string{"ED", editor.String()})
+		}
+		tags = append(tags,
+			[2]string{"TI", item.Title},
+			[2]string{"T2", item.ContainerTitle},
+			[2]string{"PY", item.Year()},
+			[2]string{"DA", risDate(item.Issued)},
+			[2]string{"VL", item.Volume},
+			[2]string{"IS", item.Issue},
+		)
+		first, last, _ := strings.Cut(item.Page, "-")
+		tags = append(tags,
+			[2]string{"SP", first},
+			[2]string{"EP", last},
+			[2]string{"PB", item.Publisher},
+			[2]string{"CY", item.PublisherPlace},
+			[2]string{"M3", item.Genre},
+			[2]string{"SN", item.Issn},
+			[2]string{"SN", item.Isbn},
+			[2]string{"DO", item.Doi},
+			[2]string{"UR", item.Url},
+			[2]string{"AB", item.Abstract},
+			[2]string{"LA", item.Language},
+		)
+		for _, keyword := range item.Keywords {
+			tags = append(tags, [2]string{"KW", keyword})
+		}
+
+		for _, tag := range tags {
+			if tag[1] != "" {
+				// values can't span lines
+				value := strings.Join(strings.Fields(tag[1]), " ")
+				fmt.Fprintf(&b, "%s  - %s\r\n", tag[0], value)
+			}
+		}
+		b.WriteString("ER  - \r\n\r\n")
+	}
+
+	return []byte(b.String())
+}
+
+// risDate writes the issued date as YYYY/MM/DD/, the last part noting circa dates
+func risDate(d *Date) string {
+	if d == nil || len(d.DateParts) == 0 {
+		return ""
+	}
+
+	parts := []string{"", "", "", ""}
+	for i, part := range d.DateParts[0] {
+		if i == 0 {
+			parts[i] = fmt.Sprintf("%04d", part)
+		} else {
+			parts[i] = fmt.Sprintf("%02d", part)
+		}
+	}
+	if d.Circa {
+		parts[3] = "circa"
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,372 @@
+package datacite
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+type Options struct {
+	// Mapping is the MODS mapping the node's fields are read through
+	Mapping *mods.Mapping
+	// Terms resolves taxonomy terms, linked agents are skipped when nil
+	Terms islandora.TermLookup
+	// BaseUrl is used for the landing page URL
+	BaseUrl string
+	// Publisher is used when the node has no publisher
+	Publisher string
+	// Prefix is sent instead of a DOI for nodes without one,
+	// so DataCite generates the DOI's suffix
+	Prefix string
+}
+
+// ContributorTypes maps MARC relator codes to DataCite contributorType.
+// Relators not listed are contributors of type Other,
+// aut and cre are creators and pbl is the publisher.
+var ContributorTypes = map[string]string{
+	"cur": "DataCurator",
+	"col": "DataCollector",
+	"dgg": "HostingInstitution",
+	"dgs": "Supervisor",
+	"dst": "Distributor",
+	"dtm": "DataManager",
+	"edt": "Editor",
+	"his": "HostingInstitution",
+	"own": "RightsHolder",
+	"cph": "RightsHolder",
+	"pdr": "ProjectLeader",
+	"pro": "Producer",
+	"res": "Researcher",
+	"rth": "ProjectLeader",
+	"spn": "Sponsor",
+	"fnd": "Sponsor",
+	"ths": "Supervisor",
+}
+
+// ResourceTypes maps field_resource_type term names to resourceTypeGeneral
+var ResourceTypes = map[string]string{
+	"collection":           "Collection",
+	"dataset":              "Dataset",
+	"event":                "Event",
+	"image":                "Image",
+	"interactive resource": "InteractiveResource",
+	"moving image":         "Audiovisual",
+	"physical object":      "PhysicalObject",
+	"service":              "Service",
+	"software":             "Software",
+	"sound":                "Sound",
+	"still image":          "Image",
+	"text":                 "Text",
+}
+
+var (
+	issnRegex = regexp.MustCompile(`^[0-9]{4}-?[0-9]{3}[0-9Xx]$`)
+	isbnRegex = regexp.MustCompile(`^(97[89][- ]?)?[0-9][0-9- ]{8,}[0-9Xx]$`)
+)
+
+// FromNode crosswalks a node into a DataCite resource.
+// Most properties come from the node's MODS record,
+// creators and contributors from its linked agents so ORCIDs and affiliations are kept.
+func FromNode(node *api.IslandoraObject, opts Options) (*Resource, error) {
+	record, err := mods.FromNode(node, mods.Options{
+		Mapping: opts.Mapping,
+		Terms:   opts.Terms,
+		BaseUrl: opts.BaseUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Resource{
+		Identifier: Identifier{IdentifierType: "DOI", Value: islandora.Doi(node)},
+		Url:        nodeUrl(node, opts.BaseUrl),
+	}
+	if r.Identifier.Value == "" {
+		r.Prefix = opts.Prefix
+	}
+
+	publisher := ""
+	if opts.Terms != nil {
+		agents, err := islandora.LinkedAgents(node, opts.Terms)
+		if err != nil {
+			return nil, err
+		}
+		for _, agent := range agents {
+			switch agent.Relator {
+			case "aut", "cre":
+				c := Creator{CreatorName: name(agent), NameIdentifiers: nameIdentifiers(agent), Affiliations: agent.Affiliations}
+				if agent.Personal() {
+					c.FamilyName, c.GivenName = agent.FamilyGiven()
+				}
+				r.Creators = append(r.Creators, c)
+			case "pbl":
+				if publisher == "" {
+					publisher = agent.Name
+				}
+			default:
+				t, ok := ContributorTypes[agent.Relator]
+				if !ok {
+					t = "Other"
+				}
+				c := Contributor{ContributorType: t, ContributorName: name(agent), NameIdentifiers: nameIdentifiers(agent), Affiliations: agent.Affiliations}
+				if agent.Personal() {
+					c.FamilyName, c.GivenName = agent.FamilyGiven()
+				}
+				r.Contributors = append(r.Contributors, c)
+			}
+		}
+	}
+
+	for _, t := range record.TitleInfo {
+		title := Title{Value: t.Title}
+		if t.SubTitle != "" {
+			title.Value += ": " + t.SubTitle
+		}
+		switch t.Type {
+		case "":
+		case "alternative":
+			title.TitleType = "AlternativeTitle"
+		case "translated":
+			title.TitleType = "TranslatedTitle"
+		default:
+			title.TitleType = "Other"
+		}
+		r.Titles = append(r.Titles, title)
+	}
+
+	var issued, created, copyright []mods.Date
+	for _, origin := range record.OriginInfo {
+		for _, p := range origin.Publisher {
+			if r.Publisher == "" {
+				r.Publisher = p.Value
+			}
+		}
+		issued = append(issued, origin.DateIssued...)
+		created = append(created, origin.DateCreated...)
+		copyright = append(copyright, origin.CopyrightDate...)
+		for _, list := range []struct {
+			dateType string
+			dates    []mods.Date
+		}{
+			{"Issued", origin.DateIssued},
+			{"Created", origin.DateCreated},
+			{"Collected", origin.DateCaptured},
+			{"Copyrighted", origin.CopyrightDate},
+			{"Valid", origin.DateValid},
+			{"Updated", origin.DateModified},
+			{"Other", origin.DateOther},
+		} {
+			for _, value := range dates(list.dates) {
+				r.Dates = append(r.Dates, Date{DateType: list.dateType, Value: value})
+			}
+		}
+	}
+	if r.Publisher == "" {
+		r.Publisher = publisher
+	}
+	if r.Publisher == "" {
+		r.Publisher = opts.Publisher
+	}
+	for _, list := range [][]mods.Date{issued, created, copyright} {
+		if year := publicationYear(list); year != "" {
+			r.PublicationYear = year
+			break
+		}
+	}
+
+	r.ResourceType = ResourceType{ResourceTypeGeneral: "Other"}
+	if len(record.TypeOfResource) > 0 {
+		t := record.TypeOfResource[0].Value
+		r.ResourceType.Value = t
+		if general, ok := ResourceTypes[strings.ToLower(t)]; ok {
+			r.ResourceType.ResourceTypeGeneral = general
+		}
+	}
+	if len(record.Genre) > 0 {
+		r.ResourceType.Value = record.Genre[0].Value
+	}
+
+	for _, s := range record.Subject {
+		texts := slices.Concat(s.Topic, s.Geographic, s.Temporal, s.Genre)
+		for _, n := range s.Name {
+			for _, part := range n.NamePart {
+				texts = append(texts, mods.Text{Authority: n.Authority, Value: part.Value})
+			}
+		}
+		for _, t := range texts {
+			scheme := t.Authority
+			if scheme == "" {
+				scheme = s.Authority
+			}
+			r.Subjects = append(r.Subjects, Subject{SubjectScheme: scheme, Value: t.Value})
+		}
+	}
+
+	for _, l := range record.Language {
+		if r.Language != "" {
+			break
+		}
+		for _, t := range l.LanguageTerm {
+			if t.Type == "code" {
+				r.Language = t.Value
+				break
+			}
+		}
+		if r.Language == "" && len(l.LanguageTerm) > 0 {
+			r.Language = l.LanguageTerm[0].Value
+		}
+	}
+
+	for _, id := range record.Identifier {
+		switch strings.ToLower(id.Type) {
+		case "doi":
+			continue
+		case "uri":
+			r.AlternateIdentifiers = append(r.AlternateIdentifiers, AlternateIdentifier{AlternateIdentifierType: "URL", Value: id.Value})
+		case "":
+			r.AlternateIdentifiers = append(r.AlternateIdentifiers, AlternateIdentifier{AlternateIdentifierType: "Local", Value: id.Value})
+		default:
+			r.AlternateIdentifiers = append(r.AlternateIdentifiers, AlternateIdentifier{AlternateIdentifierType: id.Type, Value: id.Value})
+		}
+	}
+
+	for _, item := range record.RelatedItem {
+		relation := "References"
+		switch item.Type {
+		case "host", "series":
+			relation = "IsPartOf"
+		case "constituent":
+			relation = "HasPart"
+		case "preceding":
+			relation = "Continues"
+		case "succeeding":
+			relation = "IsContinuedBy"
+		case "otherVersion":
+			relation = "IsVersionOf"
+		case "otherFormat":
+			relation = "IsVariantFormOf"
+		}
+		for _, id := range item.Identifier {
+			if t := identifierType(id.Value); t != "" {
+				r.RelatedIdentifiers = append(r.RelatedIdentifiers, RelatedIdentifier{RelatedIdentifierType: t, RelationType: relation, Value: id.Value})
+			}
+		}
+	}
+
+	for _, a := range record.AccessCondition {
+		rights := Rights{Value: a.Value}
+		if strings.HasPrefix(a.Value, "http://") || strings.HasPrefix(a.Value, "https://") {
+			rights = Rights{RightsURI: a.Value}
+		}
+		r.RightsList = append(r.RightsList, rights)
+	}
+
+	for _, list := range []struct {
+		descriptionType string
+		texts           []mods.Text
+	}{
+		{"Abstract", record.Abstract},
+		{"TableOfContents", record.TableOfContents},
+		{"Other", record.Note},
+	} {
+		for _, t := range list.texts {
+			r.Descriptions = append(r.Descriptions, Description{DescriptionType: list.descriptionType, Value: t.Value})
+		}
+	}
+
+	return r, nil
+}
+
+func name(agent islandora.Agent) Name {
+	if agent.Personal() {
+		family, given := agent.FamilyGiven()
+		if given == "" {
+			return Name{NameType: "Personal", Value: family}
+		}
+		return Name{NameType: "Personal", Value: family + ", " + given}
+	}
+
+	return Name{NameType: "Organizational", Value: agent.Name}
+}
+
+func nameIdentifiers(agent islandora.Agent) []NameIdentifier {
+	if agent.Orcid == "" {
+		return nil
+	}
+
+	return []NameIdentifier{{NameIdentifierScheme: "ORCID", SchemeURI: "https://orcid.org", Value: agent.OrcidUrl()}}
+}
+
+// dates converts MODS dates into RKMS-ISO8601 values,
+// joining start and end points into intervals and dropping EDTF qualifiers
+func dates(list []mods.Date) []string {
+	values := []string{}
+	for i := 0; i < len(list); i++ {
+		d := list[i]
+		value := strings.TrimRight(d.Value, "~?%")
+		if strings.Contains(value, "X") {
+			continue
+		}
+		switch d.Point {
+		case "start":
+			end := ""
+			if i+1 < len(list) && list[i+1].Point == "end" {
+				end = strings.TrimRight(list[i+1].Value, "~?%")
+				i++
+			}
+			value += "/" + end
+		case "end":
+			value = "/" + value
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func publicationYear(list []mods.Date) string {
+	for _, d := range list {
+		parsed, err := edtf.Parse(d.Value)
+		if err != nil {
+			continue
+		}
+		date := parsed.Start
+		if parsed.Kind == edtf.KindSet && len(parsed.Dates) > 0 {
+			date = parsed.Dates[0]
+		}
+		if year, ok := date.YearInt(); ok {
+			return fmt.Sprintf("%04d", year)
+		}
+	}
+
+	return ""
+}
+
+func identifierType(id string) string {
+	switch {
+	case doiRegex.MatchString(id):
+		return "DOI"
+	case strings.HasPrefix(id, "http://") || strings.HasPrefix(id, "https://"):
+		return "URL"
+	case issnRegex.MatchString(id):
+		return "ISSN"
+	case isbnRegex.MatchString(id):
+		return "ISBN"
+	}
+
+	return ""
+}
+
+func nodeUrl(node *api.IslandoraObject, baseUrl string) string {
+	if baseUrl == "" || node.Nid == nil || len(*node.Nid) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/node/%d", strings.TrimSuffix(baseUrl, "/"), (*node.Nid)[0].Value)
+}
@@ -0,0 +1,205 @@
+// Package datacite crosswalks Islandora nodes into DataCite Metadata Schema 4.x
+// as XML, or as the attributes of the DataCite REST API
+package datacite
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+const (
+	Namespace      = "http://datacite.org/schema/kernel-4"
+	SchemaLocation = "http://datacite.org/schema/kernel-4 http://schema.datacite.org/meta/kernel-4.5/metadata.xsd"
+	xsiNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// ResourceTypesGeneral are the resourceTypeGeneral values allowed by the schema
+var ResourceTypesGeneral = []string{
+	"Audiovisual", "Book", "BookChapter", "Collection", "ComputationalNotebook",
+	"ConferencePaper", "ConferenceProceeding", "DataPaper", "Dataset", "Dissertation",
+	"Event", "Image", "Instrument", "InteractiveResource", "Journal", "JournalArticle",
+	"Model", "OutputManagementPlan", "PeerReview", "PhysicalObject", "Preprint",
+	"Report", "Service", "Software", "Sound", "Standard", "StudyRegistration",
+	"Text", "Workflow", "Other",
+}
+
+type Resource struct {
+	XMLName        xml.Name `xml:"http://datacite.org/schema/kernel-4 resource"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr,omitempty"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr,omitempty"`
+
+	Identifier           Identifier            `xml:"identifier"`
+	Creators             []Creator             `xml:"creators>creator"`
+	Titles               []Title               `xml:"titles>title"`
+	Publisher            string                `xml:"publisher"`
+	PublicationYear      string                `xml:"publicationYear"`
+	ResourceType         ResourceType          `xml:"resourceType"`
+	Subjects             []Subject             `xml:"subjects>subject"`
+	Contributors         []Contributor         `xml:"contributors>contributor"`
+	Dates                []Date                `xml:"dates>date"`
+	Language             string                `xml:"language,omitempty"`
+	AlternateIdentifiers []AlternateIdentifier `xml:"alternateIdentifiers>alternateIdentifier"`
+	RelatedIdentifiers   []RelatedIdentifier   `xml:"relatedIdentifiers>relatedIdentifier"`
+	RightsList           []Rights              `xml:"rightsList>rights"`
+	Descriptions         []Description         `xml:"descriptions>description"`
+
+	// Url is the landing page, only part of the REST API attributes
+	Url string `xml:"-"`
+	// Prefix mints a new DOI when there is no identifier, only part of the REST API attributes
+	Prefix string `xml:"-"`
+}
+
+type Identifier struct {
+	IdentifierType string `xml:"identifierType,attr"`
+	Value          string `xml:",chardata"`
+}
+
+type Name struct {
+	NameType string `xml:"nameType,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type NameIdentifier struct {
+	NameIdentifierScheme string `xml:"nameIdentifierScheme,attr"`
+	SchemeURI            string `xml:"schemeURI,attr,omitempty"`
+	Value                string `xml:",chardata"`
+}
+
+type Creator struct {
+	CreatorName     Name             `xml:"creatorName"`
+	GivenName       string           `xml:"givenName,omitempty"`
+	FamilyName      string           `xml:"familyName,omitempty"`
+	NameIdentifiers []NameIdentifier `xml:"nameIdentifier"`
+	Affiliations    []string         `xml:"affiliation"`
+}
+
+type Contributor struct {
+	ContributorType string           `xml:"contributorType,attr"`
+	ContributorName Name             `xml:"contributorName"`
+	GivenName       string           `xml:"givenName,omitempty"`
+	FamilyName      string           `xml:"familyName,omitempty"`
+	NameIdentifiers []NameIdentifier `xml:"nameIdentifier"`
+	Affiliations    []string         `xml:"affiliation"`
+}
+
+type Title struct {
+	TitleType string `xml:"titleType,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+type ResourceType struct {
+	ResourceTypeGeneral string `xml:"resourceTypeGeneral,attr"`
+	Value               string `xml:",chardata"`
+}
+
+type Subject struct {
+	SubjectScheme string `xml:"subjectScheme,attr,omitempty"`
+	Value         string `xml:",chardata"`
+}
+
+type Date struct {
+	DateType string `xml:"dateType,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type AlternateIdentifier struct {
+	AlternateIdentifierType string `xml:"alternateIdentifierType,attr"`
+	Value                   string `xml:",chardata"`
+}
+
+type RelatedIdentifier struct {
+	RelatedIdentifierType string `xml:"relatedIdentifierType,attr"`
+	RelationType          string `xml:"relationType,attr"`
+	Value                 string `xml:",chardata"`
+}
+
+type Rights struct {
+	RightsURI string `xml:"rightsURI,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+type Description struct {
+	DescriptionType string `xml:"descriptionType,attr"`
+	Value           string `xml:",chardata"`
+}
+
+var (
+	doiRegex    = regexp.MustCompile(`^10\.[0-9]{4,}(\.[0-9]+)*/\S+$`)
+	prefixRegex = regexp.MustCompile(`^10\.[0-9]{4,}(\.[0-9]+)*$`)
+	yearRegex   = regexp.MustCompile(`^[0-9]{4}$`)
+)
+
+// Validate returns the required properties the resource is missing or has invalid.
+// Without requireDoi a resource with a prefix instead of a DOI is valid,
+// for REST API requests that have DataCite generate the DOI.
+func Validate(r *Resource, requireDoi bool) []string {
+	problems := []string{}
+	if r.Identifier.Value == "" {
+		if requireDoi || r.Prefix == "" {
+			problems = append(problems, "missing identifier (DOI)")
+		} else if !prefixRegex.MatchString(r.Prefix) {
+			problems = append(problems, fmt.Sprintf("invalid DOI prefix %q", r.Prefix))
+		}
+	} else if !doiRegex.MatchString(r.Identifier.Value) {
+		problems = append(problems, fmt.Sprintf("invalid DOI %q", r.Identifier.Value))
+	}
+	if len(r.Creators) == 0 {
+		problems = append(problems, "missing creator")
+	}
+	for i, c := range r.Creators {
+		if c.CreatorName.Value == "" {
+			problems = append(problems, fmt.Sprintf("creator %d has no name", i+1))
+		}
+	}
+	if len(r.Titles) == 0 || r.Titles[0].Value == "" {
+		problems = append(problems, "missing title")
+	}
+	if r.Publisher == "" {
+		problems = append(problems, "missing publisher")
+	}
+	if !yearRegex.MatchString(r.PublicationYear) {
+		problems = append(problems, fmt.Sprintf("invalid publicationYear %q", r.PublicationYear))
+	}
+	if !slices.Contains(ResourceTypesGeneral, r.ResourceType.ResourceTypeGeneral) {
+		problems = append(problems, fmt.Sprintf("invalid resourceTypeGeneral %q", r.ResourceType.ResourceTypeGeneral))
+	}
+
+	return problems
+}
+
+// Marshal encodes the resource as XML
+func Marshal(r *Resource) ([]byte, error) {
+	root := *r
+	root.XmlnsXsi = xsiNamespace
+	root.SchemaLocation = SchemaLocation
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(root)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the resource as a DataCite REST API request body.
+// event is publish, register or hide, a draft DOI is created when empty.
+func MarshalJSON(r *Resource, event string) ([]byte, error) {
+	attributes := Attributes(r)
+	attributes.Event = event
+
+	return json.MarshalIndent(map[string]any{
+		"data": map[string]any{
+			"type":       "dois",
+			"attributes": attributes,
+		},
+	}, "", "  ")
+}
@@ -0,0 +1,139 @@
+package datacite
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTermLookup() islandora.MapTerms {
+	terms := islandora.MapTerms{
+		2: islandora.NewTerm("corporate_body", "Lehigh University"),
+		3: islandora.NewTerm("person", "Smith, Ann"),
+		4: islandora.NewTerm("resource_types", "Dataset"),
+		5: islandora.NewTerm("genre", "Survey data"),
+		6: islandora.NewTerm("subject", "Steel industry"),
+	}
+	doe := islandora.NewTerm("person", "Doe, Jane - Lehigh University")
+	doe.Identifier = model.TypedTextField{{Attr0: "orcid", Value: "https://orcid.org/0000-0002-1825-0097"}}
+	doe.Relationships = model.TypedRelationField{{RelType: "schema:worksFor", TargetId: 2}}
+	terms[1] = doe
+
+	return terms
+}
+
+const testNode = `{
+	"nid": [{"value": 42}],
+	"title": [{"value": "Steel survey"}],
+	"field_alt_title": [{"value": "Survey of steel"}],
+	"field_linked_agent": [
+		{"target_id": 1, "rel_type": "relators:cre"},
+		{"target_id": 2, "rel_type": "relators:pbl"},
+		{"target_id": 3, "rel_type": "relators:edt"}
+	],
+	"field_resource_type": [{"target_id": 4, "target_type": "taxonomy_term"}],
+	"field_genre": [{"target_id": 5, "target_type": "taxonomy_term"}],
+	"field_lcsh_topic": [{"target_id": 6, "target_type": "taxonomy_term"}],
+	"field_edtf_date_issued": [{"value": "2021-03~"}],
+	"field_edtf_date_created": [{"value": "2019/2020"}],
+	"field_identifier": [{"attr0": "doi", "value": "https://doi.org/10.1234/steel"}],
+	"field_related_item": [{"title": "Journal of Things", "identifier": "1234-5678"}],
+	"field_rights": [{"value": "https://creativecommons.org/licenses/by/4.0/"}],
+	"field_abstract": [{"value": "Responses from 1950s mill workers"}]
+}`
+
+func TestFromNode(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+
+	r, err := FromNode(&node, Options{Terms: testTermLookup(), BaseUrl: "https://example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, Validate(r, true))
+
+	assert.Equal(t, Identifier{IdentifierType: "DOI", Value: "10.1234/steel"}, r.Identifier)
+	assert.Equal(t, "https://example.com/node/42", r.Url)
+	assert.Equal(t, []Creator{{
+		CreatorName:     Name{NameType: "Personal", Value: "Doe, Jane"},
+		GivenName:       "Jane",
+		FamilyName:      "Doe",
+		NameIdentifiers: []NameIdentifier{{NameIdentifierScheme: "ORCID", SchemeURI: "https://orcid.org", Value: "https://orcid.org/0000-0002-1825-0097"}},
+		Affiliations:    []string{"Lehigh University"},
+	}}, r.Creators)
+	require.Len(t, r.Contributors, 1)
+	assert.Equal(t, "Editor", r.Contributors[0].ContributorType)
+	assert.Equal(t, "Lehigh University", r.Publisher)
+	assert.Equal(t, "2021", r.PublicationYear)
+	assert.Equal(t, ResourceType{ResourceTypeGeneral: "Dataset", Value: "Survey data"}, r.ResourceType)
+	assert.Equal(t, []Title{{Value: "Steel survey"}, {TitleType: "AlternativeTitle", Value: "Survey of steel"}}, r.Titles)
+	assert.Equal(t, []Date{{DateType: "Issued", Value: "2021-03"}, {DateType: "Created", Value: "2019/2020"}}, r.Dates)
+	assert.Equal(t, []RelatedIdentifier{{RelatedIdentifierType: "ISSN", RelationType: "IsPartOf", Value: "1234-5678"}}, r.RelatedIdentifiers)
+	assert.Equal(t, []Rights{{RightsURI: "https://creativecommons.org/licenses/by/4.0/"}}, r.RightsList)
+	assert.Equal(t, []Description{{DescriptionType: "Abstract", Value: "Responses from 1950s mill workers"}}, r.Descriptions)
+	assert.Equal(t, []AlternateIdentifier{{AlternateIdentifierType: "URL", Value: "https://example.com/node/42"}}, r.AlternateIdentifiers)
+
+	data, err := MarshalJSON(r, "publish")
+	require.NoError(t, err)
+	var body struct {
+		Data struct {
+			Type       string         `json:"type"`
+			Attributes JsonAttributes `json:"attributes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, "dois", body.Data.Type)
+	assert.Equal(t, "publish", body.Data.Attributes.Event)
+	assert.Equal(t, []JsonAffiliation{{Name: "Lehigh University"}}, body.Data.Attributes.Creators[0].Affiliation)
+	assert.Equal(t, "Dataset", body.Data.Attributes.Types.ResourceTypeGeneral)
+
+	xml, err := Marshal(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(xml), `<creatorName nameType="Personal">Doe, Jane</creatorName>`)
+	assert.Contains(t, string(xml), `<resourceType resourceTypeGeneral="Dataset">Survey data</resourceType>`)
+}
+
+func TestValidate(t *testing.T) {
+	problems := Validate(&Resource{
+		Identifier:      Identifier{Value: "doi:10.1234/x"},
+		Titles:          []Title{{Value: "A title"}},
+		PublicationYear: "19XX",
+	}, true)
+	assert.Equal(t, []string{
+		`invalid DOI "doi:10.1234/x"`,
+		"missing creator",
+		"missing publisher",
+		`invalid publicationYear "19XX"`,
+		`invalid resourceTypeGeneral ""`,
+	}, problems)
+}
+
+func TestPrefix(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(strings.Replace(testNode, `"attr0": "doi"`, `"attr0": "hdl"`, 1)), &node))
+
+	r, err := FromNode(&node, Options{Terms: testTermLookup(), BaseUrl: "https://example.com", Prefix: "10.1234"})
+	require.NoError(t, err)
+	assert.Empty(t, Validate(r, false))
+	assert.Equal(t, []string{"missing identifier (DOI)"}, Validate(r, true))
+
+	attributes := Attributes(r)
+	assert.Empty(t, attributes.Doi)
+	assert.Equal(t, "10.1234", attributes.Prefix)
+
+	r.Prefix = "10.1234/x"
+	assert.Equal(t, []string{`invalid DOI prefix "10.1234/x"`}, Validate(r, false))
+
+	// nodes with a DOI keep it
+	var doiNode api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &doiNode))
+	r, err = FromNode(&doiNode, Options{Terms: testTermLookup(), Prefix: "10.1234"})
+	require.NoError(t, err)
+	attributes = Attributes(r)
+	assert.Equal(t, "10.1234/steel", attributes.Doi)
+	assert.Empty(t, attributes.Prefix)
+}
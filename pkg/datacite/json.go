@@ -0,0 +1,162 @@
+package datacite
+
+// JSON shapes of the DataCite REST API, see https://support.datacite.org/reference/post_dois
+
+type JsonAttributes struct {
+	Event                string                    `json:"event,omitempty"`
+	Doi                  string                    `json:"doi,omitempty"`
+	Prefix               string                    `json:"prefix,omitempty"`
+	Url                  string                    `json:"url,omitempty"`
+	Creators             []JsonName                `json:"creators"`
+	Titles               []JsonTitle               `json:"titles"`
+	Publisher            JsonPublisher             `json:"publisher"`
+	PublicationYear      string                    `json:"publicationYear"`
+	Types                JsonTypes                 `json:"types"`
+	Subjects             []JsonSubject             `json:"subjects,omitempty"`
+	Contributors         []JsonName                `json:"contributors,omitempty"`
+	Dates                []JsonDate                `json:"dates,omitempty"`
+	Language             string                    `json:"language,omitempty"`
+	AlternateIdentifiers []JsonAlternateIdentifier `json:"alternateIdentifiers,omitempty"`
+	RelatedIdentifiers   []JsonRelatedIdentifier   `json:"relatedIdentifiers,omitempty"`
+	RightsList           []JsonRights              `json:"rightsList,omitempty"`
+	Descriptions         []JsonDescription         `json:"descriptions,omitempty"`
+	SchemaVersion        string                    `json:"schemaVersion"`
+}
+
+type JsonName struct {
+	Name            string               `json:"name"`
+	NameType        string               `json:"nameType,omitempty"`
+	GivenName       string               `json:"givenName,omitempty"`
+	FamilyName      string               `json:"familyName,omitempty"`
+	ContributorType string               `json:"contributorType,omitempty"`
+	NameIdentifiers []JsonNameIdentifier `json:"nameIdentifiers,omitempty"`
+	Affiliation     []JsonAffiliation    `json:"affiliation,omitempty"`
+}
+
+type JsonNameIdentifier struct {
+	NameIdentifier       string `json:"nameIdentifier"`
+	NameIdentifierScheme string `json:"nameIdentifierScheme"`
+	SchemeUri            string `json:"schemeUri,omitempty"`
+}
+
+type JsonAffiliation struct {
+	Name string `json:"name"`
+}
+
+type JsonTitle struct {
+	Title     string `json:"title"`
+	TitleType string `json:"titleType,omitempty"`
+}
+
+type JsonPublisher struct {
+	Name string `json:"name"`
+}
+
+type JsonTypes struct {
+	ResourceTypeGeneral string `json:"resourceTypeGeneral"`
+	ResourceType        string `json:"resourceType,omitempty"`
+}
+
+type JsonSubject struct {
+	Subject       string `json:"subject"`
+	SubjectScheme string `json:"subjectScheme,omitempty"`
+}
+
+type JsonDate struct {
+	Date     string `json:"date"`
+	DateType string `json:"dateType"`
+}
+
+type JsonAlternateIdentifier struct {
+	AlternateIdentifier     string `json:"alternateIdentifier"`
+	AlternateIdentifierType string `json:"alternateIdentifierType"`
+}
+
+type JsonRelatedIdentifier struct {
+	RelatedIdentifier     string `json:"relatedIdentifier"`
+	RelatedIdentifierType string `json:"relatedIdentifierType"`
+	RelationType          string `json:"relationType"`
+}
+
+type JsonRights struct {
+	Rights    string `json:"rights,omitempty"`
+	RightsUri string `json:"rightsUri,omitempty"`
+}
+
+type JsonDescription struct {
+	Description     string `json:"description"`
+	DescriptionType string `json:"descriptionType"`
+}
+
+// Attributes converts the resource into REST API attributes
+func Attributes(r *Resource) JsonAttributes {
+	a := JsonAttributes{
+		Doi:             r.Identifier.Value,
+		Url:             r.Url,
+		Creators:        []JsonName{},
+		Titles:          []JsonTitle{},
+		Publisher:       JsonPublisher{Name: r.Publisher},
+		PublicationYear: r.PublicationYear,
+		Types: JsonTypes{
+			ResourceTypeGeneral: r.ResourceType.ResourceTypeGeneral,
+			ResourceType:        r.ResourceType.Value,
+		},
+		Language:      r.Language,
+		SchemaVersion: "http://datacite.org/schema/kernel-4",
+	}
+	if a.Doi == "" {
+		a.Prefix = r.Prefix
+	}
+	for _, c := range r.Creators {
+		a.Creators = append(a.Creators, jsonName(c.CreatorName, c.GivenName, c.FamilyName, c.NameIdentifiers, c.Affiliations))
+	}
+	for _, c := range r.Contributors {
+		name := jsonName(c.ContributorName, c.GivenName, c.FamilyName, c.NameIdentifiers, c.Affiliations)
+		name.ContributorType = c.ContributorType
+		a.Contributors = append(a.Contributors, name)
+	}
+	for _, t := range r.Titles {
+		a.Titles = append(a.Titles, JsonTitle{Title: t.Value, TitleType: t.TitleType})
+	}
+	for _, s := range r.Subjects {
+		a.Subjects = append(a.Subjects, JsonSubject{Subject: s.Value, SubjectScheme: s.SubjectScheme})
+	}
+	for _, d := range r.Dates {
+		a.Dates = append(a.Dates, JsonDate{Date: d.Value, DateType: d.DateType})
+	}
+	for _, id := range r.AlternateIdentifiers {
+		a.AlternateIdentifiers = append(a.AlternateIdentifiers, JsonAlternateIdentifier{AlternateIdentifier: id.Value, AlternateIdentifierType: id.AlternateIdentifierType})
+	}
+	for _, id := range r.RelatedIdentifiers {
+		a.RelatedIdentifiers = append(a.RelatedIdentifiers, JsonRelatedIdentifier{RelatedIdentifier: id.Value, RelatedIdentifierType: id.RelatedIdentifierType, RelationType: id.RelationType})
+	}
+	for _, rights := range r.RightsList {
+		a.RightsList = append(a.RightsList, JsonRights{Rights: rights.Value, RightsUri: rights.RightsURI})
+	}
+	for _, d := range r.Descriptions {
+		a.Descriptions = append(a.Descriptions, JsonDescription{Description: d.Value, DescriptionType: d.DescriptionType})
+	}
+
+	return a
+}
+
+func jsonName(name Name, given, family string, ids []NameIdentifier, affiliations []string) JsonName {
+	n := JsonName{
+		Name:       name.Value,
+		NameType:   name.NameType,
+		GivenName:  given,
+		FamilyName: family,
+	}
+	for _, id := range ids {
+		n.NameIdentifiers = append(n.NameIdentifiers, JsonNameIdentifier{
+			NameIdentifier:       id.Value,
+			NameIdentifierScheme: id.NameIdentifierScheme,
+			SchemeUri:            id.SchemeURI,
+		})
+	}
+	for _, affiliation := range affiliations {
+		n.Affiliation = append(n.Affiliation, JsonAffiliation{Name: affiliation})
+	}
+
+	return n
+}
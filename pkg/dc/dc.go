@@ -0,0 +1,223 @@
+// Package dc crosswalks MODS records into simple Dublin Core (oai_dc)
+// following the Library of Congress MODS to Dublin Core mapping
+package dc
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+const (
+	Namespace      = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	Elements       = "http://purl.org/dc/elements/1.1/"
+	SchemaLocation = "http://www.openarchives.org/OAI/2.0/oai_dc/ http://www.openarchives.org/OAI/2.0/oai_dc.xsd"
+)
+
+// Record is an oai_dc:dc element
+type Record struct {
+	XMLName        xml.Name `xml:"oai_dc:dc"`
+	XmlnsOaiDc     string   `xml:"xmlns:oai_dc,attr"`
+	XmlnsDc        string   `xml:"xmlns:dc,attr"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+
+	Title       []string `xml:"dc:title"`
+	Creator     []string `xml:"dc:creator"`
+	Subject     []string `xml:"dc:subject"`
+	Description []string `xml:"dc:description"`
+	Publisher   []string `xml:"dc:publisher"`
+	Contributor []string `xml:"dc:contributor"`
+	Date        []string `xml:"dc:date"`
+	Type        []string `xml:"dc:type"`
+	Format      []string `xml:"dc:format"`
+	Identifier  []string `xml:"dc:identifier"`
+	Source      []string `xml:"dc:source"`
+	Language    []string `xml:"dc:language"`
+	Relation    []string `xml:"dc:relation"`
+	Coverage    []string `xml:"dc:coverage"`
+	Rights      []string `xml:"dc:rights"`
+}
+
+// creatorRoles are the relator codes and role text mapped to dc:creator
+// other names are contributors
+var creatorRoles = []string{"aut", "cre", "author", "creator"}
+
+// FromMods crosswalks a MODS record into Dublin Core
+func FromMods(m *mods.Mods) *Record {
+	r := &Record{
+		XmlnsOaiDc:     Namespace,
+		XmlnsDc:        Elements,
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: SchemaLocation,
+	}
+
+	for _, t := range m.TitleInfo {
+		title := t.Title
+		if t.SubTitle != "" {
+			title += ": " + t.SubTitle
+		}
+		if t.PartName != "" {
+			title += ". " + t.PartName
+		}
+		r.Title = appendUnique(r.Title, title)
+	}
+
+	for _, name := range m.Name {
+		value := namePart(name)
+		if isCreator(name) {
+			r.Creator = appendUnique(r.Creator, value)
+			continue
+		}
+		r.Contributor = appendUnique(r.Contributor, value)
+	}
+
+	for _, t := range m.TypeOfResource {
+		r.Type = appendUnique(r.Type, t.Value)
+	}
+	for _, g := range m.Genre {
+		r.Type = appendUnique(r.Type, g.Value)
+	}
+
+	for _, origin := range m.OriginInfo {
+		for _, p := range origin.Publisher {
+			r.Publisher = appendUnique(r.Publisher, p.Value)
+		}
+		for _, dates := range [][]mods.Date{
+			origin.DateIssued,
+			origin.DateCreated,
+			origin.DateCaptured,
+			origin.DateValid,
+			origin.DateModified,
+			origin.CopyrightDate,
+			origin.DateOther,
+		} {
+			for _, d := range joinDates(dates) {
+				r.Date = appendUnique(r.Date, d)
+			}
+		}
+	}
+
+	for _, language := range m.Language {
+		for _, term := range language.LanguageTerm {
+			r.Language = appendUnique(r.Language, term.Value)
+		}
+	}
+
+	for _, physical := range m.PhysicalDescription {
+		for _, f := range physical.Form {
+			r.Format = appendUnique(r.Format, f.Value)
+		}
+		for _, e := range physical.Extent {
+			r.Format = appendUnique(r.Format, e.Value)
+		}
+		for _, t := range physical.InternetMediaType {
+			r.Format = appendUnique(r.Format, t)
+		}
+	}
+
+	for _, texts := range [][]mods.Text{m.Abstract, m.TableOfContents, m.Note} {
+		for _, t := range texts {
+			r.Description = appendUnique(r.Description, t.Value)
+		}
+	}
+
+	for _, s := range m.Subject {
+		for _, t := range s.Topic {
+			r.Subject = appendUnique(r.Subject, t.Value)
+		}
+		for _, n := range s.Name {
+			r.Subject = appendUnique(r.Subject, namePart(n))
+		}
+		for _, g := range s.Genre {
+			r.Subject = appendUnique(r.Subject, g.Value)
+		}
+		for _, t := range s.Geographic {
+			r.Coverage = appendUnique(r.Coverage, t.Value)
+		}
+		for _, t := range s.Temporal {
+			r.Coverage = appendUnique(r.Coverage, t.Value)
+		}
+		if h := s.HierarchicalGeographic; h != nil {
+			parts := slices.Concat(h.Continent, h.Country, h.State, h.Territory, h.County, h.City)
+			r.Coverage = appendUnique(r.Coverage, strings.Join(parts, "--"))
+		}
+	}
+	for _, c := range m.Classification {
+		r.Subject = appendUnique(r.Subject, c.Value)
+	}
+
+	for _, item := range m.RelatedItem {
+		for _, t := range item.TitleInfo {
+			r.Relation = appendUnique(r.Relation, t.Title)
+		}
+	}
+
+	for _, id := range m.Identifier {
+		r.Identifier = appendUnique(r.Identifier, id.Value)
+	}
+	for _, location := range m.Location {
+		for _, u := range location.Url {
+			r.Identifier = appendUnique(r.Identifier, u.Value)
+		}
+	}
+
+	for _, a := range m.AccessCondition {
+		r.Rights = appendUnique(r.Rights, a.Value)
+	}
+
+	return r
+}
+
+func namePart(name mods.Name) string {
+	parts := make([]string, len(name.NamePart))
+	for i, p := range name.NamePart {
+		parts[i] = p.Value
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func isCreator(name mods.Name) bool {
+	for _, role := range name.Role {
+		for _, term := range role.RoleTerm {
+			if slices.Contains(creatorRoles, strings.ToLower(term.Value)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// joinDates writes start and end points as an interval e.g. 1950~/1960
+func joinDates(dates []mods.Date) []string {
+	values := []string{}
+	for i := 0; i < len(dates); i++ {
+		d := dates[i]
+		switch {
+		case d.Point == "start" && i+1 < len(dates) && dates[i+1].Point == "end":
+			values = append(values, d.Value+"/"+dates[i+1].Value)
+			i++
+		case d.Point == "start":
+			values = append(values, d.Value+"/..")
+		case d.Point == "end":
+			values = append(values, "../"+d.Value)
+		default:
+			values = append(values, d.Value)
+		}
+	}
+
+	return values
+}
+
+func appendUnique(values []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
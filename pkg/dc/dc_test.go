@@ -0,0 +1,51 @@
+package dc
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromMods(t *testing.T) {
+	record := FromMods(&mods.Mods{
+		TitleInfo: []mods.TitleInfo{{Title: "Steel", PartName: "Part 1"}, {Type: "alternative", Title: "Iron"}},
+		Name: []mods.Name{
+			{NamePart: []mods.Text{{Value: "Doe, Jane"}}, Role: []mods.Role{{RoleTerm: []mods.Text{{Type: "code", Value: "cre"}}}}},
+			{NamePart: []mods.Text{{Value: "Roe, Rick"}}, Role: []mods.Role{{RoleTerm: []mods.Text{{Type: "code", Value: "edt"}}}}},
+		},
+		Genre: []mods.Text{{Value: "Articles"}},
+		OriginInfo: []mods.OriginInfo{{
+			Publisher: []mods.Text{{Value: "Lehigh University"}},
+			DateIssued: []mods.Date{
+				{Point: "start", Value: "1950~"},
+				{Point: "end", Value: "1960"},
+			},
+			DateCreated: []mods.Date{{Value: "1949"}},
+		}},
+		Subject: []mods.Subject{
+			{Topic: []mods.Text{{Value: "Steel industry"}}},
+			{HierarchicalGeographic: &mods.HierarchicalGeographic{Country: []string{"United States"}, City: []string{"Bethlehem"}}},
+		},
+		Identifier:      []mods.Text{{Type: "doi", Value: "10.1234/foo"}},
+		Location:        []mods.Location{{Url: []mods.Url{{Value: "https://example.com/node/1"}}}},
+		AccessCondition: []mods.Text{{Value: "In Copyright"}},
+	})
+
+	assert.Equal(t, []string{"Steel. Part 1", "Iron"}, record.Title)
+	assert.Equal(t, []string{"Doe, Jane"}, record.Creator)
+	assert.Equal(t, []string{"Roe, Rick"}, record.Contributor)
+	assert.Equal(t, []string{"Articles"}, record.Type)
+	assert.Equal(t, []string{"1950~/1960", "1949"}, record.Date)
+	assert.Equal(t, []string{"Steel industry"}, record.Subject)
+	assert.Equal(t, []string{"United States--Bethlehem"}, record.Coverage)
+	assert.Equal(t, []string{"10.1234/foo", "https://example.com/node/1"}, record.Identifier)
+	assert.Equal(t, []string{"In Copyright"}, record.Rights)
+
+	data, err := xml.Marshal(record)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<oai_dc:dc xmlns:oai_dc="http://www.openarchives.org/OAI/2.0/oai_dc/" xmlns:dc="http://purl.org/dc/elements/1.1/"`)
+	assert.Contains(t, string(data), `<dc:title>Steel. Part 1</dc:title>`)
+}
@@ -0,0 +1,348 @@
+// Package edtf parses Extended Date/Time Format (ISO 8601-2) strings
+// as stored in Islandora's edtf fields.
+// It supports levels 0 and 1 along with level 2 sets, lists,
+// per-component qualifiers, extended seasons and significant digits.
+package edtf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindDate Kind = iota
+	KindInterval
+	KindSet
+)
+
+// Value is a parsed EDTF string
+type Value struct {
+	Raw  string
+	Kind Kind
+	// Start is the date for KindDate, and the start of a KindInterval
+	Start Date
+	// End is the end of a KindInterval
+	End Date
+	// Dates are the members of a KindSet
+	Dates []Date
+	// OneOf is true for [one, of, these] sets and false for {all, of, these} lists
+	OneOf bool
+}
+
+type Date struct {
+	// Year, Month and Day as written, they may contain X for unspecified digits
+	// Month may also be a season (21-41)
+	Year  string
+	Month string
+	Day   string
+	// Time is the time of day including any timezone e.g. 10:10:10Z
+	Time string
+
+	Uncertain   bool
+	Approximate bool
+
+	// Open is a ".." endpoint, Unknown is an empty endpoint
+	Open    bool
+	Unknown bool
+	// Earlier and Later are set for set members like ..1760 and 1760..
+	Earlier bool
+	Later   bool
+}
+
+var (
+	timeRegex    = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9](Z|[+-]([01][0-9]|2[0-3])(:?[0-5][0-9])?)?$`)
+	yearRegex    = regexp.MustCompile(`^-?[0-9X]{4}(S[0-9]+)?$`)
+	longYear     = regexp.MustCompile(`^Y-?[0-9]{5,}(S[0-9]+)?$`)
+	exponentYear = regexp.MustCompile(`^Y-?[0-9]+E[0-9]+(S[0-9]+)?$`)
+	twoDigits    = regexp.MustCompile(`^[0-9X]{2}$`)
+)
+
+// Validate returns an error describing why s is not valid EDTF
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+func Parse(s string) (Value, error) {
+	v := Value{Raw: s}
+	if s == "" {
+		return v, fmt.Errorf("empty date")
+	}
+
+	if strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+		return parseSet(s)
+	}
+
+	if strings.Contains(s, "/") {
+		start, end, _ := strings.Cut(s, "/")
+		v.Kind = KindInterval
+		var err error
+		v.Start, err = parseEndpoint(start)
+		if err != nil {
+			return v, fmt.Errorf("invalid interval start %q: %w", start, err)
+		}
+		v.End, err = parseEndpoint(end)
+		if err != nil {
+			return v, fmt.Errorf("invalid interval end %q: %w", end, err)
+		}
+		if (v.Start.Unknown || v.Start.Open) && (v.End.Unknown || v.End.Open) {
+			return v, fmt.Errorf("interval %q has no start or end", s)
+		}
+		if after(v.Start, v.End) {
+			return v, fmt.Errorf("interval %q ends before it starts", s)
+		}
+		return v, nil
+	}
+
+	d, err := parseDate(s)
+	if err != nil {
+		return v, err
+	}
+	v.Start = d
+
+	return v, nil
+}
+
+func parseSet(s string) (Value, error) {
+	v := Value{Raw: s, Kind: KindSet, OneOf: s[0] == '['}
+	closing := "}"
+	if v.OneOf {
+		closing = "]"
+	}
+	if !strings.HasSuffix(s, closing) {
+		return v, fmt.Errorf("set %q is missing a closing %s", s, closing)
+	}
+
+	inner := s[1 : len(s)-1]
+	if inner == "" {
+		return v, fmt.Errorf("set %q is empty", s)
+	}
+	for _, member := range strings.Split(inner, ",") {
+		member = strings.TrimSpace(member)
+		if start, end, ok := strings.Cut(member, ".."); ok && start != "" && end != "" {
+			first, err := parseDate(start)
+			if err != nil {
+				return v, err
+			}
+			last, err := parseDate(end)
+			if err != nil {
+				return v, err
+			}
+			if after(first, last) {
+				return v, fmt.Errorf("range %q ends before it starts", member)
+			}
+			v.Dates = append(v.Dates, first, last)
+			continue
+		}
+
+		var earlier, later bool
+		if strings.HasPrefix(member, "..") {
+			earlier = true
+			member = member[2:]
+		} else if strings.HasSuffix(member, "..") {
+			later = true
+			member = member[:len(member)-2]
+		}
+		d, err := parseDate(member)
+		if err != nil {
+			return v, err
+		}
+		d.Earlier = earlier
+		d.Later = later
+		v.Dates = append(v.Dates, d)
+	}
+
+	return v, nil
+}
+
+func parseEndpoint(s string) (Date, error) {
+	switch s {
+	case "":
+		return Date{Unknown: true}, nil
+	case "..":
+		return Date{Open: true}, nil
+	}
+
+	return parseDate(s)
+}
+
+func parseDate(s string) (Date, error) {
+	var d Date
+	if s == "" {
+		return d, fmt.Errorf("empty date")
+	}
+
+	date, t, hasTime := strings.Cut(s, "T")
+	if hasTime {
+		if !timeRegex.MatchString(t) {
+			return d, fmt.Errorf("invalid time %q", t)
+		}
+		d.Time = t
+	}
+
+	// level 1 qualification of the whole date
+	date, d.Uncertain, d.Approximate = trailingQualifier(date)
+
+	if strings.HasPrefix(date, "Y") {
+		if hasTime || !(longYear.MatchString(date) || exponentYear.MatchString(date)) {
+			return d, fmt.Errorf("invalid year %q", date)
+		}
+		d.Year = date
+		return d, nil
+	}
+
+	negative := strings.HasPrefix(date, "-")
+	parts := strings.Split(strings.TrimPrefix(date, "-"), "-")
+	if negative {
+		parts[0] = "-" + parts[0]
+	}
+	if len(parts) > 3 {
+		return d, fmt.Errorf("invalid date %q", s)
+	}
+	if hasTime && len(parts) != 3 {
+		return d, fmt.Errorf("a time requires a full date %q", s)
+	}
+
+	// level 2 qualification of individual components e.g. 2004-?06-11
+	for i, part := range parts {
+		part = strings.TrimLeft(part, "?~%")
+		part, _, _ = trailingQualifier(part)
+		parts[i] = part
+	}
+	if hasTime && strings.ContainsAny(s, "?~%") {
+		return d, fmt.Errorf("a date and time can not be qualified %q", s)
+	}
+
+	d.Year = parts[0]
+	if !yearRegex.MatchString(d.Year) {
+		return d, fmt.Errorf("invalid year %q", d.Year)
+	}
+	if strings.Contains(d.Year, "S") && len(parts) > 1 {
+		return d, fmt.Errorf("significant digits are only allowed on years %q", s)
+	}
+
+	if len(parts) > 1 {
+		d.Month = parts[1]
+		if !twoDigits.MatchString(d.Month) {
+			return d, fmt.Errorf("invalid month %q", d.Month)
+		}
+		if m, err := strconv.Atoi(d.Month); err == nil {
+			season := m >= 21 && m <= 41
+			if (m < 1 || m > 12) && !season {
+				return d, fmt.Errorf("invalid month %q", d.Month)
+			}
+			if season && len(parts) > 2 {
+				return d, fmt.Errorf("a season can not have a day %q", s)
+			}
+		}
+	}
+
+	if len(parts) > 2 {
+		d.Day = parts[2]
+		if !twoDigits.MatchString(d.Day) {
+			return d, fmt.Errorf("invalid day %q", d.Day)
+		}
+		if day, err := strconv.Atoi(d.Day); err == nil {
+			max := 31
+			if month, err := strconv.Atoi(d.Month); err == nil {
+				max = daysIn(month, d.Year)
+			}
+			if day < 1 || day > max {
+				return d, fmt.Errorf("invalid day %q for %s", d.Day, strings.TrimSuffix(d.Year+"-"+d.Month, "-"))
+			}
+		}
+	}
+
+	return d, nil
+}
+
+func trailingQualifier(s string) (string, bool, bool) {
+	switch {
+	case strings.HasSuffix(s, "%"):
+		return s[:len(s)-1], true, true
+	case strings.HasSuffix(s, "?"):
+		return s[:len(s)-1], true, false
+	case strings.HasSuffix(s, "~"):
+		return s[:len(s)-1], false, true
+	}
+
+	return s, false, false
+}
+
+func daysIn(month int, year string) int {
+	switch month {
+	case 4, 6, 9, 11:
+		return 30
+	case 2:
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			// e.g. 19XX could be a leap year
+			return 29
+		}
+		if y%4 == 0 && (y%100 != 0 || y%400 == 0) {
+			return 29
+		}
+		return 28
+	}
+
+	return 31
+}
+
+// after reports whether a is definitely later than b
+// only fully specified calendar dates are compared
+func after(a, b Date) bool {
+	ay, aok := a.YearInt()
+	by, bok := b.YearInt()
+	if !aok || !bok {
+		return false
+	}
+	if ay != by {
+		return ay > by
+	}
+
+	am, aerr := strconv.Atoi(a.Month)
+	bm, berr := strconv.Atoi(b.Month)
+	if aerr != nil || berr != nil || am > 12 || bm > 12 {
+		return false
+	}
+	if am != bm {
+		return am > bm
+	}
+
+	ad, aerr := strconv.Atoi(a.Day)
+	bd, berr := strconv.Atoi(b.Day)
+	if aerr != nil || berr != nil {
+		return false
+	}
+
+	return ad > bd
+}
+
+// YearInt returns the year as an int
+// if it is fully specified and not a Y prefixed year
+func (d Date) YearInt() (int, bool) {
+	year, _, _ := strings.Cut(d.Year, "S")
+	if year == "" || strings.ContainsAny(year, "XY") {
+		return 0, false
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, false
+	}
+
+	return y, true
+}
+
+// Season returns the season number (21-41) if Month is a season
+func (d Date) Season() int {
+	m, err := strconv.Atoi(d.Month)
+	if err != nil || m < 21 {
+		return 0
+	}
+
+	return m
+}
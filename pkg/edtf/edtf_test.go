@@ -0,0 +1,119 @@
+package edtf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		// level 0
+		"1985",
+		"1985-04",
+		"1985-04-12",
+		"1985-04-12T23:20:30",
+		"1985-04-12T23:20:30Z",
+		"1985-04-12T23:20:30-04",
+		"1985-04-12T23:20:30+04:30",
+		"1964/2008",
+		"2004-06/2006-08",
+		"2004-02-01/2005-02-08",
+		"2000-02-29",
+		// level 1
+		"Y170000002",
+		"Y-170000002",
+		"2001-21",
+		"1984?",
+		"2004-06~",
+		"2004-06-11%",
+		"201X",
+		"20XX",
+		"2004-XX",
+		"1985-04-XX",
+		"1985-XX-XX",
+		"1985-04-12/..",
+		"../1985-04-12",
+		"1985-04/",
+		"/1985",
+		"-1985",
+		"1984~/2004-06",
+		// level 2
+		"2004-?06-11",
+		"?2004-06-~11",
+		"2004-06~-11",
+		"2001-34",
+		"[1667,1668,1670..1672]",
+		"[..1760-12-03]",
+		"[1760-12..]",
+		"{1667,1668,1670..1672}",
+		"{1960,1961-12}",
+		"1950S2",
+		"Y171010000S3",
+		"Y-17E7",
+	}
+	for _, s := range valid {
+		assert.NoError(t, Validate(s), s)
+	}
+
+	invalid := []string{
+		"",
+		"85",
+		"1985-13",
+		"1985-04-31",
+		"1900-02-29",
+		"2001-02-29",
+		"1985-04-12T25:00:00",
+		"1985-04-12T23:20:30~",
+		"1985-04T23:20:30",
+		"Y1700",
+		"2001-21-01",
+		"2008/1964",
+		"../..",
+		"/",
+		"[1667,1668",
+		"{}",
+		"[1672..1670]",
+		"April 1985",
+		"1985-04-12-01",
+		"1950S2-01",
+	}
+	for _, s := range invalid {
+		assert.Error(t, Validate(s), s)
+	}
+}
+
+func TestParse(t *testing.T) {
+	v, err := Parse("1984?/2004-06~")
+	require.NoError(t, err)
+	assert.Equal(t, KindInterval, v.Kind)
+	assert.True(t, v.Start.Uncertain)
+	assert.False(t, v.Start.Approximate)
+	assert.Equal(t, "2004", v.End.Year)
+	assert.Equal(t, "06", v.End.Month)
+	assert.True(t, v.End.Approximate)
+
+	v, err = Parse("1985/..")
+	require.NoError(t, err)
+	assert.True(t, v.End.Open)
+	year, ok := v.Start.YearInt()
+	assert.True(t, ok)
+	assert.Equal(t, 1985, year)
+
+	v, err = Parse("[..1760-12-03, 1762]")
+	require.NoError(t, err)
+	assert.Equal(t, KindSet, v.Kind)
+	assert.True(t, v.OneOf)
+	require.Len(t, v.Dates, 2)
+	assert.True(t, v.Dates[0].Earlier)
+
+	v, err = Parse("2001-22")
+	require.NoError(t, err)
+	assert.Equal(t, 22, v.Start.Season())
+
+	v, err = Parse("19XX")
+	require.NoError(t, err)
+	_, ok = v.Start.YearInt()
+	assert.False(t, ok)
+}
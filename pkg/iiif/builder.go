@@ -0,0 +1,338 @@
+package iiif
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/dc"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+// ErrNoImage is returned for nodes without a service file image to paint on a canvas
+var ErrNoImage = errors.New("no image")
+
+type Options struct {
+	Source islandora.Source
+	// BaseUrl is the Islandora site, used for homepage links
+	BaseUrl string
+	// ManifestUrl is the URL manifests and collections are published at, {nid} is replaced with the node ID
+	ManifestUrl string
+	// ImageServer is the base URL of the IIIF image server e.g. https://iiif.example.com/iiif/3
+	ImageServer string
+	// ImageApiVersion is the IIIF Image API version of ImageServer, 2 or 3
+	ImageApiVersion int
+	// ServiceFileUse is the media use of the images painted on canvases
+	ServiceFileUse   string
+	CollectionModels []string
+	PagedModels      []string
+	// Mapping is the MODS mapping metadata is crosswalked through
+	Mapping *mods.Mapping
+	// ImageInfo loads an image's size when its media has none
+	ImageInfo func(service string) (ImageInfo, error)
+}
+
+type Builder struct {
+	opts Options
+}
+
+func NewBuilder(opts Options) *Builder {
+	opts.BaseUrl = strings.TrimSuffix(opts.BaseUrl, "/")
+	opts.ImageServer = strings.TrimSuffix(opts.ImageServer, "/")
+	if opts.ManifestUrl == "" {
+		opts.ManifestUrl = opts.BaseUrl + "/node/{nid}/manifest"
+	}
+	if opts.ImageApiVersion == 0 {
+		opts.ImageApiVersion = 3
+	}
+	if opts.ServiceFileUse == "" {
+		opts.ServiceFileUse = "Service File"
+	}
+	if opts.CollectionModels == nil {
+		opts.CollectionModels = []string{"Collection"}
+	}
+	if opts.PagedModels == nil {
+		opts.PagedModels = []string{"Paged Content", "Publication Issue"}
+	}
+	if opts.ImageInfo == nil {
+		opts.ImageInfo = FetchImageInfo
+	}
+
+	return &Builder{opts: opts}
+}
+
+// Build returns a Collection for collection nodes, otherwise a Manifest.
+// Paged content has a canvas per member page ordered by field_weight,
+// other nodes have a single canvas for their own service file.
+func (b *Builder) Build(node *api.IslandoraObject) (Document, error) {
+	model, err := islandora.Model(node, b.opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case slices.Contains(b.opts.CollectionModels, model):
+		return b.collection(node)
+	case slices.Contains(b.opts.PagedModels, model):
+		return b.manifest(node, true)
+	default:
+		return b.manifest(node, false)
+	}
+}
+
+// ManifestUrl returns the URL a node's manifest or collection is published at
+func (b *Builder) ManifestUrl(nid int) string {
+	return strings.ReplaceAll(b.opts.ManifestUrl, "{nid}", strconv.Itoa(nid))
+}
+
+func (b *Builder) collection(node *api.IslandoraObject) (*Collection, error) {
+	nid := islandora.NodeId(node)
+	members, err := islandora.SortedMembers(b.opts.Source, nid)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Collection{
+		Context: Context,
+		ID:      b.ManifestUrl(nid),
+		Type:    "Collection",
+		Label:   Label(islandora.Title(node)),
+		Items:   []Resource{},
+		members: []*api.IslandoraObject{},
+	}
+	c.Summary, c.Metadata, c.Rights, c.RequiredStatement, err = b.metadata(node)
+	if err != nil {
+		return nil, err
+	}
+	c.Homepage = b.homepage(node)
+
+	for _, member := range members {
+		model, err := islandora.Model(member, b.opts.Source)
+		if err != nil {
+			return nil, err
+		}
+		t := "Manifest"
+		switch {
+		case slices.Contains(b.opts.CollectionModels, model):
+			t = "Collection"
+		case !slices.Contains(b.opts.PagedModels, model):
+			// members like documents and audio have no image to make a manifest from
+			_, err := b.image(member)
+			if errors.Is(err, ErrNoImage) {
+				slog.Warn("Leaving member without an image out of collection", "nid", islandora.NodeId(member), "err", err)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		c.members = append(c.members, member)
+		c.Items = append(c.Items, Resource{
+			ID:    b.ManifestUrl(islandora.NodeId(member)),
+			Type:  t,
+			Label: Label(islandora.Title(member)),
+		})
+	}
+
+	return c, nil
+}
+
+func (b *Builder) manifest(node *api.IslandoraObject, paged bool) (*Manifest, error) {
+	nid := islandora.NodeId(node)
+	m := &Manifest{
+		Context: Context,
+		ID:      b.ManifestUrl(nid),
+		Type:    "Manifest",
+		Label:   Label(islandora.Title(node)),
+		Items:   []Canvas{},
+	}
+	var err error
+	m.Summary, m.Metadata, m.Rights, m.RequiredStatement, err = b.metadata(node)
+	if err != nil {
+		return nil, err
+	}
+	m.Homepage = b.homepage(node)
+
+	pages := []*api.IslandoraObject{node}
+	if paged {
+		m.Behavior = []string{"paged"}
+		pages, err = islandora.SortedMembers(b.opts.Source, nid)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, page := range pages {
+		canvas, err := b.canvas(m.ID, page)
+		if err != nil {
+			return nil, err
+		}
+		m.Items = append(m.Items, canvas)
+	}
+
+	if len(m.Items) > 0 {
+		body := m.Items[0].Items[0].Items[0].Body
+		service := body.Service[0].ID + body.Service[0].AtID
+		m.Thumbnail = []Resource{{
+			ID:      service + "/full/!400,400/0/default.jpg",
+			Type:    "Image",
+			Format:  "image/jpeg",
+			Service: body.Service,
+		}}
+	}
+
+	return m, nil
+}
+
+// image returns a node's service file, ErrNoImage when it has none or it is not an image
+func (b *Builder) image(node *api.IslandoraObject) (*islandora.Media, error) {
+	nid := islandora.NodeId(node)
+	media, err := b.opts.Source.Media(nid)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load media of node %d: %v", nid, err)
+	}
+	file, err := islandora.MediaWithUse(media, b.opts.ServiceFileUse, b.opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil || file.FileUrl() == "" {
+		return nil, fmt.Errorf("%w: node %d has no %s", ErrNoImage, nid, b.opts.ServiceFileUse)
+	}
+	if mime := file.Mime(); mime != "" && !strings.HasPrefix(mime, "image/") {
+		return nil, fmt.Errorf("%w: node %d's %s is %s, not an image", ErrNoImage, nid, b.opts.ServiceFileUse, mime)
+	}
+
+	return file, nil
+}
+
+func (b *Builder) canvas(manifestId string, node *api.IslandoraObject) (Canvas, error) {
+	nid := islandora.NodeId(node)
+	file, err := b.image(node)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	service := b.opts.ImageServer + "/" + url.QueryEscape(file.FileUrl())
+	width, height := file.Dimensions()
+	if width == 0 || height == 0 {
+		info, err := b.opts.ImageInfo(service)
+		if err != nil {
+			return Canvas{}, fmt.Errorf("unable to load the size of node %d's image: %v", nid, err)
+		}
+		width, height = info.Width, info.Height
+	}
+
+	id := fmt.Sprintf("%s/canvas/%d", manifestId, nid)
+	body := Resource{
+		Type:   "Image",
+		Format: "image/jpeg",
+		Width:  width,
+		Height: height,
+	}
+	if b.opts.ImageApiVersion == 2 {
+		body.ID = service + "/full/full/0/default.jpg"
+		body.Service = []Service{{AtID: service, AtType: "ImageService2", Profile: "http://iiif.io/api/image/2/level2.json"}}
+	} else {
+		body.ID = service + "/full/max/0/default.jpg"
+		body.Service = []Service{{ID: service, Type: "ImageService3", Profile: "level2"}}
+	}
+
+	return Canvas{
+		ID:     id,
+		Type:   "Canvas",
+		Label:  Label(islandora.Title(node)),
+		Width:  width,
+		Height: height,
+		Items: []AnnotationPage{{
+			ID:   id + "/page",
+			Type: "AnnotationPage",
+			Items: []Annotation{{
+				ID:         id + "/annotation",
+				Type:       "Annotation",
+				Motivation: "painting",
+				Body:       body,
+				Target:     id,
+			}},
+		}},
+	}, nil
+}
+
+// metadataLabels are the Dublin Core elements shown as metadata, in order
+var metadataLabels = []string{
+	"Title", "Creator", "Contributor", "Date", "Type", "Format", "Publisher",
+	"Subject", "Coverage", "Language", "Relation", "Source", "Identifier",
+}
+
+// metadata crosswalks the node through MODS and Dublin Core.
+// Rights statement and Creative Commons URIs are the rights, other rights are a required statement.
+func (b *Builder) metadata(node *api.IslandoraObject) (LanguageMap, []MetadataEntry, string, *MetadataEntry, error) {
+	record, err := mods.FromNode(node, mods.Options{
+		Mapping: b.opts.Mapping,
+		Terms:   b.opts.Source,
+		BaseUrl: b.opts.BaseUrl,
+	})
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+	d := dc.FromMods(record)
+
+	values := map[string][]string{
+		"Title":       d.Title,
+		"Creator":     d.Creator,
+		"Contributor": d.Contributor,
+		"Date":        d.Date,
+		"Type":        d.Type,
+		"Format":      d.Format,
+		"Publisher":   d.Publisher,
+		"Subject":     d.Subject,
+		"Coverage":    d.Coverage,
+		"Language":    d.Language,
+		"Relation":    d.Relation,
+		"Source":      d.Source,
+		"Identifier":  d.Identifier,
+	}
+	metadata := []MetadataEntry{}
+	for _, label := range metadataLabels {
+		if len(values[label]) > 0 {
+			metadata = append(metadata, MetadataEntry{Label: Label(label), Value: Label(values[label]...)})
+		}
+	}
+
+	var summary LanguageMap
+	if len(d.Description) > 0 {
+		summary = Label(d.Description[0])
+	}
+
+	rights := ""
+	statements := []string{}
+	for _, r := range d.Rights {
+		if rights == "" && (strings.HasPrefix(r, "http://rightsstatements.org/vocab/") || strings.HasPrefix(r, "http://creativecommons.org/")) {
+			rights = r
+			continue
+		}
+		statements = append(statements, r)
+	}
+	var required *MetadataEntry
+	if len(statements) > 0 {
+		required = &MetadataEntry{Label: Label("Rights"), Value: Label(statements...)}
+	}
+
+	return summary, metadata, rights, required, nil
+}
+
+func (b *Builder) homepage(node *api.IslandoraObject) []Resource {
+	if b.opts.BaseUrl == "" {
+		return nil
+	}
+
+	return []Resource{{
+		ID:     fmt.Sprintf("%s/node/%d", b.opts.BaseUrl, islandora.NodeId(node)),
+		Type:   "Text",
+		Label:  Label(islandora.Title(node)),
+		Format: "text/html",
+	}}
+}
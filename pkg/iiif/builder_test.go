@@ -0,0 +1,175 @@
+package iiif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNodes = `[
+	{"nid": [{"value": 1}], "title": [{"value": "Steel"}], "field_model": [{"target_id": 1}], "field_member_of": [{"target_id": 6}]},
+	{
+		"nid": [{"value": 2}],
+		"title": [{"value": "A book"}],
+		"field_model": [{"target_id": 2}],
+		"field_member_of": [{"target_id": 1}, {"target_id": 6}],
+		"field_abstract": [{"value": "About steel"}],
+		"field_edtf_date_issued": [{"value": "1950"}],
+		"field_rights": [{"value": "http://rightsstatements.org/vocab/InC/1.0/"}]
+	},
+	{"nid": [{"value": 4}], "title": [{"value": "Page 2"}], "field_weight": [{"value": 2}], "field_model": [{"target_id": 3}], "field_member_of": [{"target_id": 2}]},
+	{"nid": [{"value": 5}], "title": [{"value": "Loose page"}], "field_model": [{"target_id": 3}], "field_member_of": [{"target_id": 2}]},
+	{"nid": [{"value": 3}], "title": [{"value": "Page 1"}], "field_weight": [{"value": 1}], "field_model": [{"target_id": 3}], "field_member_of": [{"target_id": 2}]},
+	{"nid": [{"value": 6}], "title": [{"value": "Bethlehem"}], "field_model": [{"target_id": 1}]},
+	{"nid": [{"value": 7}], "title": [{"value": "A report"}], "field_model": [{"target_id": 12}], "field_member_of": [{"target_id": 6}]},
+	{"nid": [{"value": 8}], "title": [{"value": "A photo"}], "field_model": [{"target_id": 13}], "field_member_of": [{"target_id": 6}]},
+	{"nid": [{"value": 9}], "title": [{"value": "Image"}], "field_model": [{"target_id": 3}]}
+]`
+
+func serviceFile(url string, width, height int) islandora.Media {
+	return islandora.Media{
+		MediaUse: model.EntityReferenceField{{TargetId: 10}},
+		MimeType: model.GenericField{{Value: "image/jp2"}},
+		Image:    model.ImageField{{Url: url, Width: width, Height: height}},
+	}
+}
+
+// testBuilder returns a builder for testNodes and a lookup of its nodes by nid
+func testBuilder(t *testing.T) (*Builder, func(nid int) *api.IslandoraObject) {
+	nodes, err := islandora.ParseNodes([]byte(testNodes))
+	require.NoError(t, err)
+	source := islandora.NewMemorySource(nodes, map[int][]islandora.Media{
+		3: {
+			{MediaUse: model.EntityReferenceField{{TargetId: 11}}, Image: model.ImageField{{Url: "https://example.com/original.tif"}}},
+			serviceFile("https://example.com/page1.jp2", 1000, 2000),
+		},
+		4: {serviceFile("https://example.com/page2.jp2", 0, 0)},
+		5: {serviceFile("https://example.com/page3.jp2", 10, 20)},
+		7: {{
+			MediaUse: model.EntityReferenceField{{TargetId: 10}},
+			MimeType: model.GenericField{{Value: "application/pdf"}},
+			File:     model.FileField{{Url: "https://example.com/report.pdf"}},
+		}},
+		8: {serviceFile("https://example.com/photo.jp2", 30, 40)},
+	}, islandora.MapTerms{
+		1:  islandora.NewTerm("islandora_models", "Collection"),
+		2:  islandora.NewTerm("islandora_models", "Paged Content"),
+		3:  islandora.NewTerm("islandora_models", "Page"),
+		10: islandora.NewTerm("islandora_media_use", "Service File"),
+		11: islandora.NewTerm("islandora_media_use", "Original File"),
+		12: islandora.NewTerm("islandora_models", "Digital Document"),
+		13: islandora.NewTerm("islandora_models", "Image"),
+	})
+	node := func(nid int) *api.IslandoraObject {
+		n, err := source.Node(nid)
+		require.NoError(t, err)
+		return n
+	}
+
+	return NewBuilder(Options{
+		Source:      source,
+		BaseUrl:     "https://example.com/",
+		ImageServer: "https://iiif.example.com/iiif/3/",
+		ImageInfo: func(service string) (ImageInfo, error) {
+			return ImageInfo{Width: 300, Height: 400}, nil
+		},
+	}), node
+}
+
+func TestBuildManifest(t *testing.T) {
+	b, node := testBuilder(t)
+
+	doc, err := b.Build(node(2))
+	require.NoError(t, err)
+	m, ok := doc.(*Manifest)
+	require.True(t, ok)
+
+	assert.Equal(t, "https://example.com/node/2/manifest", m.ID)
+	assert.Equal(t, Label("A book"), m.Label)
+	assert.Equal(t, Label("About steel"), m.Summary)
+	assert.Equal(t, []string{"paged"}, m.Behavior)
+	assert.Equal(t, "http://rightsstatements.org/vocab/InC/1.0/", m.Rights)
+	assert.Contains(t, m.Metadata, MetadataEntry{Label: Label("Date"), Value: Label("1950")})
+
+	require.Len(t, m.Items, 3)
+	assert.Equal(t, Label("Page 1"), m.Items[0].Label)
+	assert.Equal(t, Label("Page 2"), m.Items[1].Label)
+	assert.Equal(t, Label("Loose page"), m.Items[2].Label)
+
+	canvas := m.Items[0]
+	assert.Equal(t, "https://example.com/node/2/manifest/canvas/3", canvas.ID)
+	assert.Equal(t, 1000, canvas.Width)
+	assert.Equal(t, 2000, canvas.Height)
+	annotation := canvas.Items[0].Items[0]
+	assert.Equal(t, canvas.ID, annotation.Target)
+	assert.Equal(t, "https://iiif.example.com/iiif/3/https%3A%2F%2Fexample.com%2Fpage1.jp2/full/max/0/default.jpg", annotation.Body.ID)
+	assert.Equal(t, []Service{{ID: "https://iiif.example.com/iiif/3/https%3A%2F%2Fexample.com%2Fpage1.jp2", Type: "ImageService3", Profile: "level2"}}, annotation.Body.Service)
+
+	// the size comes from info.json when the media has none
+	assert.Equal(t, 300, m.Items[1].Width)
+	assert.Equal(t, 400, m.Items[1].Height)
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"@context":"http://iiif.io/api/presentation/3/context.json","id":"https://example.com/node/2/manifest","type":"Manifest"`)
+}
+
+func TestBuildCollection(t *testing.T) {
+	b, node := testBuilder(t)
+
+	doc, err := b.Build(node(1))
+	require.NoError(t, err)
+	c, ok := doc.(*Collection)
+	require.True(t, ok)
+
+	assert.Equal(t, "Collection", c.Type)
+	assert.Equal(t, []Resource{{ID: "https://example.com/node/2/manifest", Type: "Manifest", Label: Label("A book")}}, c.Items)
+	require.Len(t, c.Members(), 1)
+}
+
+func TestBuildMissingServiceFile(t *testing.T) {
+	b, node := testBuilder(t)
+
+	_, err := b.Build(node(9))
+	assert.ErrorIs(t, err, ErrNoImage)
+	assert.ErrorContains(t, err, "node 9 has no Service File")
+}
+
+func TestBuildMixedCollection(t *testing.T) {
+	b, node := testBuilder(t)
+
+	// build the collection and its members recursively, as export iiif --recursive does
+	docs := map[int]Document{}
+	queue := []*api.IslandoraObject{node(6)}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		doc, err := b.Build(n)
+		require.NoError(t, err)
+		docs[islandora.NodeId(n)] = doc
+		if c, ok := doc.(*Collection); ok {
+			queue = append(queue, c.Members()...)
+		}
+	}
+
+	// the document has no image so it is left out
+	c := docs[6].(*Collection)
+	assert.Equal(t, []Resource{
+		{ID: "https://example.com/node/1/manifest", Type: "Collection", Label: Label("Steel")},
+		{ID: "https://example.com/node/2/manifest", Type: "Manifest", Label: Label("A book")},
+		{ID: "https://example.com/node/8/manifest", Type: "Manifest", Label: Label("A photo")},
+	}, c.Items)
+	assert.Len(t, c.Members(), 3)
+	assert.NotContains(t, docs, 7)
+	assert.IsType(t, &Manifest{}, docs[8])
+	assert.IsType(t, &Manifest{}, docs[2])
+
+	_, err := b.Build(node(7))
+	assert.ErrorIs(t, err, ErrNoImage)
+	assert.ErrorContains(t, err, "node 7's Service File is application/pdf, not an image")
+}
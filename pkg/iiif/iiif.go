@@ -0,0 +1,138 @@
+// Package iiif builds IIIF Presentation 3.0 manifests and collections for Islandora nodes
+package iiif
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+)
+
+const Context = "http://iiif.io/api/presentation/3/context.json"
+
+// LanguageMap is a label or value keyed by language, "none" when unknown
+type LanguageMap map[string][]string
+
+func Label(values ...string) LanguageMap {
+	return LanguageMap{"none": values}
+}
+
+type MetadataEntry struct {
+	Label LanguageMap `json:"label"`
+	Value LanguageMap `json:"value"`
+}
+
+// Document is a *Manifest or *Collection
+type Document interface {
+	document()
+}
+
+type Manifest struct {
+	Context           string          `json:"@context"`
+	ID                string          `json:"id"`
+	Type              string          `json:"type"`
+	Label             LanguageMap     `json:"label"`
+	Summary           LanguageMap     `json:"summary,omitempty"`
+	Metadata          []MetadataEntry `json:"metadata,omitempty"`
+	Rights            string          `json:"rights,omitempty"`
+	RequiredStatement *MetadataEntry  `json:"requiredStatement,omitempty"`
+	Behavior          []string        `json:"behavior,omitempty"`
+	Homepage          []Resource      `json:"homepage,omitempty"`
+	Thumbnail         []Resource      `json:"thumbnail,omitempty"`
+	Items             []Canvas        `json:"items"`
+}
+
+type Collection struct {
+	Context           string          `json:"@context"`
+	ID                string          `json:"id"`
+	Type              string          `json:"type"`
+	Label             LanguageMap     `json:"label"`
+	Summary           LanguageMap     `json:"summary,omitempty"`
+	Metadata          []MetadataEntry `json:"metadata,omitempty"`
+	Rights            string          `json:"rights,omitempty"`
+	RequiredStatement *MetadataEntry  `json:"requiredStatement,omitempty"`
+	Homepage          []Resource      `json:"homepage,omitempty"`
+	Items             []Resource      `json:"items"`
+
+	members []*api.IslandoraObject
+}
+
+// Members returns the nodes the collection's items were built from
+func (c *Collection) Members() []*api.IslandoraObject {
+	return c.members
+}
+
+func (*Manifest) document()   {}
+func (*Collection) document() {}
+
+// Resource is a reference to, or an embedded, content resource, manifest or collection
+type Resource struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	Label   LanguageMap `json:"label,omitempty"`
+	Format  string      `json:"format,omitempty"`
+	Width   int         `json:"width,omitempty"`
+	Height  int         `json:"height,omitempty"`
+	Service []Service   `json:"service,omitempty"`
+}
+
+// Service is an image service, version 2 services use @id and @type
+type Service struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type,omitempty"`
+	AtID    string `json:"@id,omitempty"`
+	AtType  string `json:"@type,omitempty"`
+	Profile string `json:"profile"`
+}
+
+type Canvas struct {
+	ID     string           `json:"id"`
+	Type   string           `json:"type"`
+	Label  LanguageMap      `json:"label,omitempty"`
+	Width  int              `json:"width"`
+	Height int              `json:"height"`
+	Items  []AnnotationPage `json:"items"`
+}
+
+type AnnotationPage struct {
+	ID    string       `json:"id"`
+	Type  string       `json:"type"`
+	Items []Annotation `json:"items"`
+}
+
+type Annotation struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Motivation string   `json:"motivation"`
+	Body       Resource `json:"body"`
+	Target     string   `json:"target"`
+}
+
+// ImageInfo is the size of an image from its image service's info.json
+type ImageInfo struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// FetchImageInfo loads an image service's info.json
+func FetchImageInfo(service string) (ImageInfo, error) {
+	var info ImageInfo
+	resp, err := http.Get(service + "/info.json")
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return info, fmt.Errorf("bad status code for %s/info.json: %s", service, resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		return info, err
+	}
+	if info.Width <= 0 || info.Height <= 0 {
+		return info, fmt.Errorf("%s/info.json has no width and height", service)
+	}
+
+	return info, nil
+}
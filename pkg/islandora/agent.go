@@ -0,0 +1,101 @@
+package islandora
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+)
+
+// Agent is a linked agent with the details citation and registration formats need
+type Agent struct {
+	Name string
+	// Relator is the MARC relator code from a relators:* rel_type e.g. aut
+	Relator string
+	// RelType is the rel_type as stored e.g. relators:aut
+	RelType string
+	// Vid is the agent's vocabulary e.g. person or corporate_body
+	Vid string
+	// Orcid is the bare ORCID iD e.g. 0000-0002-1825-0097
+	Orcid        string
+	Affiliations []string
+}
+
+// LinkedAgents loads the terms in a node's field_linked_agent.
+// Affiliations come from the term's schema:worksFor relationships
+// and are removed from names written as "Name - Affiliation".
+// There are no agents without terms to load them from.
+func LinkedAgents(node *api.IslandoraObject, terms TermLookup) ([]Agent, error) {
+	if node.FieldLinkedAgent == nil || terms == nil {
+		return nil, nil
+	}
+
+	agents := []Agent{}
+	for _, ref := range *node.FieldLinkedAgent {
+		term, err := terms.Term(ref.TargetId)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load term %d: %v", ref.TargetId, err)
+		}
+		if len(term.Name) == 0 {
+			return nil, fmt.Errorf("term %d has no name", ref.TargetId)
+		}
+
+		agent := Agent{Name: term.Name[0].Value, RelType: ref.RelType}
+		if scheme, code, ok := strings.Cut(ref.RelType, ":"); ok && scheme == "relators" {
+			agent.Relator = code
+		}
+		if len(term.Vid) > 0 {
+			agent.Vid = term.Vid[0].TargetId
+		}
+		for _, id := range term.Identifier {
+			if strings.EqualFold(id.Attr0, "orcid") && id.Value != "" {
+				agent.Orcid = strings.TrimPrefix(strings.TrimPrefix(id.Value, "https://orcid.org/"), "http://orcid.org/")
+			}
+		}
+		for _, r := range term.Relationships {
+			if r.RelType != "schema:worksFor" {
+				continue
+			}
+			org, err := terms.Term(r.TargetId)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load term %d: %v", r.TargetId, err)
+			}
+			if len(org.Name) == 0 {
+				continue
+			}
+			affiliation := org.Name[0].Value
+			agent.Affiliations = append(agent.Affiliations, affiliation)
+			agent.Name = strings.TrimSuffix(agent.Name, " - "+affiliation)
+		}
+		agents = append(agents, agent)
+	}
+
+	return agents, nil
+}
+
+// Personal reports whether the agent is a person rather than an organization
+func (a Agent) Personal() bool {
+	return a.Vid == "" || a.Vid == "person"
+}
+
+// FamilyGiven splits a person's name written as "Family, Given" or "Given Family"
+func (a Agent) FamilyGiven() (string, string) {
+	if family, given, ok := strings.Cut(a.Name, ", "); ok {
+		return strings.TrimSpace(family), strings.TrimSpace(given)
+	}
+	parts := strings.Fields(a.Name)
+	if len(parts) < 2 {
+		return a.Name, ""
+	}
+
+	return parts[len(parts)-1], strings.Join(parts[:len(parts)-1], " ")
+}
+
+// OrcidUrl returns the ORCID iD as a URL
+func (a Agent) OrcidUrl() string {
+	if a.Orcid == "" {
+		return ""
+	}
+
+	return "https://orcid.org/" + a.Orcid
+}
@@ -3,7 +3,9 @@ package islandora
 import (
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,6 +14,9 @@ import (
 
 const cacheDir = "/tmp/islandora"
 
+// ErrNotFound is returned when the requested entity does not exist
+var ErrNotFound = errors.New("not found")
+
 // getCacheFilename creates a unique filename based on URL
 func getCacheFilename(url string) string {
 	hash := md5.Sum([]byte(url))
@@ -49,6 +54,9 @@ func getRequest(url string) (*http.Request, error) {
 func decodeJsonResponse(resp *http.Response, obj any) error {
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("bad status code for %s: %s: %w", resp.Request.URL, resp.Status, ErrNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad status code for %s: %s", resp.Request.URL, resp.Status)
 	}
@@ -60,3 +68,56 @@ func decodeJsonResponse(resp *http.Response, obj any) error {
 
 	return nil
 }
+
+// fetchJson GETs url and decodes the response into T, using the on-disk cache
+func fetchJson[T any](url string) (T, error) {
+	cacheFile := getCacheFilename(url)
+	var obj T
+
+	// Try to read from cache first
+	if isCacheValid(cacheFile) {
+		data, err := os.ReadFile(cacheFile)
+		if err == nil {
+			if json.Unmarshal(data, &obj) == nil {
+				return obj, nil
+			}
+		}
+	}
+
+	return fetchFresh[T](url)
+}
+
+// fetchFresh GETs url and decodes the response into T, skipping the cache read
+// so recent changes are seen, and refreshes the cached copy
+func fetchFresh[T any](url string) (T, error) {
+	cacheFile := getCacheFilename(url)
+	var obj T
+
+	req, err := getRequest(url)
+	if err != nil {
+		return obj, err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return obj, err
+	}
+
+	err = decodeJsonResponse(resp, &obj)
+	if err != nil {
+		return obj, err
+	}
+
+	// Cache the result
+	if err := ensureCacheDir(); err == nil {
+		if data, err := json.Marshal(obj); err == nil {
+			err = os.WriteFile(cacheFile, data, 0644)
+			if err != nil {
+				slog.Error("Unable to write file", "file", cacheFile, "err", err)
+			}
+		}
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,126 @@
+package islandora
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+)
+
+type jsonApiNodes struct {
+	Data []struct {
+		Attributes struct {
+			Nid int `json:"drupal_internal__nid"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Links struct {
+		Next struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"links"`
+}
+
+// FetchChangedNids returns the IDs of the islandora_object nodes changed at or after since
+// this requires JSON:API to be enabled on the site
+func FetchChangedNids(baseUrl string, since time.Time) (map[int]bool, error) {
+	params := url.Values{}
+	params.Set("fields[node--islandora_object]", "drupal_internal__nid")
+	params.Set("filter[changed][condition][path]", "changed")
+	params.Set("filter[changed][condition][operator]", ">=")
+	params.Set("filter[changed][condition][value]", strconv.FormatInt(since.Unix(), 10))
+	params.Set("page[limit]", "50")
+	params.Set("sort", "drupal_internal__nid")
+	next := fmt.Sprintf("%s/jsonapi/node/islandora_object?%s", baseUrl, params.Encode())
+
+	nids := map[int]bool{}
+	for next != "" {
+		page, err := fetchFresh[jsonApiNodes](next)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range page.Data {
+			nids[node.Attributes.Nid] = true
+		}
+		next = page.Links.Next.Href
+	}
+
+	return nids, nil
+}
+
+// FetchNodesChanged crawls nid and its descendants like FetchNodes
+// and reports which of them changed at or after since.
+// Changed nodes are found with a JSON:API filter when available so only they are re-fetched,
+// otherwise every node is re-fetched and its changed field compared.
+func FetchNodesChanged(baseUrl string, nid int, since time.Time) ([]*api.IslandoraObject, map[int]bool, error) {
+	changedNids, err := FetchChangedNids(baseUrl, since)
+	if err != nil {
+		slog.Warn("Unable to filter changed nodes with JSON:API, comparing every node's changed field instead", "err", err)
+	}
+
+	var allNodes []*api.IslandoraObject
+	changed := map[int]bool{}
+	queue := []int{nid}
+	for len(queue) > 0 {
+		currentNid := queue[0]
+		queue = queue[1:]
+
+		url := fmt.Sprintf("%s/node/%d?_format=json", baseUrl, currentNid)
+		var node *api.IslandoraObject
+		if changedNids != nil {
+			node, err = FetchNode(url)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		// the JSON:API filter only covers islandora_object nodes
+		if changedNids == nil || changedNids[currentNid] || !isIslandoraObject(node) {
+			fresh, err := fetchFresh[api.IslandoraObject](url)
+			if err != nil {
+				return nil, nil, err
+			}
+			node = &fresh
+			changed[currentNid], err = ChangedSince(node, since)
+			if err != nil {
+				return nil, nil, fmt.Errorf("node %d: %v", currentNid, err)
+			}
+		}
+		allNodes = append(allNodes, node)
+
+		// members are always re-fetched so new children are found
+		url = fmt.Sprintf("%s/node/%d/members?_format=json", baseUrl, currentNid)
+		children, err := fetchFresh[[]Nid](url)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, child := range children {
+			id, err := strconv.Atoi(child.Nid)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid member nid %q of node %d", child.Nid, currentNid)
+			}
+			queue = append(queue, id)
+		}
+	}
+
+	return allNodes, changed, nil
+}
+
+// ChangedSince reports whether a node's changed field is at or after since
+func ChangedSince(node *api.IslandoraObject, since time.Time) (bool, error) {
+	if node.Changed == nil || len(*node.Changed) == 0 {
+		return false, fmt.Errorf("node has no changed field")
+	}
+
+	changed, err := time.Parse(time.RFC3339, (*node.Changed)[0].Value)
+	if err != nil {
+		return false, fmt.Errorf("invalid changed date %q: %v", (*node.Changed)[0].Value, err)
+	}
+
+	return !changed.Before(since), nil
+}
+
+func isIslandoraObject(node *api.IslandoraObject) bool {
+	return node.Type != nil && len(*node.Type) > 0 && (*node.Type)[0].TargetId == "islandora_object"
+}
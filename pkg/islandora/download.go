@@ -0,0 +1,202 @@
+package islandora
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DownloadFile saves url to path, checking it against size when it is known
+// e.g. a media's field_file_size.
+// The download is written to path.part first, and an interrupted download
+// is resumed from there with a range request when the server supports it.
+// The file's ETag or Last-Modified is kept in path.part.validator and sent
+// as If-Range, so a file that changed since is downloaded again in full.
+func DownloadFile(url, path string, size int64) error {
+	part := path + ".part"
+	validator := part + ".validator"
+	offset := int64(0)
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+	ifRange, err := os.ReadFile(validator)
+	if err != nil || len(ifRange) == 0 {
+		// without a validator the partial file can't be trusted
+		offset = 0
+	}
+
+	req, err := getRequest(url)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		req.Header.Set("If-Range", string(ifRange))
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := int64(-1)
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		var first int64
+		first, total, err = contentRange(resp.Header.Get("Content-Range"))
+		if err != nil || first != offset {
+			return fmt.Errorf("unexpected Content-Range %q for %s", resp.Header.Get("Content-Range"), url)
+		}
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// the server sent the whole file
+		flags |= os.O_TRUNC
+		total = resp.ContentLength
+		err = saveValidator(validator, resp.Header)
+		if err != nil {
+			return err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the previous download may have finished before it was renamed
+		_, total, _ = contentRange(resp.Header.Get("Content-Range"))
+		if offset > 0 && offset == total && (size <= 0 || size == total) {
+			return finishDownload(part, path)
+		}
+		if offset == 0 {
+			return fmt.Errorf("bad status code for %s: %s", url, resp.Status)
+		}
+		err = os.Remove(validator)
+		if err != nil {
+			return err
+		}
+		return DownloadFile(url, path, size)
+	case http.StatusNotFound:
+		return fmt.Errorf("bad status code for %s: %s: %w", url, resp.Status, ErrNotFound)
+	default:
+		return fmt.Errorf("bad status code for %s: %s", url, resp.Status)
+	}
+
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, resp.Body)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("error downloading %s: %v", url, err)
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	if size <= 0 {
+		size = total
+	}
+	if size >= 0 {
+		info, err := os.Stat(part)
+		if err != nil {
+			return err
+		}
+		if info.Size() != size {
+			if info.Size() > size {
+				os.Remove(part)
+				os.Remove(validator)
+			}
+			return fmt.Errorf("downloaded %d of %d bytes from %s", info.Size(), size, url)
+		}
+	}
+
+	return finishDownload(part, path)
+}
+
+// contentRange parses a Content-Range header e.g. bytes 10-19/20 or bytes */20.
+// The total is -1 when the server doesn't know it.
+func contentRange(header string) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, -1, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	byteRange, length, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, -1, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	total := int64(-1)
+	if length != "*" {
+		n, err := strconv.ParseInt(length, 10, 64)
+		if err != nil {
+			return 0, -1, fmt.Errorf("invalid Content-Range %q", header)
+		}
+		total = n
+	}
+	if byteRange == "*" {
+		return 0, total, nil
+	}
+	first, _, _ := strings.Cut(byteRange, "-")
+	n, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, -1, fmt.Errorf("invalid Content-Range %q", header)
+	}
+
+	return n, total, nil
+}
+
+// saveValidator keeps the header a resumed download is checked against.
+// Weak ETags can't be used with If-Range.
+func saveValidator(file string, header http.Header) error {
+	validator := header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		err := os.Remove(file)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return os.WriteFile(file, []byte(validator), 0644)
+}
+
+func finishDownload(part, path string) error {
+	err := os.Remove(part + ".validator")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Rename(part, path)
+}
+
+// HashFile streams url into h, e.g. to checksum a media file without saving it
+func HashFile(url string, h io.Writer) error {
+	req, err := getRequest(url)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("bad status code for %s: %s: %w", url, resp.Status, ErrNotFound)
+	default:
+		return fmt.Errorf("bad status code for %s: %s", url, resp.Status)
+	}
+	_, err = io.Copy(h, resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", url, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package islandora
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+)
+
+// ParseNodes decodes a JSON node, or an array of nodes
+func ParseNodes(data []byte) ([]*api.IslandoraObject, error) {
+	var nodes []*api.IslandoraObject
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err := json.Unmarshal(data, &nodes)
+		if err != nil {
+			return nil, err
+		}
+		return nodes, nil
+	}
+
+	var node api.IslandoraObject
+	err := json.Unmarshal(data, &node)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nodes, &node), nil
+}
+
+// ReadNodes reads the nodes saved in a JSON file, or in every *.json file in a directory
+func ReadNodes(path string) ([]*api.IslandoraObject, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	nodes := []*api.IslandoraObject{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := ParseNodes(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", file, err)
+		}
+		nodes = append(nodes, parsed...)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,185 @@
+package islandora
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchParagraphAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the site serves the requested revision of paragraph 1 but ignores it for paragraph 2
+		revision := r.URL.Query().Get("revision")
+		if r.URL.Path == "/entity/paragraph/2" {
+			revision = "9"
+		}
+		fmt.Fprintf(w, `{"id": [{"value": 1}], "revision_id": [{"value": %s}], "type": [{"target_id": "note"}]}`, revision)
+	}))
+	defer server.Close()
+
+	p, err := FetchParagraphAs[Paragraph](server.URL, model.EntityReferenceRevisions{TargetId: 1, TargetRevisionId: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "note", p.Bundle())
+	assert.JSONEq(t, `[{"value": 5}]`, string(p["revision_id"]))
+
+	_, err = FetchParagraphAs[Paragraph](server.URL, model.EntityReferenceRevisions{TargetId: 2, TargetRevisionId: 5})
+	assert.ErrorContains(t, err, "returned revision 9")
+}
+
+func TestFetchVocabularyUncached(t *testing.T) {
+	names := []string{"Maps"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [`)
+		for i, name := range names {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"attributes": {"drupal_internal__tid": %d, "name": %q}}`, i+1, name)
+		}
+		fmt.Fprint(w, `], "links": {}}`)
+	}))
+	defer server.Close()
+
+	terms, err := FetchVocabulary(server.URL, "genre")
+	require.NoError(t, err)
+	assert.Equal(t, []VocabularyTerm{{Tid: 1, Name: "Maps"}}, terms)
+
+	// a term created since the last fetch is seen
+	names = append(names, "Photographs")
+	terms, err = FetchVocabulary(server.URL, "genre")
+	require.NoError(t, err)
+	assert.Equal(t, []VocabularyTerm{{Tid: 1, Name: "Maps"}, {Tid: 2, Name: "Photographs"}}, terms)
+}
+
+func TestDownloadFile(t *testing.T) {
+	content := "0123456789"
+	etag := `"v1"`
+	statuses := []int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("ETag", etag)
+		http.ServeContent(rec, r, "file.txt", time.Time{}, strings.NewReader(content))
+		statuses = append(statuses, rec.Code)
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = rec.Body.WriteTo(w)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.txt")
+	partial := func(data, validator string) {
+		require.NoError(t, os.WriteFile(path+".part", []byte(data), 0644))
+		require.NoError(t, os.WriteFile(path+".part.validator", []byte(validator), 0644))
+	}
+	downloaded := func(want ...int) {
+		assert.Equal(t, want, statuses)
+		statuses = []int{}
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+		assert.NoFileExists(t, path+".part")
+		assert.NoFileExists(t, path+".part.validator")
+		require.NoError(t, os.Remove(path))
+	}
+
+	// 200 on a fresh download
+	require.NoError(t, DownloadFile(server.URL, path, 10))
+	downloaded(http.StatusOK)
+
+	// 206 resumes an unchanged file
+	partial("01234", etag)
+	require.NoError(t, DownloadFile(server.URL, path, 0))
+	downloaded(http.StatusPartialContent)
+
+	// 200 replaces a partial download of a file that has since changed
+	partial("abcde", `"v0"`)
+	require.NoError(t, DownloadFile(server.URL, path, 10))
+	downloaded(http.StatusOK)
+
+	// 416 finishes a download that wasn't renamed
+	partial(content, etag)
+	require.NoError(t, DownloadFile(server.URL, path, 10))
+	downloaded(http.StatusRequestedRangeNotSatisfiable)
+
+	// 416 for a partial file longer than the file starts over
+	partial(content+"stale", etag)
+	require.NoError(t, DownloadFile(server.URL, path, 0))
+	downloaded(http.StatusRequestedRangeNotSatisfiable, http.StatusOK)
+
+	// the size is checked against the media's file size
+	err := DownloadFile(server.URL, path, 12)
+	assert.ErrorContains(t, err, "downloaded 10 of 12 bytes")
+	assert.NoFileExists(t, path)
+}
+
+func TestSortedMembers(t *testing.T) {
+	nodes, err := ParseNodes([]byte(`[
+		{"nid": [{"value": 1}], "title": [{"value": "A book"}]},
+		{"nid": [{"value": 5}], "title": [{"value": "Loose page"}], "field_member_of": [{"target_id": 1}]},
+		{"nid": [{"value": 4}], "title": [{"value": "Page 2"}], "field_weight": [{"value": 2}], "field_member_of": [{"target_id": 1}]},
+		{"nid": [{"value": 6}], "field_full_title": [{"value": "Page 1, the long title"}], "field_weight": [{"value": 1}], "field_model": [{"target_id": 3}], "field_member_of": [{"target_id": 1}]},
+		{"nid": [{"value": 3}], "field_weight": [{"value": 2}], "field_member_of": [{"target_id": 1}]}
+	]`))
+	require.NoError(t, err)
+	source := NewMemorySource(nodes, nil, MapTerms{3: NewTerm("islandora_models", "Page")})
+
+	members, err := SortedMembers(source, 1)
+	require.NoError(t, err)
+	titles := []string{}
+	for _, member := range members {
+		titles = append(titles, Title(member))
+	}
+	assert.Equal(t, []string{"Page 1, the long title", "3", "Page 2", "Loose page"}, titles)
+
+	model, err := Model(members[0], source)
+	require.NoError(t, err)
+	assert.Equal(t, "Page", model)
+	model, err = Model(members[1], source)
+	require.NoError(t, err)
+	assert.Empty(t, model)
+
+	node, err := source.Node(1)
+	require.NoError(t, err)
+	assert.Equal(t, "A book", Title(node))
+	_, err = source.Node(2)
+	assert.ErrorIs(t, err, ErrNotFound)
+	members, err = source.Members(4)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
+
+func TestFetchNodeFresh(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			http.Error(w, "gone", status)
+			return
+		}
+		fmt.Fprint(w, `{"nid": [{"value": 1}], "title": [{"value": "Parent"}]}`)
+	}))
+	defer server.Close()
+
+	url := server.URL + "/node/1?_format=json"
+	node, err := FetchNodeFresh(url)
+	require.NoError(t, err)
+	assert.Equal(t, "Parent", Title(node))
+
+	// a node deleted since it was cached is not found
+	status = http.StatusNotFound
+	_, err = FetchNode(url)
+	require.NoError(t, err)
+	_, err = FetchNodeFresh(url)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
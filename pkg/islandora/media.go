@@ -0,0 +1,107 @@
+package islandora
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+// Media is a media entity attached to a node with field_media_of
+type Media struct {
+	Mid       model.IntField             `json:"mid"`
+	Bundle    model.ConfigReferenceField `json:"bundle"`
+	Name      model.GenericField         `json:"name"`
+	Changed   model.GenericField         `json:"changed"`
+	MediaOf   model.EntityReferenceField `json:"field_media_of"`
+	MediaUse  model.EntityReferenceField `json:"field_media_use"`
+	MimeType  model.GenericField         `json:"field_mime_type"`
+	FileSize  model.IntField             `json:"field_file_size"`
+	Image     model.ImageField           `json:"field_media_image"`
+	File      model.FileField            `json:"field_media_file"`
+	Document  model.FileField            `json:"field_media_document"`
+	AudioFile model.FileField            `json:"field_media_audio_file"`
+	VideoFile model.FileField            `json:"field_media_video_file"`
+}
+
+// FetchMedia loads the media of a node
+// this requires a REST export of the node's media at /node/{nid}/media
+func FetchMedia(baseUrl string, nid int) ([]Media, error) {
+	return fetchJson[[]Media](fmt.Sprintf("%s/node/%d/media?_format=json", baseUrl, nid))
+}
+
+// FileUrl returns the URL of the media's source file
+func (m Media) FileUrl() string {
+	if len(m.Image) > 0 {
+		return m.Image[0].Url
+	}
+	for _, field := range []model.FileField{m.File, m.Document, m.AudioFile, m.VideoFile} {
+		if len(field) > 0 {
+			return field[0].Url
+		}
+	}
+
+	return ""
+}
+
+// Mime returns the MIME type of the media's source file
+func (m Media) Mime() string {
+	if len(m.MimeType) == 0 {
+		return ""
+	}
+
+	return m.MimeType[0].Value
+}
+
+// Size returns the size in bytes of the media's source file, 0 when unknown
+func (m Media) Size() int {
+	if len(m.FileSize) == 0 {
+		return 0
+	}
+
+	return m.FileSize[0].Value
+}
+
+// Dimensions returns the width and height of an image media, 0 when unknown
+func (m Media) Dimensions() (int, int) {
+	if len(m.Image) == 0 {
+		return 0, 0
+	}
+
+	return m.Image[0].Width, m.Image[0].Height
+}
+
+// Uses returns the names of the media's media use terms e.g. Service File,
+// none without terms to load them from
+func (m Media) Uses(terms TermLookup) ([]string, error) {
+	uses := []string{}
+	if terms == nil {
+		return uses, nil
+	}
+	for _, ref := range m.MediaUse {
+		term, err := terms.Term(ref.TargetId)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load media use %d: %v", ref.TargetId, err)
+		}
+		for _, name := range term.Name {
+			uses = append(uses, name.Value)
+		}
+	}
+
+	return uses, nil
+}
+
+// MediaWithUse returns the first media with the media use named use
+func MediaWithUse(media []Media, use string, terms TermLookup) (*Media, error) {
+	for i := range media {
+		uses, err := media[i].Uses(terms)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(uses, use) {
+			return &media[i], nil
+		}
+	}
+
+	return nil, nil
+}
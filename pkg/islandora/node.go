@@ -105,6 +105,17 @@ func FetchNode(url string) (*api.IslandoraObject, error) {
 	return &obj, nil
 }
 
+// FetchNodeFresh loads a node skipping the on-disk cache,
+// so a node deleted or unpublished since it was cached is not found
+func FetchNodeFresh(url string) (*api.IslandoraObject, error) {
+	node, err := fetchFresh[api.IslandoraObject](url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
 // breadth first search of all descendants for a node
 func FetchNodes(baseUrl string, nid int) ([]*api.IslandoraObject, error) {
 	var allNodes []*api.IslandoraObject
@@ -0,0 +1,81 @@
+package islandora
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+// Paragraph is the raw JSON of a paragraph entity, keyed by field name.
+// Use FetchParagraphsAs with a generated Paragraph* struct for typed access.
+type Paragraph map[string]json.RawMessage
+
+// Bundle returns the paragraph type
+func (p Paragraph) Bundle() string {
+	var t model.ConfigReferenceField
+	if err := json.Unmarshal(p["type"], &t); err != nil || len(t) == 0 {
+		return ""
+	}
+
+	return t[0].TargetId
+}
+
+// FetchParagraph loads the paragraph revision an entity_reference_revisions item points to
+// this requires the paragraph REST resource to be enabled on the site
+func FetchParagraph(baseUrl string, ref model.EntityReferenceRevisions) (Paragraph, error) {
+	return FetchParagraphAs[Paragraph](baseUrl, ref)
+}
+
+// FetchParagraphs loads every paragraph referenced by an entity_reference_revisions field
+func FetchParagraphs(baseUrl string, field model.EntityReferenceRevisionsField) ([]Paragraph, error) {
+	return FetchParagraphsAs[Paragraph](baseUrl, field)
+}
+
+// FetchParagraphAs loads the revision of a paragraph an entity_reference_revisions item points to
+// decoded as T, e.g. a generated Paragraph* struct.
+// The revision is requested with a revision query parameter, and an error is returned
+// when the site responds with a different revision rather than using it silently.
+func FetchParagraphAs[T any](baseUrl string, ref model.EntityReferenceRevisions) (T, error) {
+	var p T
+	url := fmt.Sprintf("%s/entity/paragraph/%d?_format=json", baseUrl, ref.TargetId)
+	if ref.TargetRevisionId != 0 {
+		url = fmt.Sprintf("%s&revision=%d", url, ref.TargetRevisionId)
+	}
+	raw, err := fetchJson[Paragraph](url)
+	if err != nil {
+		return p, err
+	}
+
+	if ref.TargetRevisionId != 0 {
+		var revision model.IntField
+		if err := json.Unmarshal(raw["revision_id"], &revision); err != nil || len(revision) == 0 {
+			return p, fmt.Errorf("paragraph %d has no revision_id", ref.TargetId)
+		}
+		if revision[0].Value != ref.TargetRevisionId {
+			return p, fmt.Errorf("requested revision %d of paragraph %d but the site returned revision %d", ref.TargetRevisionId, ref.TargetId, revision[0].Value)
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+
+	return p, err
+}
+
+// FetchParagraphsAs loads the paragraph revisions referenced by an entity_reference_revisions field, in order
+func FetchParagraphsAs[T any](baseUrl string, field model.EntityReferenceRevisionsField) ([]T, error) {
+	paragraphs := make([]T, 0, len(field))
+	for _, ref := range field {
+		p, err := FetchParagraphAs[T](baseUrl, ref)
+		if err != nil {
+			return nil, err
+		}
+		paragraphs = append(paragraphs, p)
+	}
+
+	return paragraphs, nil
+}
@@ -0,0 +1,163 @@
+package islandora
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+)
+
+// Source loads the members, media and terms of the objects
+// documents like IIIF manifests and METS are built from
+type Source interface {
+	TermLookup
+	Members(nid int) ([]*api.IslandoraObject, error)
+	Media(nid int) ([]Media, error)
+}
+
+// Site is a Source backed by an Islandora site
+type Site struct {
+	SiteTerms
+}
+
+func NewSite(baseUrl string) Site {
+	return Site{SiteTerms{BaseUrl: baseUrl}}
+}
+
+func (s Site) Members(nid int) ([]*api.IslandoraObject, error) {
+	members, err := FetchMembers(fmt.Sprintf("%s/node/%d/members?_format=json", s.BaseUrl, nid))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*api.IslandoraObject, len(members))
+	for i, member := range members {
+		nodes[i], err = FetchNode(fmt.Sprintf("%s/node/%s?_format=json", s.BaseUrl, member.Nid))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
+
+func (s Site) Media(nid int) ([]Media, error) {
+	return FetchMedia(s.BaseUrl, nid)
+}
+
+// MemorySource is a Source for nodes already loaded e.g. with ReadNodes,
+// a node's members are the nodes whose field_member_of references it
+type MemorySource struct {
+	MapTerms
+	nodes   map[int]*api.IslandoraObject
+	members map[int][]*api.IslandoraObject
+	media   map[int][]Media
+}
+
+func NewMemorySource(nodes []*api.IslandoraObject, media map[int][]Media, terms MapTerms) *MemorySource {
+	s := &MemorySource{
+		MapTerms: terms,
+		nodes:    map[int]*api.IslandoraObject{},
+		members:  map[int][]*api.IslandoraObject{},
+		media:    media,
+	}
+	for _, node := range nodes {
+		s.nodes[NodeId(node)] = node
+		if node.FieldMemberOf == nil {
+			continue
+		}
+		for _, parent := range *node.FieldMemberOf {
+			s.members[parent.TargetId] = append(s.members[parent.TargetId], node)
+		}
+	}
+
+	return s
+}
+
+// Node returns the node with a nid
+func (s *MemorySource) Node(nid int) (*api.IslandoraObject, error) {
+	node, ok := s.nodes[nid]
+	if !ok {
+		return nil, fmt.Errorf("node %d: %w", nid, ErrNotFound)
+	}
+
+	return node, nil
+}
+
+func (s *MemorySource) Members(nid int) ([]*api.IslandoraObject, error) {
+	// a copy, SortedMembers sorts in place
+	return slices.Clone(s.members[nid]), nil
+}
+
+func (s *MemorySource) Media(nid int) ([]Media, error) {
+	return s.media[nid], nil
+}
+
+// SortedMembers returns a node's members ordered by field_weight then nid,
+// members without a weight last
+func SortedMembers(source Source, nid int) ([]*api.IslandoraObject, error) {
+	members, err := source.Members(nid)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load members of node %d: %v", nid, err)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		wi, oki := weight(members[i])
+		wj, okj := weight(members[j])
+		if oki != okj {
+			return oki
+		}
+		if wi != wj {
+			return wi < wj
+		}
+		return NodeId(members[i]) < NodeId(members[j])
+	})
+
+	return members, nil
+}
+
+// Model returns the name of a node's field_model term
+func Model(node *api.IslandoraObject, terms TermLookup) (string, error) {
+	if node.FieldModel == nil || len(*node.FieldModel) == 0 {
+		return "", nil
+	}
+	tid := (*node.FieldModel)[0].TargetId
+	term, err := terms.Term(tid)
+	if err != nil {
+		return "", fmt.Errorf("unable to load model %d: %v", tid, err)
+	}
+	if len(term.Name) == 0 {
+		return "", nil
+	}
+
+	return term.Name[0].Value, nil
+}
+
+// NodeId returns a node's nid, 0 when it has none
+func NodeId(node *api.IslandoraObject) int {
+	if node.Nid == nil || len(*node.Nid) == 0 {
+		return 0
+	}
+
+	return (*node.Nid)[0].Value
+}
+
+// Title returns a node's full title, falling back to its title and then its nid
+func Title(node *api.IslandoraObject) string {
+	if node.FieldFullTitle != nil && len(*node.FieldFullTitle) > 0 {
+		return (*node.FieldFullTitle)[0].Value
+	}
+	if node.Title != nil && len(*node.Title) > 0 {
+		return (*node.Title)[0].Value
+	}
+
+	return strconv.Itoa(NodeId(node))
+}
+
+func weight(node *api.IslandoraObject) (int, bool) {
+	if node.FieldWeight == nil || len(*node.FieldWeight) == 0 {
+		return 0, false
+	}
+
+	return (*node.FieldWeight)[0].Value, true
+}
@@ -1,11 +1,6 @@
 package islandora
 
 import (
-	"encoding/json"
-	"log/slog"
-	"net/http"
-	"os"
-
 	"github.com/lehigh-university-libraries/go-islandora/model"
 )
 
@@ -14,44 +9,11 @@ type Term struct {
 }
 
 func FetchTerm(url string) (model.TermResponse, error) {
-	cacheFile := getCacheFilename(url)
-	var term model.TermResponse
-
-	// Try to read from cache first
-	if isCacheValid(cacheFile) {
-		data, err := os.ReadFile(cacheFile)
-		if err == nil {
-			if json.Unmarshal(data, &term) == nil {
-				return term, nil
-			}
-		}
-	}
-
-	req, err := getRequest(url)
-	if err != nil {
-		return term, err
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return term, err
-	}
-
-	err = decodeJsonResponse(resp, &term)
-	if err != nil {
-		return term, err
-	}
-
-	// Cache the result
-	if err := ensureCacheDir(); err == nil {
-		if data, err := json.Marshal(term); err == nil {
-			err = os.WriteFile(cacheFile, data, 0644)
-			if err != nil {
-				slog.Error("Unable to write file", "file", cacheFile, "err", err)
-			}
-		}
-	}
+	return FetchTermAs[model.TermResponse](url)
+}
 
-	return term, nil
+// FetchTermAs decodes a term into T
+// e.g. a Term* struct generated by `generate node-structs --config-dir`
+func FetchTermAs[T any](url string) (T, error) {
+	return fetchJson[T](url)
 }
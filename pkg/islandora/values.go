@@ -0,0 +1,250 @@
+package islandora
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+// TermLookup loads the taxonomy terms nodes reference
+type TermLookup interface {
+	Term(tid int) (model.TermResponse, error)
+}
+
+// SiteTerms looks up terms on an Islandora site
+type SiteTerms struct {
+	BaseUrl string
+}
+
+func (s SiteTerms) Term(tid int) (model.TermResponse, error) {
+	return FetchTerm(fmt.Sprintf("%s/taxonomy/term/%d?_format=json", s.BaseUrl, tid))
+}
+
+// MapTerms looks up terms held in memory by term ID
+type MapTerms map[int]model.TermResponse
+
+func (m MapTerms) Term(tid int) (model.TermResponse, error) {
+	term, ok := m[tid]
+	if !ok {
+		return model.TermResponse{}, fmt.Errorf("term %d: %w", tid, ErrNotFound)
+	}
+
+	return term, nil
+}
+
+// NewTerm returns a term in vocabulary vid with just a name
+func NewTerm(vid, name string) model.TermResponse {
+	return model.TermResponse{
+		Name: model.GenericField{{Value: name}},
+		Vid:  model.ConfigReferenceField{{TargetId: vid}},
+	}
+}
+
+// Value is a field value flattened for crosswalks into other metadata formats
+type Value struct {
+	// Type is a typed text's attr0 or a typed relation's rel_type
+	Type  string
+	Value string
+	// Term is the referenced taxonomy term, Value is its name
+	Term *model.TermResponse
+}
+
+// Vid returns the vocabulary of a term value
+func (v Value) Vid() string {
+	if v.Term == nil || len(v.Term.Vid) == 0 {
+		return ""
+	}
+
+	return v.Term.Vid[0].TargetId
+}
+
+// FieldValues returns the values of a node's field by its machine name e.g. field_genre.
+// Taxonomy terms are loaded with terms, other entity references are returned as IDs.
+func FieldValues(node *api.IslandoraObject, field string, terms TermLookup) ([]Value, error) {
+	f, ok := nodeField(node, field)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s", field)
+	}
+	if f.IsNil() {
+		return nil, nil
+	}
+
+	values := []Value{}
+	switch v := f.Interface().(type) {
+	case *model.GenericField:
+		for _, g := range *v {
+			values = append(values, Value{Value: g.Value})
+		}
+	case *model.EdtfField:
+		for _, e := range *v {
+			values = append(values, Value{Value: e.Value})
+		}
+	case *model.EmailField:
+		for _, e := range *v {
+			values = append(values, Value{Value: e.Value})
+		}
+	case *model.IntField:
+		for _, i := range *v {
+			values = append(values, Value{Value: strconv.Itoa(i.Value)})
+		}
+	case *model.BoolField:
+		for _, b := range *v {
+			values = append(values, Value{Value: strconv.FormatBool(b.Value)})
+		}
+	case *model.LinkField:
+		for _, l := range *v {
+			values = append(values, Value{Value: l.Uri})
+		}
+	case *model.TypedTextField:
+		for _, t := range *v {
+			values = append(values, Value{Type: t.Attr0, Value: t.Value})
+		}
+	case *model.EntityReferenceField:
+		for _, ref := range *v {
+			value := Value{Value: strconv.Itoa(ref.TargetId)}
+			if ref.TargetType == "taxonomy_term" && terms != nil {
+				err := lookupTerm(&value, ref.TargetId, terms)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", field, err)
+				}
+			}
+			values = append(values, value)
+		}
+	case *model.TypedRelationField:
+		for _, rel := range *v {
+			value := Value{Type: rel.RelType, Value: strconv.Itoa(rel.TargetId)}
+			if terms != nil {
+				err := lookupTerm(&value, rel.TargetId, terms)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", field, err)
+				}
+			}
+			values = append(values, value)
+		}
+	// structured values are JSON objects
+	case *model.PartDetailField:
+		for _, p := range *v {
+			values = append(values, Value{Value: p.String()})
+		}
+	case *model.RelatedItemField:
+		for _, r := range *v {
+			values = append(values, Value{Value: r.String()})
+		}
+	case *model.HierarchicalGeographicField:
+		for _, h := range *v {
+			values = append(values, Value{Value: h.String()})
+		}
+	default:
+		m, ok := v.(interface{ MarshalCSV() (string, error) })
+		if !ok {
+			return nil, fmt.Errorf("unsupported field type %T", v)
+		}
+		csv, err := m.MarshalCSV()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range strings.Split(csv, "|") {
+			values = append(values, Value{Value: s})
+		}
+	}
+
+	// skip empty values so callers don't have to
+	populated := values[:0]
+	for _, value := range values {
+		if strings.TrimSpace(value.Value) != "" {
+			populated = append(populated, value)
+		}
+	}
+
+	return populated, nil
+}
+
+// FieldStrings returns just the values of a node's field
+func FieldStrings(node *api.IslandoraObject, field string, terms TermLookup) ([]string, error) {
+	values, err := FieldValues(node, field, terms)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.Value
+	}
+
+	return s, nil
+}
+
+// HasField reports whether field is a node field
+func HasField(field string) bool {
+	_, ok := nodeField(&api.IslandoraObject{}, field)
+	return ok
+}
+
+func lookupTerm(value *Value, tid int, terms TermLookup) error {
+	term, err := terms.Term(tid)
+	if err != nil {
+		return fmt.Errorf("unable to load term %d: %w", tid, err)
+	}
+	if len(term.Name) > 0 {
+		value.Value = term.Name[0].Value
+	}
+	value.Term = &term
+
+	return nil
+}
+
+func nodeField(node *api.IslandoraObject, field string) (reflect.Value, bool) {
+	v := reflect.ValueOf(node).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == field {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// Doi returns the first DOI in a node's field_identifier without a resolver prefix
+func Doi(node *api.IslandoraObject) string {
+	if node.FieldIdentifier == nil {
+		return ""
+	}
+	for _, id := range *node.FieldIdentifier {
+		if strings.EqualFold(id.Attr0, "doi") && id.Value != "" {
+			doi := strings.TrimPrefix(id.Value, "https://doi.org/")
+			doi = strings.TrimPrefix(doi, "http://dx.doi.org/")
+			return strings.TrimPrefix(doi, "doi:")
+		}
+	}
+
+	return ""
+}
+
+// PartNumber returns the number of the first field_part_detail with one of the types e.g. volume or issue
+func PartNumber(node *api.IslandoraObject, types ...string) string {
+	if node.FieldPartDetail == nil {
+		return ""
+	}
+	for _, detail := range *node.FieldPartDetail {
+		if detail.Number != "" && slices.Contains(types, strings.ToLower(detail.Type)) {
+			return detail.Number
+		}
+	}
+
+	return ""
+}
+
+// Pages returns the first and last page of a node's page part detail, written like 12-20
+func Pages(node *api.IslandoraObject) (string, string) {
+	pages := PartNumber(node, "page", "pages")
+	first, last, _ := strings.Cut(strings.NewReplacer("–", "-", "—", "-", " ", "").Replace(pages), "-")
+
+	return first, last
+}
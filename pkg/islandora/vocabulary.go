@@ -0,0 +1,47 @@
+package islandora
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type VocabularyTerm struct {
+	Tid  int    `json:"drupal_internal__tid"`
+	Name string `json:"name"`
+}
+
+type jsonApiTerms struct {
+	Data []struct {
+		Attributes VocabularyTerm `json:"attributes"`
+	} `json:"data"`
+	Links struct {
+		Next struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"links"`
+}
+
+// FetchVocabulary loads every term in a vocabulary
+// this requires JSON:API to be enabled on the site.
+// Pages skip the on-disk cache so terms created since the last run are seen.
+func FetchVocabulary(baseUrl, vid string) ([]VocabularyTerm, error) {
+	params := url.Values{}
+	params.Set(fmt.Sprintf("fields[taxonomy_term--%s]", vid), "drupal_internal__tid,name")
+	params.Set("page[limit]", "50")
+	params.Set("sort", "drupal_internal__tid")
+	next := fmt.Sprintf("%s/jsonapi/taxonomy_term/%s?%s", baseUrl, vid, params.Encode())
+
+	terms := []VocabularyTerm{}
+	for next != "" {
+		page, err := fetchFresh[jsonApiTerms](next)
+		if err != nil {
+			return nil, err
+		}
+		for _, term := range page.Data {
+			terms = append(terms, term.Attributes)
+		}
+		next = page.Links.Next.Href
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,465 @@
+package marc
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/proquest"
+)
+
+type Options struct {
+	// Mapping is used to read nodes, and its relators for relator terms
+	Mapping *mods.Mapping
+	// Terms resolves taxonomy terms, linked agents are skipped when nil
+	Terms islandora.TermLookup
+	// BaseUrl is used for the 856 link to the node
+	BaseUrl string
+	// Institution is the degree granting institution when the ETD doesn't name one
+	Institution string
+	// Place is the place of publication in 264 e.g. Bethlehem, Pennsylvania
+	Place string
+	// PlaceCode is the MARC country code in 008 e.g. pau, default xx
+	PlaceCode string
+	// Agency is the MARC organization code of the cataloging agency in 040
+	Agency string
+	// Date is the date entered on file in 008, default today
+	Date time.Time
+}
+
+// Etd is an electronic thesis or dissertation, read from a node or a ProQuest submission
+type Etd struct {
+	// Id is the control number, the node ID for nodes
+	Id    string
+	Title string
+	// Agents are the authors, advisors and committee members with MARC relator codes
+	Agents      []islandora.Agent
+	Degree      string
+	DegreeLevel string
+	Department  string
+	Institution string
+	Year        string
+	Pages       int
+	Abstract    string
+	// Subjects are controlled LCSH topics, Keywords are uncontrolled terms
+	Subjects []string
+	Keywords []string
+	// Language is a MARC language code
+	Language string
+	Doi      string
+	Url      string
+	// Embargo is the YYYY-MM-DD date the ETD is embargoed until
+	Embargo string
+}
+
+// indefinite is the embargo date ProQuest's "never deliver" is recorded as
+const indefinite = "2999-12-31"
+
+// languages maps ISO 639-1 codes to MARC language codes
+var languages = map[string]string{
+	"ar": "ara",
+	"de": "ger",
+	"en": "eng",
+	"es": "spa",
+	"fr": "fre",
+	"it": "ita",
+	"ja": "jpn",
+	"ko": "kor",
+	"pt": "por",
+	"ru": "rus",
+	"zh": "chi",
+}
+
+var (
+	pagesRegex = regexp.MustCompile(`^\s*([0-9]+)`)
+	blockRegex = regexp.MustCompile(`(?i)</p>|<br\s*/?>`)
+	tagRegex   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// FromSubmission reads an ETD from a ProQuest submission
+func FromSubmission(submission proquest.DISSSubmission) *Etd {
+	d := submission.Description
+	e := &Etd{
+		Title:       strings.TrimSpace(d.Title),
+		Degree:      d.Degree,
+		DegreeLevel: d.DegreeLevel,
+		Department:  d.Department,
+		Institution: d.Institution.Name,
+		Pages:       d.PageCount,
+		Abstract:    strings.Join(submission.Content.Abstract.Paragraphs, " "),
+		Keywords:    []string{},
+		Language:    language(d.Categorization.Language),
+		Embargo:     submission.EmbargoDate(),
+	}
+	if e.Department == "" {
+		e.Department = d.Institution.Department
+	}
+	if year, _, _ := strings.Cut(strings.TrimSpace(d.Dates.CompletionDate), "-"); len(year) == 4 {
+		e.Year = year
+	}
+
+	for _, author := range submission.Authorship.Authors {
+		e.Agents = append(e.Agents, islandora.Agent{
+			Name:    submissionName(author.Name),
+			Relator: "cre",
+			Vid:     "person",
+			Orcid:   strings.TrimPrefix(author.ORCiD, "https://orcid.org/"),
+		})
+	}
+	for _, advisor := range d.Advisors {
+		e.Agents = append(e.Agents, islandora.Agent{Name: submissionName(advisor.Name), Relator: "ths", Vid: "person"})
+	}
+	for _, category := range d.Categorization.Categories {
+		e.Keywords = append(e.Keywords, category.Description)
+	}
+	for _, keywords := range d.Categorization.Keywords {
+		for _, keyword := range strings.Split(keywords, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				e.Keywords = append(e.Keywords, keyword)
+			}
+		}
+	}
+
+	return e
+}
+
+func submissionName(n proquest.DISSName) string {
+	name := strings.TrimSpace(n.Surname) + ", " + strings.TrimSpace(strings.Join([]string{n.First, n.Middle}, " "))
+	if n.Suffix != "" {
+		name += ", " + n.Suffix
+	}
+
+	return name
+}
+
+// FromNode reads an ETD from a node
+func FromNode(node *api.IslandoraObject, opts Options) (*Etd, error) {
+	record, err := mods.FromNode(node, mods.Options{Mapping: opts.Mapping, Terms: opts.Terms, BaseUrl: opts.BaseUrl})
+	if err != nil {
+		return nil, err
+	}
+	agents, err := islandora.LinkedAgents(node, opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Etd{
+		Id:       node.Nid.String(),
+		Agents:   agents,
+		Keywords: []string{},
+		Doi:      islandora.Doi(node),
+	}
+	if opts.BaseUrl != "" {
+		e.Url = fmt.Sprintf("%s/node/%s", strings.TrimSuffix(opts.BaseUrl, "/"), e.Id)
+	}
+	for field, value := range map[string]*string{
+		"field_degree_name":       &e.Degree,
+		"field_degree_level":      &e.DegreeLevel,
+		"field_department_name":   &e.Department,
+		"field_edtf_date_embargo": &e.Embargo,
+	} {
+		values, err := islandora.FieldStrings(node, field, opts.Terms)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			*value = values[0]
+		}
+	}
+	for _, agent := range agents {
+		if agent.Relator == "dgg" && e.Institution == "" {
+			e.Institution = agent.Name
+		}
+	}
+
+	for _, title := range record.TitleInfo {
+		if title.Type == "" {
+			e.Title = title.Title
+			if title.SubTitle != "" {
+				e.Title += ": " + title.SubTitle
+			}
+			break
+		}
+	}
+	for _, origin := range record.OriginInfo {
+		for _, dates := range [][]mods.Date{origin.DateIssued, origin.DateCreated, origin.CopyrightDate} {
+			for _, d := range dates {
+				parsed, err := edtf.Parse(d.Value)
+				if err != nil || e.Year != "" {
+					continue
+				}
+				if year, ok := parsed.Start.YearInt(); ok {
+					e.Year = strconv.Itoa(year)
+				}
+			}
+		}
+	}
+	for _, physical := range record.PhysicalDescription {
+		for _, extent := range physical.Extent {
+			if m := pagesRegex.FindStringSubmatch(extent.Value); m != nil && e.Pages == 0 {
+				e.Pages, _ = strconv.Atoi(m[1])
+			}
+		}
+	}
+	paragraphs := []string{}
+	for _, a := range record.Abstract {
+		paragraphs = append(paragraphs, strings.Join(strings.Fields(html.UnescapeString(tagRegex.ReplaceAllString(blockRegex.ReplaceAllString(a.Value, " "), ""))), " "))
+	}
+	e.Abstract = strings.Join(paragraphs, " ")
+
+	keywords, err := islandora.FieldStrings(node, "field_keywords", opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+	e.Keywords = append(e.Keywords, keywords...)
+	for _, s := range record.Subject {
+		for _, topic := range s.Topic {
+			if s.Authority == "lcsh" || topic.Authority == "lcsh" {
+				e.Subjects = append(e.Subjects, topic.Value)
+			}
+		}
+	}
+	for _, l := range record.Language {
+		for _, t := range l.LanguageTerm {
+			if e.Language == "" && t.Type == "code" {
+				e.Language = language(t.Value)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// language returns the MARC code for an ISO 639 code
+func language(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if mapped, ok := languages[code]; ok {
+		return mapped
+	}
+	if len(code) == 3 {
+		return code
+	}
+
+	return ""
+}
+
+// Record builds the MARC 21 bibliographic record for the ETD
+func (e *Etd) Record(opts Options) *Record {
+	if opts.Mapping == nil {
+		opts.Mapping = mods.DefaultMapping()
+	}
+	if opts.Date.IsZero() {
+		opts.Date = time.Now()
+	}
+	institution := e.Institution
+	if institution == "" {
+		institution = opts.Institution
+	}
+
+	r := &Record{Leader: "00000nam a2200000 i 4500"}
+	if e.Id != "" {
+		r.Control("001", e.Id)
+	}
+	r.Control("006", "m     o  d        ")
+	r.Control("007", "cr |n|||||||||")
+	r.Control("008", e.fixedLength(opts))
+
+	r.Data("024", "7", " ", "a", e.Doi, "2", doiSource(e.Doi))
+	if opts.Agency != "" {
+		r.Data("040", " ", " ", "a", opts.Agency, "b", "eng", "e", "rda", "c", opts.Agency)
+	}
+
+	var main *islandora.Agent
+	for i, agent := range e.Agents {
+		if (agent.Relator == "aut" || agent.Relator == "cre") && agent.Personal() {
+			main = &e.Agents[i]
+			break
+		}
+	}
+	if main != nil {
+		r.Data("100", "1", " ", "a", punctuate(main.Name, ","), "e", punctuate(e.relatorTerm(main.Relator, opts), "."), "1", main.OrcidUrl())
+	}
+
+	title, subtitle, _ := strings.Cut(e.Title, ": ")
+	responsibility := ""
+	if main != nil {
+		family, given := main.FamilyGiven()
+		responsibility = strings.TrimSpace(given + " " + family)
+	}
+	ind1 := "0"
+	if main != nil {
+		ind1 = "1"
+	}
+	switch {
+	case subtitle != "" && responsibility != "":
+		r.Data("245", ind1, nonfiling(title), "a", title+" :", "b", subtitle+" /", "c", punctuate("by "+responsibility, "."))
+	case subtitle != "":
+		r.Data("245", ind1, nonfiling(title), "a", title+" :", "b", punctuate(subtitle, "."))
+	case responsibility != "":
+		r.Data("245", ind1, nonfiling(title), "a", title+" /", "c", punctuate("by "+responsibility, "."))
+	default:
+		r.Data("245", ind1, nonfiling(title), "a", punctuate(title, "."))
+	}
+
+	place := opts.Place
+	if place == "" {
+		place = "[Place of publication not identified]"
+	}
+	publisher := institution
+	if publisher == "" {
+		publisher = "[publisher not identified]"
+	}
+	year := e.Year
+	if year == "" {
+		year = "[date of publication not identified]"
+	}
+	r.Data("264", " ", "1", "a", place+" :", "b", publisher+",", "c", punctuate(year, "."))
+
+	extent := "1 online resource"
+	if e.Pages > 0 {
+		extent = fmt.Sprintf("1 online resource (%d pages)", e.Pages)
+	}
+	r.Data("300", " ", " ", "a", extent)
+	r.Data("336", " ", " ", "a", "text", "b", "txt", "2", "rdacontent")
+	r.Data("337", " ", " ", "a", "computer", "b", "c", "2", "rdamedia")
+	r.Data("338", " ", " ", "a", "online resource", "b", "cr", "2", "rdacarrier")
+
+	degree := e.Degree
+	if degree == "" {
+		degree = e.DegreeLevel
+	}
+	department := ""
+	if e.Department != "" {
+		department = "Department of " + strings.TrimPrefix(e.Department, "Department of ")
+	}
+	r.Data("502", " ", " ", "b", degree, "c", strings.Join(nonEmpty(institution, department), ", "), "d", punctuate(e.Year, "."))
+
+	switch e.Embargo {
+	case "":
+	case indefinite:
+		r.Data("506", "1", " ", "a", "Access restricted indefinitely.", "f", "Embargo", "2", "star")
+	default:
+		r.Data("506", "1", " ", "a", fmt.Sprintf("Access restricted until %s.", e.Embargo), "g", e.Embargo, "f", "Embargo", "2", "star")
+	}
+
+	r.Data("520", "3", " ", "a", e.Abstract)
+	r.Data("546", " ", " ", "a", languageNote(e.Language))
+	for _, subject := range e.Subjects {
+		r.Data("650", " ", "0", "a", punctuate(subject, "."))
+	}
+	for _, keyword := range e.Keywords {
+		r.Data("653", " ", " ", "a", keyword)
+	}
+	r.Data("655", " ", "7", "a", "Academic theses.", "2", "lcgft")
+
+	for i, agent := range e.Agents {
+		if &e.Agents[i] == main || agent.Relator == "dgg" {
+			continue
+		}
+		if agent.Personal() {
+			r.Data("700", "1", " ", "a", punctuate(agent.Name, ","), "e", punctuate(e.relatorTerm(agent.Relator, opts), "."), "1", agent.OrcidUrl())
+		} else {
+			r.Data("710", "2", " ", "a", punctuate(agent.Name, ","), "e", punctuate(e.relatorTerm(agent.Relator, opts), "."))
+		}
+	}
+	if institution != "" {
+		if e.Department != "" {
+			r.Data("710", "2", " ", "a", institution+".", "b", punctuate(strings.TrimPrefix(e.Department, "Department of "), ","), "e", "degree granting institution.")
+		} else {
+			r.Data("710", "2", " ", "a", institution+",", "e", "degree granting institution.")
+		}
+	}
+
+	if e.Doi != "" {
+		r.Data("856", "4", "0", "u", "https://doi.org/"+e.Doi)
+	}
+	r.Data("856", "4", "0", "u", e.Url)
+
+	return r
+}
+
+// fixedLength builds the 008 for an online thesis
+func (e *Etd) fixedLength(opts Options) string {
+	year := e.Year
+	if len(year) != 4 {
+		year = "uuuu"
+	}
+	place := opts.PlaceCode
+	if place == "" {
+		place = "xx"
+	}
+	lang := e.Language
+	if len(lang) != 3 {
+		lang = "und"
+	}
+
+	return opts.Date.Format("060102") + // date entered on file
+		"s" + year + "    " + // single date
+		fmt.Sprintf("%-3s", place) +
+		"    " + // illustrations
+		" " + // target audience
+		"o" + // form of item: online
+		"m   " + // nature of contents: theses
+		" 000 0 " + // government publication, conference, festschrift, index, undefined, literary form, biography
+		lang +
+		" d" // modified record, cataloging source
+}
+
+// relatorTerm is the lower case role text for a relator code e.g. thesis advisor
+func (e *Etd) relatorTerm(code string, opts Options) string {
+	if term, ok := opts.Mapping.Relators[code]; ok {
+		return strings.ToLower(term)
+	}
+
+	return code
+}
+
+// nonfiling is the 245 second indicator, the number of characters in a leading article
+func nonfiling(title string) string {
+	lower := strings.ToLower(title)
+	for _, article := range []string{"the ", "an ", "a "} {
+		if strings.HasPrefix(lower, article) {
+			return strconv.Itoa(len(article))
+		}
+	}
+
+	return "0"
+}
+
+func doiSource(doi string) string {
+	if doi == "" {
+		return ""
+	}
+
+	return "doi"
+}
+
+func languageNote(code string) string {
+	names := map[string]string{
+		"ara": "Arabic", "chi": "Chinese", "fre": "French", "ger": "German", "ita": "Italian",
+		"jpn": "Japanese", "kor": "Korean", "por": "Portuguese", "rus": "Russian", "spa": "Spanish",
+	}
+	if name, ok := names[code]; ok {
+		return "In " + name + "."
+	}
+
+	return ""
+}
+
+func nonEmpty(values ...string) []string {
+	s := []string{}
+	for _, v := range values {
+		if v != "" {
+			s = append(s, v)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,119 @@
+// Package marc writes MARC 21 bibliographic records as MARCXML
+package marc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+const (
+	Namespace      = "http://www.loc.gov/MARC21/slim"
+	SchemaLocation = "http://www.loc.gov/MARC21/slim http://www.loc.gov/standards/marcxml/schema/MARC21slim.xsd"
+	xsiNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+type Collection struct {
+	XMLName        xml.Name  `xml:"http://www.loc.gov/MARC21/slim collection"`
+	XmlnsXsi       string    `xml:"xmlns:xsi,attr"`
+	SchemaLocation string    `xml:"xsi:schemaLocation,attr"`
+	Records        []*Record `xml:"record"`
+}
+
+type Record struct {
+	XMLName       xml.Name       `xml:"record"`
+	Leader        string         `xml:"leader"`
+	ControlFields []ControlField `xml:"controlfield"`
+	DataFields    []DataField    `xml:"datafield"`
+}
+
+type ControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type DataField struct {
+	Tag       string     `xml:"tag,attr"`
+	Ind1      string     `xml:"ind1,attr"`
+	Ind2      string     `xml:"ind2,attr"`
+	Subfields []Subfield `xml:"subfield"`
+}
+
+type Subfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Control adds a control field (001-009)
+func (r *Record) Control(tag, value string) {
+	r.ControlFields = append(r.ControlFields, ControlField{Tag: tag, Value: value})
+}
+
+// Data adds a data field, subfields are given as code, value pairs
+// and those with an empty value are left out. Nothing is added when
+// all of them are empty.
+func (r *Record) Data(tag, ind1, ind2 string, subfields ...string) {
+	field := DataField{Tag: tag, Ind1: ind1, Ind2: ind2}
+	for i := 0; i+1 < len(subfields); i += 2 {
+		if subfields[i+1] != "" {
+			field.Subfields = append(field.Subfields, Subfield{Code: subfields[i], Value: subfields[i+1]})
+		}
+	}
+	if len(field.Subfields) > 0 {
+		r.DataFields = append(r.DataFields, field)
+	}
+}
+
+// Field returns the first data field with a tag
+func (r *Record) Field(tag string) *DataField {
+	for i := range r.DataFields {
+		if r.DataFields[i].Tag == tag {
+			return &r.DataFields[i]
+		}
+	}
+
+	return nil
+}
+
+// Subfield returns the first subfield with a code
+func (f *DataField) Subfield(code string) string {
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			return s.Value
+		}
+	}
+
+	return ""
+}
+
+// MarshalCollection encodes records as a MARCXML collection
+func MarshalCollection(records []*Record) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(Collection{
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: SchemaLocation,
+		Records:        records,
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
+
+// punctuate ends s with the ISBD punctuation p, unless it already ends with a mark
+func punctuate(s, p string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	if strings.HasSuffix(s, p) || (p == "." && strings.ContainsAny(s[len(s)-1:], ".?!")) {
+		return s
+	}
+
+	return s + p
+}
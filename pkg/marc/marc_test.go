@@ -0,0 +1,128 @@
+package marc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/proquest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var terms = islandora.MapTerms{
+	1: islandora.NewTerm("person", "Doe, Jane Q."),
+	2: islandora.NewTerm("person", "Smith, Ann"),
+	3: islandora.NewTerm("corporate_body", "Lehigh University"),
+	4: islandora.NewTerm("lcsh_topic", "Bridges--Design and construction"),
+	5: islandora.NewTerm("keywords", "steel"),
+	6: islandora.NewTerm("department", "Civil and Environmental Engineering"),
+	7: islandora.NewTerm("degree_name", "Ph.D."),
+}
+
+var date = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFromNode(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"nid": [{"value": 42}],
+		"title": [{"value": "The bridge: a study"}],
+		"field_linked_agent": [
+			{"target_id": 1, "rel_type": "relators:cre"},
+			{"target_id": 2, "rel_type": "relators:ths"},
+			{"target_id": 3, "rel_type": "relators:dgg"}
+		],
+		"field_edtf_date_issued": [{"value": "2023-05"}],
+		"field_edtf_date_embargo": [{"value": "2025-05-01"}],
+		"field_extent": [{"value": "182 pages"}],
+		"field_abstract": [{"value": "<p>Bridges &amp; <b>steel</b>.</p><p>Two.</p>"}],
+		"field_lcsh_topic": [{"target_id": 4, "target_type": "taxonomy_term"}],
+		"field_keywords": [{"target_id": 5, "target_type": "taxonomy_term"}],
+		"field_department_name": [{"target_id": 6, "target_type": "taxonomy_term"}],
+		"field_degree_name": [{"target_id": 7, "target_type": "taxonomy_term"}]
+	}`), &node))
+
+	opts := Options{Terms: terms, BaseUrl: "https://example.com", Place: "Bethlehem, Pennsylvania", PlaceCode: "pau", Agency: "PBL", Date: date}
+	etd, err := FromNode(&node, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "The bridge: a study", etd.Title)
+	assert.Equal(t, "2023", etd.Year)
+	assert.Equal(t, 182, etd.Pages)
+	assert.Equal(t, "Bridges & steel. Two.", etd.Abstract)
+	assert.Equal(t, "Lehigh University", etd.Institution)
+	assert.Equal(t, []string{"Bridges--Design and construction"}, etd.Subjects)
+	assert.Equal(t, []string{"steel"}, etd.Keywords)
+
+	r := etd.Record(opts)
+	assert.Equal(t, "00000nam a2200000 i 4500", r.Leader)
+	assert.Equal(t, ControlField{Tag: "001", Value: "42"}, r.ControlFields[0])
+	assert.Equal(t, "240501s2023    pau     om    000 0 und d", r.ControlFields[3].Value)
+	assert.Len(t, r.ControlFields[3].Value, 40)
+
+	assert.Equal(t, DataField{Tag: "100", Ind1: "1", Ind2: " ", Subfields: []Subfield{{"a", "Doe, Jane Q.,"}, {"e", "creator."}}}, *r.Field("100"))
+	assert.Equal(t, DataField{Tag: "245", Ind1: "1", Ind2: "4", Subfields: []Subfield{{"a", "The bridge :"}, {"b", "a study /"}, {"c", "by Jane Q. Doe."}}}, *r.Field("245"))
+	assert.Equal(t, "Bethlehem, Pennsylvania :", r.Field("264").Subfield("a"))
+	assert.Equal(t, "Lehigh University,", r.Field("264").Subfield("b"))
+	assert.Equal(t, "1 online resource (182 pages)", r.Field("300").Subfield("a"))
+	assert.Equal(t, DataField{Tag: "502", Ind1: " ", Ind2: " ", Subfields: []Subfield{{"b", "Ph.D."}, {"c", "Lehigh University, Department of Civil and Environmental Engineering"}, {"d", "2023."}}}, *r.Field("502"))
+	assert.Equal(t, "2025-05-01", r.Field("506").Subfield("g"))
+	assert.Equal(t, "Bridges--Design and construction.", r.Field("650").Subfield("a"))
+	assert.Equal(t, "steel", r.Field("653").Subfield("a"))
+	assert.Equal(t, DataField{Tag: "700", Ind1: "1", Ind2: " ", Subfields: []Subfield{{"a", "Smith, Ann,"}, {"e", "thesis advisor."}}}, *r.Field("700"))
+	assert.Equal(t, "Civil and Environmental Engineering,", r.Field("710").Subfield("b"))
+	assert.Equal(t, "https://example.com/node/42", r.Field("856").Subfield("u"))
+	assert.Nil(t, r.Field("024"))
+}
+
+func TestFromNodeWithoutTerms(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"nid": [{"value": 43}],
+		"title": [{"value": "Untitled thesis"}],
+		"field_linked_agent": [{"target_id": 1, "rel_type": "relators:cre"}]
+	}`), &node))
+
+	etd, err := FromNode(&node, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "Untitled thesis", etd.Title)
+	assert.Empty(t, etd.Agents)
+	assert.Nil(t, etd.Record(Options{Date: date}).Field("100"))
+}
+
+func TestFromSubmission(t *testing.T) {
+	var s proquest.DISSSubmission
+	s.Authorship.Authors = []proquest.DISSAuthor{{Name: proquest.DISSName{Surname: "Doe", First: "Jane", Middle: "Q."}, ORCiD: "0000-0002-1825-0097"}}
+	s.Description.Title = "A theory of everything"
+	s.Description.Degree = "M.S."
+	s.Description.Institution.Name = "Lehigh University"
+	s.Description.PageCount = 64
+	s.Description.Dates.CompletionDate = "2022"
+	s.Description.Categorization.Language = "en"
+	s.Description.Categorization.Keywords = []string{"physics, cosmology"}
+	s.Content.Abstract.Paragraphs = []string{"One.", "Two."}
+	s.Repository.Embargo = "never deliver"
+
+	r := FromSubmission(s).Record(Options{Date: date})
+	assert.Equal(t, "240501s2022    xx      om    000 0 eng d", r.ControlFields[2].Value)
+	assert.Equal(t, "https://orcid.org/0000-0002-1825-0097", r.Field("100").Subfield("1"))
+	assert.Equal(t, "2", r.Field("245").Ind2)
+	assert.Equal(t, "A theory of everything /", r.Field("245").Subfield("a"))
+	assert.Equal(t, "1 online resource (64 pages)", r.Field("300").Subfield("a"))
+	assert.Equal(t, "Access restricted indefinitely.", r.Field("506").Subfield("a"))
+	assert.Equal(t, "One. Two.", r.Field("520").Subfield("a"))
+	assert.Equal(t, "physics", r.Field("653").Subfield("a"))
+
+	data, err := MarshalCollection([]*Record{r})
+	require.NoError(t, err)
+	xml := string(data)
+	assert.True(t, strings.HasPrefix(xml, `<?xml version="1.0" encoding="UTF-8"?>
+<collection xmlns="http://www.loc.gov/MARC21/slim"`))
+	assert.Contains(t, xml, `<datafield tag="502" ind1=" " ind2=" ">
+      <subfield code="b">M.S.</subfield>
+      <subfield code="c">Lehigh University</subfield>
+      <subfield code="d">2022.</subfield>
+    </datafield>`)
+}
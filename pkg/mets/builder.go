@@ -0,0 +1,306 @@
+package mets
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/dc"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+// ChecksumTypes are the supported CHECKSUMTYPE values
+var ChecksumTypes = map[string]func() hash.Hash{
+	"MD5":     md5.New,
+	"SHA-1":   sha1.New,
+	"SHA-256": sha256.New,
+	"SHA-512": sha512.New,
+}
+
+// DivTypes are the structMap div types of object models, other models are lower cased
+var DivTypes = map[string]string{
+	"Page":              "page",
+	"Paged Content":     "volume",
+	"Publication Issue": "issue",
+}
+
+type Options struct {
+	Source islandora.Source
+	// BaseUrl is the Islandora site, the OBJID is the node's URL
+	BaseUrl string
+	// Mapping is the MODS mapping descriptive metadata is crosswalked through
+	Mapping *mods.Mapping
+	// Dmd is the descriptive metadata wrapped in dmdSecs, MODS or DC
+	Dmd string
+	// Agent is the organization named as the document's creator in metsHdr
+	Agent string
+	// FileUses are the media uses in the fileSec, in order, default all of them
+	FileUses []string
+	// ChecksumType is one of ChecksumTypes, files have no checksum when empty
+	ChecksumType string
+	// Checksum streams a file's contents into a hash e.g. islandora.HashFile
+	Checksum func(url string, w io.Writer) error
+	// Created is the metsHdr CREATEDATE, default now
+	Created time.Time
+}
+
+type Builder struct {
+	opts Options
+	mets *Mets
+	// groups are the fileGrps by media use
+	groups map[string]*FileGrp
+	uses   []string
+}
+
+func NewBuilder(opts Options) *Builder {
+	opts.BaseUrl = strings.TrimSuffix(opts.BaseUrl, "/")
+	opts.Dmd = strings.ToUpper(opts.Dmd)
+	if opts.Dmd == "" {
+		opts.Dmd = "MODS"
+	}
+	opts.ChecksumType = strings.ToUpper(opts.ChecksumType)
+
+	return &Builder{opts: opts}
+}
+
+// Build describes a node and its members, ordered by field_weight, recursively.
+// Each node is a div in the physical structMap pointing to its media files,
+// and its descriptive metadata and rights are referenced by DMDID and ADMID.
+func (b *Builder) Build(node *api.IslandoraObject) (*Mets, error) {
+	if b.opts.Dmd != "MODS" && b.opts.Dmd != "DC" {
+		return nil, fmt.Errorf("unsupported descriptive metadata %s", b.opts.Dmd)
+	}
+	if b.opts.ChecksumType != "" {
+		if _, ok := ChecksumTypes[b.opts.ChecksumType]; !ok {
+			return nil, fmt.Errorf("unsupported checksum type %s", b.opts.ChecksumType)
+		}
+		if b.opts.Checksum == nil {
+			return nil, fmt.Errorf("a checksum type needs a Checksum func")
+		}
+	}
+	created := b.opts.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	model, err := islandora.Model(node, b.opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	b.groups = map[string]*FileGrp{}
+	b.uses = []string{}
+	b.mets = &Mets{
+		ObjId:  b.objId(node),
+		Label:  islandora.Title(node),
+		Type:   model,
+		Header: Header{CreateDate: created.UTC().Format(time.RFC3339), RecordStatus: "COMPLETE"},
+	}
+	if b.opts.Agent != "" {
+		b.mets.Header.Agent = []Agent{{Role: "CREATOR", Type: "ORGANIZATION", Name: b.opts.Agent}}
+	}
+
+	div, err := b.div(node, 0)
+	if err != nil {
+		return nil, err
+	}
+	b.mets.StructMap = StructMap{Type: "physical", Div: div}
+
+	uses := b.uses
+	if b.opts.FileUses != nil {
+		uses = b.opts.FileUses
+	}
+	for _, use := range uses {
+		if group, ok := b.groups[use]; ok {
+			if b.mets.FileSec == nil {
+				b.mets.FileSec = &FileSec{}
+			}
+			b.mets.FileSec.FileGrp = append(b.mets.FileSec.FileGrp, *group)
+		}
+	}
+
+	return b.mets, nil
+}
+
+func (b *Builder) div(node *api.IslandoraObject, order int) (Div, error) {
+	nid := islandora.NodeId(node)
+	model, err := islandora.Model(node, b.opts.Source)
+	if err != nil {
+		return Div{}, err
+	}
+	div := Div{
+		Type:       divType(node, model),
+		Label:      islandora.Title(node),
+		Order:      order,
+		OrderLabel: islandora.PartNumber(node, "page", "chapter", "section"),
+		DmdId:      fmt.Sprintf("DMD_%d", nid),
+	}
+	if order == 0 {
+		div.OrderLabel = ""
+	}
+
+	record, err := mods.FromNode(node, mods.Options{Mapping: b.opts.Mapping, Terms: b.opts.Source, BaseUrl: b.opts.BaseUrl})
+	if err != nil {
+		return Div{}, err
+	}
+	dmd := MdSec{Id: div.DmdId, MdWrap: MdWrap{MimeType: "text/xml", MdType: b.opts.Dmd}}
+	if b.opts.Dmd == "DC" {
+		dmd.MdWrap.XmlData.Dc = dc.FromMods(record)
+	} else {
+		record.Version = mods.Version
+		dmd.MdWrap.XmlData.Mods = record
+	}
+	b.mets.DmdSec = append(b.mets.DmdSec, dmd)
+
+	if rights := rights(node); rights != nil {
+		div.AdmId = fmt.Sprintf("AMD_%d", nid)
+		b.mets.AmdSec = append(b.mets.AmdSec, AmdSec{
+			Id: div.AdmId,
+			RightsMD: []MdSec{{
+				Id:     fmt.Sprintf("RIGHTS_%d", nid),
+				MdWrap: MdWrap{MimeType: "text/xml", MdType: "METSRIGHTS", XmlData: XmlData{Rights: rights}},
+			}},
+		})
+	}
+
+	media, err := b.opts.Source.Media(nid)
+	if err != nil {
+		return Div{}, fmt.Errorf("unable to load media of node %d: %v", nid, err)
+	}
+	for _, m := range media {
+		file, use, err := b.file(m, nid)
+		if err != nil {
+			return Div{}, err
+		}
+		if file == nil {
+			continue
+		}
+		if _, ok := b.groups[use]; !ok {
+			b.groups[use] = &FileGrp{Use: use}
+			b.uses = append(b.uses, use)
+		}
+		b.groups[use].File = append(b.groups[use].File, *file)
+		div.Fptr = append(div.Fptr, Fptr{FileId: file.Id})
+	}
+
+	members, err := islandora.SortedMembers(b.opts.Source, nid)
+	if err != nil {
+		return Div{}, err
+	}
+	for i, member := range members {
+		child, err := b.div(member, i+1)
+		if err != nil {
+			return Div{}, err
+		}
+		div.Div = append(div.Div, child)
+	}
+
+	return div, nil
+}
+
+// file returns the file entry for a media and its fileGrp's use,
+// nil when the media has no file or none of the uses being exported
+func (b *Builder) file(m islandora.Media, nid int) (*File, string, error) {
+	fileUrl := m.FileUrl()
+	if fileUrl == "" || len(m.Mid) == 0 {
+		return nil, "", nil
+	}
+	uses, err := m.Uses(b.opts.Source)
+	if err != nil {
+		return nil, "", err
+	}
+	use := ""
+	for _, u := range uses {
+		if b.opts.FileUses == nil || slices.Contains(b.opts.FileUses, u) {
+			use = u
+			break
+		}
+	}
+	if use == "" {
+		if b.opts.FileUses != nil {
+			return nil, "", nil
+		}
+		use = "Unknown"
+	}
+
+	file := &File{
+		Id:       fmt.Sprintf("FILE_%d", m.Mid[0].Value),
+		MimeType: m.Mime(),
+		Size:     m.Size(),
+		GroupId:  fmt.Sprintf("GRP_%d", nid),
+		FLocat:   FLocat{LocType: "URL", Href: fileUrl},
+	}
+	if b.opts.ChecksumType != "" {
+		h := ChecksumTypes[b.opts.ChecksumType]()
+		err = b.opts.Checksum(fileUrl, h)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to checksum %s: %v", fileUrl, err)
+		}
+		file.Checksum = fmt.Sprintf("%x", h.Sum(nil))
+		file.ChecksumType = b.opts.ChecksumType
+	}
+
+	return file, use, nil
+}
+
+func (b *Builder) objId(node *api.IslandoraObject) string {
+	if b.opts.BaseUrl == "" {
+		return strconv.Itoa(islandora.NodeId(node))
+	}
+
+	return fmt.Sprintf("%s/node/%d", b.opts.BaseUrl, islandora.NodeId(node))
+}
+
+// divType is chapter for nodes with a chapter part detail, otherwise from the model
+func divType(node *api.IslandoraObject, model string) string {
+	if islandora.PartNumber(node, "chapter") != "" {
+		return "chapter"
+	}
+	if t, ok := DivTypes[model]; ok {
+		return t
+	}
+
+	return strings.ToLower(model)
+}
+
+// rights declares field_rights with a METSRights category
+// inferred from rightsstatements.org and Creative Commons URIs
+func rights(node *api.IslandoraObject) *Rights {
+	if node.FieldRights == nil || len(*node.FieldRights) == 0 {
+		return nil
+	}
+	r := &Rights{Category: "OTHER"}
+	for _, v := range *node.FieldRights {
+		if v.Value == "" {
+			continue
+		}
+		r.Declaration = append(r.Declaration, v.Value)
+		if r.Category != "OTHER" {
+			continue
+		}
+		value := strings.ToLower(v.Value)
+		switch {
+		case strings.Contains(value, "/vocab/inc") || strings.Contains(value, "in copyright"):
+			r.Category = "COPYRIGHTED"
+		case strings.Contains(value, "/vocab/noc") || strings.Contains(value, "no copyright") ||
+			strings.Contains(value, "/publicdomain/") || strings.Contains(value, "public domain"):
+			r.Category = "PUBLIC DOMAIN"
+		case strings.Contains(value, "creativecommons.org/licenses/"):
+			r.Category = "LICENSED"
+		}
+	}
+	if len(r.Declaration) == 0 {
+		return nil
+	}
+
+	return r
+}
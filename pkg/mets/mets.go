@@ -0,0 +1,145 @@
+// Package mets packages Islandora objects as METS 1.x documents
+package mets
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/dc"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+const (
+	Namespace       = "http://www.loc.gov/METS/"
+	SchemaLocation  = "http://www.loc.gov/METS/ http://www.loc.gov/standards/mets/mets.xsd http://cosimo.stanford.edu/sdr/metsrights/ http://cosimo.stanford.edu/sdr/metsrights.xsd"
+	RightsNamespace = "http://cosimo.stanford.edu/sdr/metsrights/"
+	xlinkNamespace  = "http://www.w3.org/1999/xlink"
+	xsiNamespace    = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+type Mets struct {
+	XMLName        xml.Name `xml:"http://www.loc.gov/METS/ mets"`
+	XmlnsXlink     string   `xml:"xmlns:xlink,attr"`
+	XmlnsRights    string   `xml:"xmlns:rts,attr"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	ObjId          string   `xml:"OBJID,attr,omitempty"`
+	Label          string   `xml:"LABEL,attr,omitempty"`
+	Type           string   `xml:"TYPE,attr,omitempty"`
+
+	Header    Header    `xml:"metsHdr"`
+	DmdSec    []MdSec   `xml:"dmdSec"`
+	AmdSec    []AmdSec  `xml:"amdSec"`
+	FileSec   *FileSec  `xml:"fileSec"`
+	StructMap StructMap `xml:"structMap"`
+}
+
+type Header struct {
+	CreateDate   string  `xml:"CREATEDATE,attr"`
+	RecordStatus string  `xml:"RECORDSTATUS,attr,omitempty"`
+	Agent        []Agent `xml:"agent"`
+}
+
+type Agent struct {
+	Role string `xml:"ROLE,attr"`
+	Type string `xml:"TYPE,attr"`
+	Name string `xml:"name"`
+}
+
+// MdSec is a dmdSec, or a rightsMD in an amdSec, with its metadata wrapped inline
+type MdSec struct {
+	Id     string `xml:"ID,attr"`
+	MdWrap MdWrap `xml:"mdWrap"`
+}
+
+type MdWrap struct {
+	MimeType string  `xml:"MIMETYPE,attr,omitempty"`
+	MdType   string  `xml:"MDTYPE,attr"`
+	Label    string  `xml:"LABEL,attr,omitempty"`
+	XmlData  XmlData `xml:"xmlData"`
+}
+
+// XmlData holds one of the wrapped metadata records
+type XmlData struct {
+	Mods   *mods.Mods `xml:"mods,omitempty"`
+	Dc     *dc.Record `xml:"oai_dc:dc,omitempty"`
+	Rights *Rights    `xml:"rts:RightsDeclarationMD,omitempty"`
+}
+
+type AmdSec struct {
+	Id       string  `xml:"ID,attr"`
+	RightsMD []MdSec `xml:"rightsMD"`
+}
+
+// Rights is a METSRights RightsDeclarationMD
+type Rights struct {
+	Category          string   `xml:"RIGHTSCATEGORY,attr"`
+	OtherCategoryType string   `xml:"OTHERCATEGORYTYPE,attr,omitempty"`
+	Declaration       []string `xml:"rts:RightsDeclaration"`
+}
+
+type FileSec struct {
+	FileGrp []FileGrp `xml:"fileGrp"`
+}
+
+type FileGrp struct {
+	Use  string `xml:"USE,attr"`
+	File []File `xml:"file"`
+}
+
+type File struct {
+	Id           string `xml:"ID,attr"`
+	MimeType     string `xml:"MIMETYPE,attr,omitempty"`
+	Size         int    `xml:"SIZE,attr,omitempty"`
+	Created      string `xml:"CREATED,attr,omitempty"`
+	Checksum     string `xml:"CHECKSUM,attr,omitempty"`
+	ChecksumType string `xml:"CHECKSUMTYPE,attr,omitempty"`
+	GroupId      string `xml:"GROUPID,attr,omitempty"`
+	FLocat       FLocat `xml:"FLocat"`
+}
+
+type FLocat struct {
+	LocType string `xml:"LOCTYPE,attr"`
+	Href    string `xml:"xlink:href,attr"`
+}
+
+type StructMap struct {
+	Type string `xml:"TYPE,attr"`
+	Div  Div    `xml:"div"`
+}
+
+type Div struct {
+	Type       string `xml:"TYPE,attr,omitempty"`
+	Label      string `xml:"LABEL,attr,omitempty"`
+	Order      int    `xml:"ORDER,attr,omitempty"`
+	OrderLabel string `xml:"ORDERLABEL,attr,omitempty"`
+	DmdId      string `xml:"DMDID,attr,omitempty"`
+	AdmId      string `xml:"ADMID,attr,omitempty"`
+	Fptr       []Fptr `xml:"fptr"`
+	Div        []Div  `xml:"div"`
+}
+
+type Fptr struct {
+	FileId string `xml:"FILEID,attr"`
+}
+
+// Marshal encodes a METS document
+func Marshal(m *Mets) ([]byte, error) {
+	root := *m
+	root.XmlnsXlink = xlinkNamespace
+	root.XmlnsRights = RightsNamespace
+	root.XmlnsXsi = xsiNamespace
+	root.SchemaLocation = SchemaLocation
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(root)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package mets
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNodes = `[
+	{
+		"nid": [{"value": 1}],
+		"title": [{"value": "A book"}],
+		"field_model": [{"target_id": 2}],
+		"field_rights": [{"value": "http://rightsstatements.org/vocab/InC/1.0/"}]
+	},
+	{"nid": [{"value": 3}], "title": [{"value": "Page 2"}], "field_weight": [{"value": 2}], "field_model": [{"target_id": 3}], "field_part_detail": [{"type": "page", "number": "ii"}], "field_member_of": [{"target_id": 1}]},
+	{"nid": [{"value": 2}], "title": [{"value": "Page 1"}], "field_weight": [{"value": 1}], "field_model": [{"target_id": 3}], "field_member_of": [{"target_id": 1}]}
+]`
+
+func media(mid, use int, mime, url string) islandora.Media {
+	return islandora.Media{
+		Mid:      model.IntField{{Value: mid}},
+		MediaUse: model.EntityReferenceField{{TargetId: use}},
+		MimeType: model.GenericField{{Value: mime}},
+		FileSize: model.IntField{{Value: 5}},
+		File:     model.FileField{{Url: url}},
+	}
+}
+
+func testMets(t *testing.T, opts Options) *Mets {
+	nodes, err := islandora.ParseNodes([]byte(testNodes))
+	require.NoError(t, err)
+	opts.Source = islandora.NewMemorySource(nodes, map[int][]islandora.Media{
+		1: {media(20, 11, "application/pdf", "https://example.com/book.pdf")},
+		2: {media(21, 10, "image/jp2", "https://example.com/1.jp2"), media(22, 12, "text/plain", "https://example.com/1.txt")},
+		3: {media(23, 10, "image/jp2", "https://example.com/2.jp2")},
+	}, islandora.MapTerms{
+		2:  islandora.NewTerm("islandora_models", "Paged Content"),
+		3:  islandora.NewTerm("islandora_models", "Page"),
+		10: islandora.NewTerm("islandora_media_use", "Service File"),
+		11: islandora.NewTerm("islandora_media_use", "Original File"),
+		12: islandora.NewTerm("islandora_media_use", "Extracted Text"),
+	})
+	opts.BaseUrl = "https://example.com/"
+	opts.Created = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	m, err := NewBuilder(opts).Build(nodes[0])
+	require.NoError(t, err)
+
+	return m
+}
+
+func TestBuild(t *testing.T) {
+	m := testMets(t, Options{
+		Agent:        "Lehigh University Libraries",
+		ChecksumType: "md5",
+		Checksum: func(url string, w io.Writer) error {
+			_, err := io.WriteString(w, "hello")
+			return err
+		},
+	})
+
+	assert.Equal(t, "https://example.com/node/1", m.ObjId)
+	assert.Equal(t, "Paged Content", m.Type)
+	assert.Equal(t, Header{CreateDate: "2024-05-01T12:00:00Z", RecordStatus: "COMPLETE", Agent: []Agent{{Role: "CREATOR", Type: "ORGANIZATION", Name: "Lehigh University Libraries"}}}, m.Header)
+
+	root := m.StructMap.Div
+	assert.Equal(t, "volume", root.Type)
+	assert.Equal(t, "AMD_1", root.AdmId)
+	assert.Equal(t, []Fptr{{FileId: "FILE_20"}}, root.Fptr)
+	require.Len(t, root.Div, 2)
+	assert.Equal(t, Div{Type: "page", Label: "Page 1", Order: 1, DmdId: "DMD_2", Fptr: []Fptr{{FileId: "FILE_21"}, {FileId: "FILE_22"}}}, root.Div[0])
+	assert.Equal(t, Div{Type: "page", Label: "Page 2", Order: 2, OrderLabel: "ii", DmdId: "DMD_3", Fptr: []Fptr{{FileId: "FILE_23"}}}, root.Div[1])
+
+	require.Len(t, m.DmdSec, 3)
+	assert.Equal(t, "MODS", m.DmdSec[0].MdWrap.MdType)
+	assert.Equal(t, "A book", m.DmdSec[0].MdWrap.XmlData.Mods.TitleInfo[0].Title)
+	assert.Equal(t, &Rights{Category: "COPYRIGHTED", Declaration: []string{"http://rightsstatements.org/vocab/InC/1.0/"}}, m.AmdSec[0].RightsMD[0].MdWrap.XmlData.Rights)
+
+	require.Len(t, m.FileSec.FileGrp, 3)
+	assert.Equal(t, "Original File", m.FileSec.FileGrp[0].Use)
+	assert.Equal(t, FileGrp{Use: "Service File", File: []File{
+		{Id: "FILE_21", MimeType: "image/jp2", Size: 5, Checksum: "5d41402abc4b2a76b9719d911017c592", ChecksumType: "MD5", GroupId: "GRP_2", FLocat: FLocat{LocType: "URL", Href: "https://example.com/1.jp2"}},
+		{Id: "FILE_23", MimeType: "image/jp2", Size: 5, Checksum: "5d41402abc4b2a76b9719d911017c592", ChecksumType: "MD5", GroupId: "GRP_3", FLocat: FLocat{LocType: "URL", Href: "https://example.com/2.jp2"}},
+	}}, m.FileSec.FileGrp[1])
+}
+
+func TestBuildOptions(t *testing.T) {
+	m := testMets(t, Options{Dmd: "dc", FileUses: []string{"Extracted Text", "Service File"}})
+	assert.Equal(t, []string{"A book"}, m.DmdSec[0].MdWrap.XmlData.Dc.Title)
+	require.Len(t, m.FileSec.FileGrp, 2)
+	assert.Equal(t, "Extracted Text", m.FileSec.FileGrp[0].Use)
+	assert.Empty(t, m.FileSec.FileGrp[1].File[0].Checksum)
+	assert.Empty(t, m.StructMap.Div.Fptr)
+
+	_, err := NewBuilder(Options{ChecksumType: "crc32"}).Build(&api.IslandoraObject{})
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(testMets(t, Options{}))
+	require.NoError(t, err)
+	xml := string(data)
+	assert.Contains(t, xml, `<mets xmlns="http://www.loc.gov/METS/" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:rts="http://cosimo.stanford.edu/sdr/metsrights/"`)
+	assert.Contains(t, xml, `<mods xmlns="http://www.loc.gov/mods/v3" version="3.8">`)
+	assert.Contains(t, xml, `<rts:RightsDeclarationMD RIGHTSCATEGORY="COPYRIGHTED">`)
+	assert.Contains(t, xml, `<FLocat LOCTYPE="URL" xlink:href="https://example.com/book.pdf"></FLocat>`)
+	assert.Contains(t, xml, `<structMap TYPE="physical">`)
+}
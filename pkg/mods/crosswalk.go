@@ -0,0 +1,477 @@
+package mods
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+)
+
+type Options struct {
+	Mapping *Mapping
+	// Terms resolves taxonomy term references, term IDs are written when nil
+	Terms islandora.TermLookup
+	// BaseUrl is used to link to the node
+	BaseUrl string
+}
+
+type crosswalk struct {
+	node *api.IslandoraObject
+	opts Options
+	err  error
+}
+
+// FromNode crosswalks a node into a MODS record
+func FromNode(node *api.IslandoraObject, opts Options) (*Mods, error) {
+	if opts.Mapping == nil {
+		opts.Mapping = DefaultMapping()
+	}
+
+	c := &crosswalk{node: node, opts: opts}
+	m := opts.Mapping
+	record := &Mods{}
+
+	record.TitleInfo = c.titles()
+	for _, e := range m.Names {
+		for _, v := range c.values(e) {
+			record.Name = append(record.Name, c.name(v))
+		}
+	}
+	for _, e := range m.TypeOfResource {
+		record.TypeOfResource = append(record.TypeOfResource, c.texts(e)...)
+	}
+	for _, e := range m.Genres {
+		record.Genre = append(record.Genre, c.texts(e)...)
+	}
+	if origin := c.originInfo(); origin != nil {
+		record.OriginInfo = append(record.OriginInfo, *origin)
+	}
+	for _, e := range m.Languages {
+		for _, v := range c.values(e) {
+			language := Language{LanguageTerm: []Text{{Type: "text", Value: v.Value}}}
+			if v.Term != nil {
+				for _, id := range v.Term.Identifier {
+					language.LanguageTerm = append(language.LanguageTerm, Text{Type: "code", Authority: id.Attr0, Value: id.Value})
+				}
+			}
+			record.Language = append(record.Language, language)
+		}
+	}
+	if physical := c.physicalDescription(); physical != nil {
+		record.PhysicalDescription = append(record.PhysicalDescription, *physical)
+	}
+	for _, e := range m.Abstracts {
+		record.Abstract = append(record.Abstract, c.texts(e)...)
+	}
+	for _, e := range m.TableOfContents {
+		record.TableOfContents = append(record.TableOfContents, c.texts(e)...)
+	}
+	for _, e := range m.Notes {
+		record.Note = append(record.Note, c.texts(e)...)
+	}
+	for _, e := range m.Subjects {
+		record.Subject = append(record.Subject, c.subjects(e)...)
+	}
+	for _, e := range m.Classifications {
+		record.Classification = append(record.Classification, c.texts(e)...)
+	}
+	for _, e := range m.RelatedItems {
+		record.RelatedItem = append(record.RelatedItem, c.relatedItems(e)...)
+	}
+	for _, e := range m.Parts {
+		if part := c.part(e); part != nil {
+			record.Part = append(record.Part, *part)
+		}
+	}
+	for _, e := range m.Identifiers {
+		for _, v := range c.values(e) {
+			t := e.Type
+			if t == "" {
+				t = v.Type
+				if mapped, ok := m.IdentifierTypes[v.Type]; ok {
+					t = mapped
+				}
+			}
+			record.Identifier = append(record.Identifier, Text{Type: t, Value: v.Value})
+		}
+	}
+	for _, e := range m.PhysicalLocations {
+		for _, v := range c.texts(e) {
+			record.Location = append(record.Location, Location{PhysicalLocation: []Text{v}})
+		}
+	}
+	if url := c.url(); url != "" {
+		record.Identifier = append(record.Identifier, Text{Type: "uri", Value: url})
+		record.Location = append(record.Location, Location{
+			Url: []Url{{Usage: "primary display", Access: "object in context", Value: url}},
+		})
+	}
+	for _, e := range m.AccessConditions {
+		record.AccessCondition = append(record.AccessCondition, c.texts(e)...)
+	}
+	record.RecordInfo = c.recordInfo()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return record, nil
+}
+
+// values returns a field's values, recording the first error
+func (c *crosswalk) values(e Element) []islandora.Value {
+	if c.err != nil {
+		return nil
+	}
+
+	values, err := islandora.FieldValues(c.node, e.Field, c.opts.Terms)
+	if err != nil {
+		c.err = err
+		return nil
+	}
+
+	return values
+}
+
+// texts returns a field's values as elements using the mapping's type and authority
+// typed text fields use their attr0 as the type
+func (c *crosswalk) texts(e Element) []Text {
+	texts := []Text{}
+	for _, v := range c.values(e) {
+		t := e.Type
+		if t == "" {
+			t = v.Type
+		}
+		texts = append(texts, Text{Type: t, Authority: e.Authority, Value: v.Value})
+	}
+
+	return texts
+}
+
+func (c *crosswalk) strings(elements []Element) []string {
+	s := []string{}
+	for _, e := range elements {
+		for _, v := range c.values(e) {
+			s = append(s, v.Value)
+		}
+	}
+
+	return s
+}
+
+func (c *crosswalk) titles() []TitleInfo {
+	var main *TitleInfo
+	titles := []TitleInfo{}
+	for _, e := range c.opts.Mapping.Titles {
+		for _, v := range c.values(e) {
+			if e.Type != "" {
+				titles = append(titles, TitleInfo{Type: e.Type, Title: v.Value})
+				continue
+			}
+			if main == nil {
+				main = &TitleInfo{Title: v.Value}
+			}
+		}
+	}
+	if main == nil {
+		return titles
+	}
+
+	parts := c.strings(c.opts.Mapping.TitlePartNames)
+	if len(parts) > 0 {
+		main.PartName = parts[0]
+	}
+
+	return append([]TitleInfo{*main}, titles...)
+}
+
+func (c *crosswalk) name(v islandora.Value) Name {
+	name := Name{
+		Type:     c.opts.Mapping.NameTypes[v.Vid()],
+		NamePart: []Text{{Value: v.Value}},
+	}
+	if v.Type == "" {
+		return name
+	}
+
+	scheme, code, _ := strings.Cut(v.Type, ":")
+	if scheme != "relators" {
+		name.Role = []Role{{RoleTerm: []Text{{Type: "text", Value: v.Type}}}}
+		return name
+	}
+
+	role := Role{}
+	if text, ok := c.opts.Mapping.Relators[code]; ok {
+		role.RoleTerm = append(role.RoleTerm, Text{Type: "text", Authority: "marcrelator", Value: text})
+	}
+	role.RoleTerm = append(role.RoleTerm, Text{Type: "code", Authority: "marcrelator", Value: code})
+	name.Role = []Role{role}
+
+	return name
+}
+
+func (c *crosswalk) originInfo() *OriginInfo {
+	m := c.opts.Mapping
+	origin := OriginInfo{}
+	populated := false
+
+	keyDate := true
+	for _, e := range m.Dates {
+		for _, v := range c.values(e) {
+			dates := Dates(v.Value)
+			for i := range dates {
+				if e.Element == "dateOther" {
+					dates[i].Type = v.Type
+					if e.Type != "" {
+						dates[i].Type = e.Type
+					}
+				}
+			}
+			if keyDate && len(dates) > 0 {
+				dates[0].KeyDate = "yes"
+				keyDate = false
+			}
+			switch e.Element {
+			case "dateIssued":
+				origin.DateIssued = append(origin.DateIssued, dates...)
+			case "dateCreated":
+				origin.DateCreated = append(origin.DateCreated, dates...)
+			case "dateCaptured":
+				origin.DateCaptured = append(origin.DateCaptured, dates...)
+			case "dateValid":
+				origin.DateValid = append(origin.DateValid, dates...)
+			case "dateModified":
+				origin.DateModified = append(origin.DateModified, dates...)
+			case "copyrightDate":
+				origin.CopyrightDate = append(origin.CopyrightDate, dates...)
+			case "dateOther":
+				origin.DateOther = append(origin.DateOther, dates...)
+			}
+			populated = true
+		}
+	}
+
+	for _, e := range m.Places {
+		for _, v := range c.values(e) {
+			term := Text{Type: e.Type, Authority: e.Authority, Value: v.Value}
+			// codes come from the term's identifier, otherwise the name is written as text
+			if e.Type == "code" {
+				term = Text{Type: "text", Value: v.Value}
+				if v.Term != nil && len(v.Term.Identifier) > 0 {
+					term = Text{Type: "code", Authority: e.Authority, Value: v.Term.Identifier[0].Value}
+				}
+			}
+			origin.Place = append(origin.Place, Place{PlaceTerm: []Text{term}})
+			populated = true
+		}
+	}
+	for _, list := range []struct {
+		elements []Element
+		texts    *[]Text
+	}{
+		{m.Publishers, &origin.Publisher},
+		{m.Editions, &origin.Edition},
+		{m.Issuance, &origin.Issuance},
+		{m.Frequencies, &origin.Frequency},
+	} {
+		for _, e := range list.elements {
+			texts := c.texts(e)
+			*list.texts = append(*list.texts, texts...)
+			populated = populated || len(texts) > 0
+		}
+	}
+
+	if !populated {
+		return nil
+	}
+
+	return &origin
+}
+
+// Dates converts an EDTF value into MODS dates.
+// Intervals become start and end points, and qualified dates set the qualifier.
+// Values that are not valid EDTF are written without an encoding.
+func Dates(value string) []Date {
+	parsed, err := edtf.Parse(value)
+	if err != nil {
+		return []Date{{Value: value}}
+	}
+
+	if parsed.Kind != edtf.KindInterval {
+		return []Date{{Encoding: "edtf", Qualifier: qualifier(parsed.Start), Value: value}}
+	}
+
+	start, end, _ := strings.Cut(value, "/")
+	dates := []Date{}
+	if !parsed.Start.Open && !parsed.Start.Unknown {
+		dates = append(dates, Date{Encoding: "edtf", Point: "start", Qualifier: qualifier(parsed.Start), Value: start})
+	}
+	if !parsed.End.Open && !parsed.End.Unknown {
+		dates = append(dates, Date{Encoding: "edtf", Point: "end", Qualifier: qualifier(parsed.End), Value: end})
+	}
+
+	return dates
+}
+
+func qualifier(d edtf.Date) string {
+	switch {
+	case d.Approximate:
+		return "approximate"
+	case d.Uncertain:
+		return "questionable"
+	}
+
+	return ""
+}
+
+func (c *crosswalk) physicalDescription() *PhysicalDescription {
+	m := c.opts.Mapping
+	physical := PhysicalDescription{}
+	for _, e := range m.Forms {
+		physical.Form = append(physical.Form, c.texts(e)...)
+	}
+	physical.InternetMediaType = c.strings(m.MediaTypes)
+	for _, e := range m.Extents {
+		for _, v := range c.values(e) {
+			physical.Extent = append(physical.Extent, Extent{Unit: v.Type, Value: v.Value})
+		}
+	}
+	physical.DigitalOrigin = c.strings(m.DigitalOrigins)
+	for _, e := range m.PhysicalNotes {
+		physical.Note = append(physical.Note, c.texts(e)...)
+	}
+
+	if len(physical.Form)+len(physical.InternetMediaType)+len(physical.Extent)+len(physical.DigitalOrigin)+len(physical.Note) == 0 {
+		return nil
+	}
+
+	return &physical
+}
+
+func (c *crosswalk) subjects(e Element) []Subject {
+	subjects := []Subject{}
+	for _, v := range c.values(e) {
+		subject := Subject{Authority: e.Authority}
+		text := []Text{{Value: v.Value}}
+		switch e.Element {
+		case "topic":
+			subject.Topic = text
+		case "geographic":
+			subject.Geographic = text
+		case "temporal":
+			subject.Temporal = text
+		case "genre":
+			subject.Genre = text
+		case "name":
+			subject.Name = []Name{c.name(islandora.Value{Value: v.Value, Term: v.Term})}
+		case "hierarchicalGeographic":
+			var geo model.HierarchicalGeographic
+			err := json.Unmarshal([]byte(v.Value), &geo)
+			if err != nil {
+				c.err = fmt.Errorf("%s: %v", e.Field, err)
+				return nil
+			}
+			subject.HierarchicalGeographic = &HierarchicalGeographic{
+				Continent: nonEmpty(geo.Continent),
+				Country:   nonEmpty(geo.Country),
+				State:     nonEmpty(geo.State),
+				Territory: nonEmpty(geo.Territory),
+				County:    nonEmpty(geo.County),
+				City:      nonEmpty(geo.City),
+			}
+		}
+		subjects = append(subjects, subject)
+	}
+
+	return subjects
+}
+
+func (c *crosswalk) relatedItems(e Element) []RelatedItem {
+	items := []RelatedItem{}
+	for _, v := range c.values(e) {
+		var related model.RelatedItem
+		err := json.Unmarshal([]byte(v.Value), &related)
+		if err != nil {
+			c.err = fmt.Errorf("%s: %v", e.Field, err)
+			return nil
+		}
+
+		item := RelatedItem{Type: e.Type}
+		if related.Title != "" {
+			item.TitleInfo = []TitleInfo{{Title: related.Title}}
+		}
+		if related.Identifier != "" {
+			item.Identifier = []Text{{Value: related.Identifier}}
+		}
+		if related.Number != "" {
+			item.Part = []Part{{Detail: []Detail{{Number: []string{related.Number}}}}}
+		}
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (c *crosswalk) part(e Element) *Part {
+	part := Part{}
+	for _, v := range c.values(e) {
+		var detail model.PartDetail
+		err := json.Unmarshal([]byte(v.Value), &detail)
+		if err != nil {
+			c.err = fmt.Errorf("%s: %v", e.Field, err)
+			return nil
+		}
+		part.Detail = append(part.Detail, Detail{
+			Type:    detail.Type,
+			Number:  nonEmpty(detail.Number),
+			Caption: nonEmpty(detail.Caption),
+			Title:   nonEmpty(detail.Title),
+		})
+	}
+	if len(part.Detail) == 0 {
+		return nil
+	}
+
+	return &part
+}
+
+func (c *crosswalk) url() string {
+	if c.opts.BaseUrl == "" || c.node.Nid == nil || len(*c.node.Nid) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/node/%d", strings.TrimSuffix(c.opts.BaseUrl, "/"), (*c.node.Nid)[0].Value)
+}
+
+func (c *crosswalk) recordInfo() *RecordInfo {
+	info := RecordInfo{
+		RecordOrigin: c.strings(c.opts.Mapping.RecordOrigins),
+	}
+	if c.node.Created != nil && len(*c.node.Created) > 0 {
+		info.RecordCreationDate = []Date{{Encoding: "w3cdtf", Value: (*c.node.Created)[0].Value}}
+	}
+	if c.node.Changed != nil && len(*c.node.Changed) > 0 {
+		info.RecordChangeDate = []Date{{Encoding: "w3cdtf", Value: (*c.node.Changed)[0].Value}}
+	}
+	if c.node.Uuid != nil && len(*c.node.Uuid) > 0 {
+		info.RecordIdentifier = []Text{{Value: (*c.node.Uuid)[0].Value}}
+	}
+
+	if len(info.RecordOrigin)+len(info.RecordCreationDate)+len(info.RecordChangeDate)+len(info.RecordIdentifier) == 0 {
+		return nil
+	}
+
+	return &info
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return []string{s}
+}
@@ -0,0 +1,148 @@
+package mods
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed mapping.yaml
+var defaultMapping []byte
+
+// Mapping describes which node fields populate each MODS element
+type Mapping struct {
+	Titles         []Element `yaml:"titles"`
+	TitlePartNames []Element `yaml:"title_part_names"`
+	Names          []Element `yaml:"names"`
+	// NameTypes maps an agent's vocabulary to a name type e.g. person: personal
+	NameTypes map[string]string `yaml:"name_types"`
+	// Relators maps MARC relator codes to role text e.g. cre: Creator
+	Relators          map[string]string `yaml:"relators"`
+	TypeOfResource    []Element         `yaml:"type_of_resource"`
+	Genres            []Element         `yaml:"genres"`
+	Dates             []Element         `yaml:"dates"`
+	Places            []Element         `yaml:"places"`
+	Publishers        []Element         `yaml:"publishers"`
+	Editions          []Element         `yaml:"editions"`
+	Issuance          []Element         `yaml:"issuance"`
+	Frequencies       []Element         `yaml:"frequencies"`
+	Languages         []Element         `yaml:"languages"`
+	Forms             []Element         `yaml:"forms"`
+	MediaTypes        []Element         `yaml:"media_types"`
+	Extents           []Element         `yaml:"extents"`
+	DigitalOrigins    []Element         `yaml:"digital_origins"`
+	PhysicalNotes     []Element         `yaml:"physical_notes"`
+	Abstracts         []Element         `yaml:"abstracts"`
+	TableOfContents   []Element         `yaml:"table_of_contents"`
+	Notes             []Element         `yaml:"notes"`
+	Subjects          []Element         `yaml:"subjects"`
+	Classifications   []Element         `yaml:"classifications"`
+	RelatedItems      []Element         `yaml:"related_items"`
+	Parts             []Element         `yaml:"parts"`
+	Identifiers       []Element         `yaml:"identifiers"`
+	IdentifierTypes   map[string]string `yaml:"identifier_types"`
+	AccessConditions  []Element         `yaml:"access_conditions"`
+	PhysicalLocations []Element         `yaml:"physical_locations"`
+	RecordOrigins     []Element         `yaml:"record_origins"`
+}
+
+// Element maps a node field to a MODS element
+type Element struct {
+	Field string `yaml:"field"`
+	// Element is the child element for dates (e.g. dateIssued) and subjects (e.g. topic)
+	Element   string `yaml:"element,omitempty"`
+	Type      string `yaml:"type,omitempty"`
+	Authority string `yaml:"authority,omitempty"`
+}
+
+var (
+	dateElements    = []string{"dateIssued", "dateCreated", "dateCaptured", "dateValid", "dateModified", "copyrightDate", "dateOther"}
+	subjectElements = []string{"topic", "geographic", "temporal", "name", "genre", "hierarchicalGeographic"}
+)
+
+// DefaultMapping returns the built-in mapping for Islandora Starter Site fields
+func DefaultMapping() *Mapping {
+	m, err := ParseMapping(defaultMapping)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// LoadMapping reads a mapping from a YAML file
+// or returns the default mapping if path is empty
+func LoadMapping(path string) (*Mapping, error) {
+	if path == "" {
+		return DefaultMapping(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMapping(data)
+}
+
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	err := yaml.UnmarshalStrict(data, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string][]Element{
+		"titles":             m.Titles,
+		"title_part_names":   m.TitlePartNames,
+		"names":              m.Names,
+		"type_of_resource":   m.TypeOfResource,
+		"genres":             m.Genres,
+		"dates":              m.Dates,
+		"places":             m.Places,
+		"publishers":         m.Publishers,
+		"editions":           m.Editions,
+		"issuance":           m.Issuance,
+		"frequencies":        m.Frequencies,
+		"languages":          m.Languages,
+		"forms":              m.Forms,
+		"media_types":        m.MediaTypes,
+		"extents":            m.Extents,
+		"digital_origins":    m.DigitalOrigins,
+		"physical_notes":     m.PhysicalNotes,
+		"abstracts":          m.Abstracts,
+		"table_of_contents":  m.TableOfContents,
+		"notes":              m.Notes,
+		"subjects":           m.Subjects,
+		"classifications":    m.Classifications,
+		"related_items":      m.RelatedItems,
+		"parts":              m.Parts,
+		"identifiers":        m.Identifiers,
+		"access_conditions":  m.AccessConditions,
+		"physical_locations": m.PhysicalLocations,
+		"record_origins":     m.RecordOrigins,
+	}
+	for section, elements := range sections {
+		for _, e := range elements {
+			if !islandora.HasField(e.Field) {
+				return nil, fmt.Errorf("%s: unknown field %q", section, e.Field)
+			}
+		}
+	}
+	for _, e := range m.Dates {
+		if !slices.Contains(dateElements, e.Element) {
+			return nil, fmt.Errorf("dates: %s has unknown element %q", e.Field, e.Element)
+		}
+	}
+	for _, e := range m.Subjects {
+		if !slices.Contains(subjectElements, e.Element) {
+			return nil, fmt.Errorf("subjects: %s has unknown element %q", e.Field, e.Element)
+		}
+	}
+
+	return &m, nil
+}
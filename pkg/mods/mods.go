@@ -0,0 +1,211 @@
+// Package mods crosswalks Islandora nodes into MODS 3.x XML
+package mods
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+const (
+	Namespace      = "http://www.loc.gov/mods/v3"
+	SchemaLocation = "http://www.loc.gov/mods/v3 http://www.loc.gov/standards/mods/v3/mods-3-8.xsd"
+	Version        = "3.8"
+	xsiNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+type Collection struct {
+	XMLName        xml.Name `xml:"http://www.loc.gov/mods/v3 modsCollection"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Mods           []*Mods  `xml:"mods"`
+}
+
+type Mods struct {
+	XMLName        xml.Name `xml:"http://www.loc.gov/mods/v3 mods"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr,omitempty"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr,omitempty"`
+	Version        string   `xml:"version,attr,omitempty"`
+
+	TitleInfo           []TitleInfo           `xml:"titleInfo"`
+	Name                []Name                `xml:"name"`
+	TypeOfResource      []Text                `xml:"typeOfResource"`
+	Genre               []Text                `xml:"genre"`
+	OriginInfo          []OriginInfo          `xml:"originInfo"`
+	Language            []Language            `xml:"language"`
+	PhysicalDescription []PhysicalDescription `xml:"physicalDescription"`
+	Abstract            []Text                `xml:"abstract"`
+	TableOfContents     []Text                `xml:"tableOfContents"`
+	Note                []Text                `xml:"note"`
+	Subject             []Subject             `xml:"subject"`
+	Classification      []Text                `xml:"classification"`
+	RelatedItem         []RelatedItem         `xml:"relatedItem"`
+	Identifier          []Text                `xml:"identifier"`
+	Location            []Location            `xml:"location"`
+	AccessCondition     []Text                `xml:"accessCondition"`
+	Part                []Part                `xml:"part"`
+	RecordInfo          *RecordInfo           `xml:"recordInfo"`
+}
+
+// Text is an element with the attributes MODS commonly uses
+type Text struct {
+	Type         string `xml:"type,attr,omitempty"`
+	Authority    string `xml:"authority,attr,omitempty"`
+	DisplayLabel string `xml:"displayLabel,attr,omitempty"`
+	Value        string `xml:",chardata"`
+}
+
+type TitleInfo struct {
+	Type     string `xml:"type,attr,omitempty"`
+	Title    string `xml:"title"`
+	SubTitle string `xml:"subTitle,omitempty"`
+	PartName string `xml:"partName,omitempty"`
+}
+
+type Name struct {
+	Type        string   `xml:"type,attr,omitempty"`
+	Authority   string   `xml:"authority,attr,omitempty"`
+	ValueURI    string   `xml:"valueURI,attr,omitempty"`
+	NamePart    []Text   `xml:"namePart"`
+	Affiliation []string `xml:"affiliation"`
+	Role        []Role   `xml:"role"`
+}
+
+type Role struct {
+	RoleTerm []Text `xml:"roleTerm"`
+}
+
+type OriginInfo struct {
+	EventType     string  `xml:"eventType,attr,omitempty"`
+	Place         []Place `xml:"place"`
+	Publisher     []Text  `xml:"publisher"`
+	DateIssued    []Date  `xml:"dateIssued"`
+	DateCreated   []Date  `xml:"dateCreated"`
+	DateCaptured  []Date  `xml:"dateCaptured"`
+	DateValid     []Date  `xml:"dateValid"`
+	DateModified  []Date  `xml:"dateModified"`
+	CopyrightDate []Date  `xml:"copyrightDate"`
+	DateOther     []Date  `xml:"dateOther"`
+	Edition       []Text  `xml:"edition"`
+	Issuance      []Text  `xml:"issuance"`
+	Frequency     []Text  `xml:"frequency"`
+}
+
+type Place struct {
+	PlaceTerm []Text `xml:"placeTerm"`
+}
+
+type Date struct {
+	Encoding  string `xml:"encoding,attr,omitempty"`
+	Point     string `xml:"point,attr,omitempty"`
+	KeyDate   string `xml:"keyDate,attr,omitempty"`
+	Qualifier string `xml:"qualifier,attr,omitempty"`
+	Type      string `xml:"type,attr,omitempty"`
+	Value     string `xml:",chardata"`
+}
+
+type Language struct {
+	LanguageTerm []Text `xml:"languageTerm"`
+}
+
+type PhysicalDescription struct {
+	Form              []Text   `xml:"form"`
+	InternetMediaType []string `xml:"internetMediaType"`
+	Extent            []Extent `xml:"extent"`
+	DigitalOrigin     []string `xml:"digitalOrigin"`
+	Note              []Text   `xml:"note"`
+}
+
+type Extent struct {
+	Unit  string `xml:"unit,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type Subject struct {
+	Authority              string                  `xml:"authority,attr,omitempty"`
+	ValueURI               string                  `xml:"valueURI,attr,omitempty"`
+	Topic                  []Text                  `xml:"topic"`
+	Geographic             []Text                  `xml:"geographic"`
+	Temporal               []Text                  `xml:"temporal"`
+	Name                   []Name                  `xml:"name"`
+	Genre                  []Text                  `xml:"genre"`
+	HierarchicalGeographic *HierarchicalGeographic `xml:"hierarchicalGeographic"`
+}
+
+type HierarchicalGeographic struct {
+	Continent []string `xml:"continent"`
+	Country   []string `xml:"country"`
+	State     []string `xml:"state"`
+	Territory []string `xml:"territory"`
+	County    []string `xml:"county"`
+	City      []string `xml:"city"`
+}
+
+type RelatedItem struct {
+	Type       string      `xml:"type,attr,omitempty"`
+	TitleInfo  []TitleInfo `xml:"titleInfo"`
+	Identifier []Text      `xml:"identifier"`
+	Location   []Location  `xml:"location"`
+	Part       []Part      `xml:"part"`
+}
+
+type Part struct {
+	Detail []Detail `xml:"detail"`
+}
+
+type Detail struct {
+	Type    string   `xml:"type,attr,omitempty"`
+	Number  []string `xml:"number"`
+	Caption []string `xml:"caption"`
+	Title   []string `xml:"title"`
+}
+
+type Location struct {
+	PhysicalLocation []Text `xml:"physicalLocation"`
+	Url              []Url  `xml:"url"`
+}
+
+type Url struct {
+	Usage  string `xml:"usage,attr,omitempty"`
+	Access string `xml:"access,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type RecordInfo struct {
+	RecordOrigin       []string `xml:"recordOrigin"`
+	RecordCreationDate []Date   `xml:"recordCreationDate"`
+	RecordChangeDate   []Date   `xml:"recordChangeDate"`
+	RecordIdentifier   []Text   `xml:"recordIdentifier"`
+}
+
+// Marshal encodes a standalone MODS record
+func Marshal(m *Mods) ([]byte, error) {
+	root := *m
+	root.XmlnsXsi = xsiNamespace
+	root.SchemaLocation = SchemaLocation
+	root.Version = Version
+
+	return marshal(root)
+}
+
+// MarshalCollection encodes the records in a modsCollection
+func MarshalCollection(records []*Mods) ([]byte, error) {
+	return marshal(Collection{
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: SchemaLocation,
+		Mods:           records,
+	})
+}
+
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+package mods
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNode = `{
+	"nid": [{"value": 42}],
+	"uuid": [{"value": "abc-123"}],
+	"changed": [{"value": "2024-05-01T12:00:00+00:00"}],
+	"title": [{"value": "Short title"}],
+	"field_full_title": [{"value": "The full title"}],
+	"field_alt_title": [{"value": "Another title"}],
+	"field_linked_agent": [
+		{"target_id": 1, "rel_type": "relators:cre"},
+		{"target_id": 2, "rel_type": "relators:pbl"}
+	],
+	"field_genre": [{"target_id": 3, "target_type": "taxonomy_term"}],
+	"field_edtf_date_issued": [{"value": "1950~/1960"}],
+	"field_edtf_date_created": [{"value": "1949?"}],
+	"field_date_other": [{"attr0": "reprinted", "value": "2001"}],
+	"field_identifier": [{"attr0": "doi", "value": "10.1234/foo"}, {"attr0": "call-number", "value": "LD 1"}],
+	"field_part_detail": [{"type": "volume", "number": "12"}, {"type": "issue", "number": "3", "caption": "no."}],
+	"field_related_item": [{"title": "Journal of Things", "identifier": "1234-5678", "number": "12"}],
+	"field_subject_hierarchical_geo": [{"country": "United States", "state": "Pennsylvania", "city": "Bethlehem"}],
+	"field_lcsh_topic": [{"target_id": 4, "target_type": "taxonomy_term"}],
+	"field_extent": [{"attr0": "page", "value": "12 pages"}],
+	"field_rights": [{"value": "http://rightsstatements.org/vocab/InC/1.0/"}]
+}`
+
+func TestFromNode(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+
+	record, err := FromNode(&node, Options{
+		Terms: islandora.MapTerms{
+			1: islandora.NewTerm("person", "Doe, Jane"),
+			2: islandora.NewTerm("corporate_body", "Lehigh University"),
+			3: islandora.NewTerm("genre", "Articles"),
+			4: islandora.NewTerm("subject", "Steel industry"),
+		},
+		BaseUrl: "https://example.com/",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []TitleInfo{
+		{Title: "The full title"},
+		{Type: "alternative", Title: "Another title"},
+	}, record.TitleInfo)
+
+	require.Len(t, record.Name, 2)
+	assert.Equal(t, Name{
+		Type:     "personal",
+		NamePart: []Text{{Value: "Doe, Jane"}},
+		Role: []Role{{RoleTerm: []Text{
+			{Type: "text", Authority: "marcrelator", Value: "Creator"},
+			{Type: "code", Authority: "marcrelator", Value: "cre"},
+		}}},
+	}, record.Name[0])
+	assert.Equal(t, "corporate", record.Name[1].Type)
+	assert.Equal(t, []Text{{Value: "Articles"}}, record.Genre)
+
+	require.Len(t, record.OriginInfo, 1)
+	origin := record.OriginInfo[0]
+	assert.Equal(t, []Date{
+		{Encoding: "edtf", Point: "start", KeyDate: "yes", Qualifier: "approximate", Value: "1950~"},
+		{Encoding: "edtf", Point: "end", Value: "1960"},
+	}, origin.DateIssued)
+	assert.Equal(t, []Date{{Encoding: "edtf", Qualifier: "questionable", Value: "1949?"}}, origin.DateCreated)
+	assert.Equal(t, []Date{{Encoding: "edtf", Type: "reprinted", Value: "2001"}}, origin.DateOther)
+
+	assert.Equal(t, []Text{
+		{Type: "doi", Value: "10.1234/foo"},
+		{Type: "local", Value: "LD 1"},
+		{Type: "uri", Value: "https://example.com/node/42"},
+	}, record.Identifier)
+	assert.Equal(t, []Part{{Detail: []Detail{
+		{Type: "volume", Number: []string{"12"}},
+		{Type: "issue", Number: []string{"3"}, Caption: []string{"no."}},
+	}}}, record.Part)
+	assert.Equal(t, []RelatedItem{{
+		Type:       "host",
+		TitleInfo:  []TitleInfo{{Title: "Journal of Things"}},
+		Identifier: []Text{{Value: "1234-5678"}},
+		Part:       []Part{{Detail: []Detail{{Number: []string{"12"}}}}},
+	}}, record.RelatedItem)
+	assert.Equal(t, []Subject{
+		{Authority: "lcsh", Topic: []Text{{Value: "Steel industry"}}},
+		{HierarchicalGeographic: &HierarchicalGeographic{
+			Country: []string{"United States"},
+			State:   []string{"Pennsylvania"},
+			City:    []string{"Bethlehem"},
+		}},
+	}, record.Subject)
+	assert.Equal(t, []Extent{{Unit: "page", Value: "12 pages"}}, record.PhysicalDescription[0].Extent)
+	assert.Equal(t, []Text{{Type: "use and reproduction", Value: "http://rightsstatements.org/vocab/InC/1.0/"}}, record.AccessCondition)
+
+	data, err := Marshal(record)
+	require.NoError(t, err)
+	xml := string(data)
+	assert.Contains(t, xml, `<mods xmlns="http://www.loc.gov/mods/v3" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.loc.gov/mods/v3 http://www.loc.gov/standards/mods/v3/mods-3-8.xsd" version="3.8">`)
+	assert.Contains(t, xml, `<dateIssued encoding="edtf" point="start" keyDate="yes" qualifier="approximate">1950~</dateIssued>`)
+
+	data, err = MarshalCollection([]*Mods{record, record})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "<mods "))
+	assert.Contains(t, string(data), "<modsCollection ")
+}
+
+func TestFromNodeTermError(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+
+	_, err := FromNode(&node, Options{Terms: islandora.MapTerms{}})
+	assert.ErrorContains(t, err, "unable to load term 1")
+}
+
+func TestParseMapping(t *testing.T) {
+	assert.NotNil(t, DefaultMapping())
+
+	_, err := ParseMapping([]byte("titles:\n  - field: field_bogus\n"))
+	assert.ErrorContains(t, err, `unknown field "field_bogus"`)
+
+	_, err = ParseMapping([]byte("dates:\n  - field: field_edtf_date\n    element: date\n"))
+	assert.ErrorContains(t, err, `unknown element "date"`)
+
+	m, err := ParseMapping([]byte("titles:\n  - field: title\n"))
+	require.NoError(t, err)
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(testNode), &node))
+	record, err := FromNode(&node, Options{Mapping: m})
+	require.NoError(t, err)
+	assert.Equal(t, []TitleInfo{{Title: "Short title"}}, record.TitleInfo)
+	assert.Empty(t, record.Name)
+}
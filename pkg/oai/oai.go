@@ -0,0 +1,122 @@
+// Package oai is an OAI-PMH 2.0 data provider for Islandora nodes
+package oai
+
+import (
+	"encoding/xml"
+)
+
+const (
+	Namespace      = "http://www.openarchives.org/OAI/2.0/"
+	SchemaLocation = "http://www.openarchives.org/OAI/2.0/ http://www.openarchives.org/OAI/2.0/OAI-PMH.xsd"
+	Granularity    = "YYYY-MM-DDThh:mm:ssZ"
+	timeFormat     = "2006-01-02T15:04:05Z"
+	dateFormat     = "2006-01-02"
+)
+
+// error codes defined by the protocol
+const (
+	BadArgument             = "badArgument"
+	BadResumptionToken      = "badResumptionToken"
+	BadVerb                 = "badVerb"
+	CannotDisseminateFormat = "cannotDisseminateFormat"
+	IdDoesNotExist          = "idDoesNotExist"
+	NoRecordsMatch          = "noRecordsMatch"
+	NoSetHierarchy          = "noSetHierarchy"
+)
+
+type Response struct {
+	XMLName        xml.Name `xml:"http://www.openarchives.org/OAI/2.0/ OAI-PMH"`
+	XmlnsXsi       string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	ResponseDate   string   `xml:"responseDate"`
+	Request        Request  `xml:"request"`
+	Errors         []Error  `xml:"error"`
+
+	Identify            *Identify            `xml:"Identify"`
+	ListMetadataFormats *ListMetadataFormats `xml:"ListMetadataFormats"`
+	ListSets            *ListSets            `xml:"ListSets"`
+	ListIdentifiers     *ListIdentifiers     `xml:"ListIdentifiers"`
+	ListRecords         *ListRecords         `xml:"ListRecords"`
+	GetRecord           *GetRecord           `xml:"GetRecord"`
+}
+
+type Request struct {
+	Verb            string `xml:"verb,attr,omitempty"`
+	Identifier      string `xml:"identifier,attr,omitempty"`
+	MetadataPrefix  string `xml:"metadataPrefix,attr,omitempty"`
+	From            string `xml:"from,attr,omitempty"`
+	Until           string `xml:"until,attr,omitempty"`
+	Set             string `xml:"set,attr,omitempty"`
+	ResumptionToken string `xml:"resumptionToken,attr,omitempty"`
+	BaseURL         string `xml:",chardata"`
+}
+
+type Error struct {
+	Code    string `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+type Identify struct {
+	RepositoryName    string   `xml:"repositoryName"`
+	BaseURL           string   `xml:"baseURL"`
+	ProtocolVersion   string   `xml:"protocolVersion"`
+	AdminEmail        []string `xml:"adminEmail"`
+	EarliestDatestamp string   `xml:"earliestDatestamp"`
+	DeletedRecord     string   `xml:"deletedRecord"`
+	Granularity       string   `xml:"granularity"`
+}
+
+type MetadataFormat struct {
+	MetadataPrefix    string `xml:"metadataPrefix"`
+	Schema            string `xml:"schema"`
+	MetadataNamespace string `xml:"metadataNamespace"`
+}
+
+type ListMetadataFormats struct {
+	MetadataFormat []MetadataFormat `xml:"metadataFormat"`
+}
+
+type Set struct {
+	SetSpec string `xml:"setSpec"`
+	SetName string `xml:"setName"`
+}
+
+type ListSets struct {
+	Set []Set `xml:"set"`
+}
+
+type Header struct {
+	Status     string   `xml:"status,attr,omitempty"`
+	Identifier string   `xml:"identifier"`
+	Datestamp  string   `xml:"datestamp"`
+	SetSpec    []string `xml:"setSpec"`
+}
+
+type Metadata struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+type Record struct {
+	Header   Header    `xml:"header"`
+	Metadata *Metadata `xml:"metadata"`
+}
+
+type ResumptionToken struct {
+	CompleteListSize int    `xml:"completeListSize,attr"`
+	Cursor           int    `xml:"cursor,attr"`
+	Value            string `xml:",chardata"`
+}
+
+type ListIdentifiers struct {
+	Header          []Header         `xml:"header"`
+	ResumptionToken *ResumptionToken `xml:"resumptionToken"`
+}
+
+type ListRecords struct {
+	Record          []Record         `xml:"record"`
+	ResumptionToken *ResumptionToken `xml:"resumptionToken"`
+}
+
+type GetRecord struct {
+	Record Record `xml:"record"`
+}
@@ -0,0 +1,518 @@
+package oai
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/dc"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+type Options struct {
+	RepositoryName string
+	// BaseURL is the public URL of the OAI-PMH endpoint
+	BaseURL     string
+	AdminEmails []string
+	// RepositoryIdentifier namespaces record identifiers e.g. oai:{RepositoryIdentifier}:node/1
+	RepositoryIdentifier string
+	// PageSize is how many headers or records are listed before a resumption token
+	PageSize int
+	// Mods configures the crosswalk both metadata formats are generated from
+	Mods mods.Options
+	// SetModels are the models (by term name) of nodes listed as sets.
+	// Every node with members is a set when Mods.Terms is nil.
+	SetModels []string
+}
+
+// Provider serves OAI-PMH requests from a snapshot of nodes
+type Provider struct {
+	opts  Options
+	index atomic.Pointer[index]
+}
+
+type record struct {
+	nid       int
+	node      *api.IslandoraObject
+	datestamp time.Time
+	sets      []string
+}
+
+type index struct {
+	records  []*record
+	byNid    map[int]*record
+	sets     []Set
+	earliest time.Time
+}
+
+type format struct {
+	MetadataFormat
+	encode func(*mods.Mods) any
+}
+
+var formats = []format{
+	{
+		MetadataFormat: MetadataFormat{
+			MetadataPrefix:    "oai_dc",
+			Schema:            "http://www.openarchives.org/OAI/2.0/oai_dc.xsd",
+			MetadataNamespace: dc.Namespace,
+		},
+		encode: func(m *mods.Mods) any {
+			return dc.FromMods(m)
+		},
+	},
+	{
+		MetadataFormat: MetadataFormat{
+			MetadataPrefix:    "mods",
+			Schema:            "http://www.loc.gov/standards/mods/v3/mods-3-8.xsd",
+			MetadataNamespace: mods.Namespace,
+		},
+		encode: func(m *mods.Mods) any {
+			root := *m
+			root.XmlnsXsi = xsiNamespace
+			root.SchemaLocation = mods.SchemaLocation
+			root.Version = mods.Version
+			return root
+		},
+	},
+}
+
+func NewProvider(opts Options) *Provider {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+	if opts.RepositoryName == "" {
+		opts.RepositoryName = "Islandora"
+	}
+	if opts.SetModels == nil {
+		opts.SetModels = []string{"Collection"}
+	}
+
+	p := &Provider{opts: opts}
+	p.index.Store(&index{byNid: map[int]*record{}})
+
+	return p
+}
+
+// SetNodes replaces the nodes being served.
+// Unpublished nodes are left out.
+func (p *Provider) SetNodes(nodes []*api.IslandoraObject) error {
+	idx := &index{byNid: map[int]*record{}}
+	parents := map[int][]int{}
+	for _, node := range nodes {
+		if node.Nid == nil || len(*node.Nid) == 0 {
+			return fmt.Errorf("node has no nid")
+		}
+		if node.Status != nil && len(*node.Status) > 0 && !(*node.Status)[0].Value {
+			continue
+		}
+		nid := (*node.Nid)[0].Value
+		datestamp, err := datestamp(node)
+		if err != nil {
+			return fmt.Errorf("node %d: %v", nid, err)
+		}
+		r := &record{nid: nid, node: node, datestamp: datestamp}
+		idx.records = append(idx.records, r)
+		idx.byNid[nid] = r
+		if node.FieldMemberOf != nil {
+			for _, parent := range *node.FieldMemberOf {
+				parents[nid] = append(parents[nid], parent.TargetId)
+			}
+		}
+	}
+
+	setNids := []int{}
+	for _, r := range idx.records {
+		for _, parent := range parents[r.nid] {
+			if _, ok := idx.byNid[parent]; ok && !slices.Contains(setNids, parent) {
+				setNids = append(setNids, parent)
+			}
+		}
+	}
+	slices.Sort(setNids)
+	isSet := map[int]bool{}
+	for _, nid := range setNids {
+		ok, err := p.isSet(idx.byNid[nid].node)
+		if err != nil {
+			return fmt.Errorf("node %d: %v", nid, err)
+		}
+		if !ok {
+			continue
+		}
+		isSet[nid] = true
+		idx.sets = append(idx.sets, Set{SetSpec: setSpec(nid), SetName: title(idx.byNid[nid].node)})
+	}
+
+	// items belong to the sets of all their ancestors
+	for _, r := range idx.records {
+		seen := map[int]bool{r.nid: true}
+		queue := slices.Clone(parents[r.nid])
+		for len(queue) > 0 {
+			parent := queue[0]
+			queue = queue[1:]
+			if seen[parent] {
+				continue
+			}
+			seen[parent] = true
+			if isSet[parent] {
+				r.sets = append(r.sets, setSpec(parent))
+			}
+			queue = append(queue, parents[parent]...)
+		}
+		sort.Strings(r.sets)
+	}
+
+	sort.SliceStable(idx.records, func(i, j int) bool {
+		a, b := idx.records[i], idx.records[j]
+		if !a.datestamp.Equal(b.datestamp) {
+			return a.datestamp.Before(b.datestamp)
+		}
+		return a.nid < b.nid
+	})
+	if len(idx.records) > 0 {
+		idx.earliest = idx.records[0].datestamp
+	}
+
+	p.index.Store(idx)
+	return nil
+}
+
+func (p *Provider) isSet(node *api.IslandoraObject) (bool, error) {
+	if p.opts.Mods.Terms == nil {
+		return true, nil
+	}
+	if node.FieldModel == nil {
+		return false, nil
+	}
+	for _, ref := range *node.FieldModel {
+		term, err := p.opts.Mods.Terms.Term(ref.TargetId)
+		if err != nil {
+			return false, fmt.Errorf("unable to load model %d: %v", ref.TargetId, err)
+		}
+		for _, name := range term.Name {
+			if slices.Contains(p.opts.SetModels, name.Value) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := p.Handle(r.Form)
+	if err != nil {
+		slog.Error("Unable to build OAI-PMH response", "query", r.Form.Encode(), "err", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(resp)
+	if err != nil {
+		slog.Error("Unable to write OAI-PMH response", "err", err)
+		return
+	}
+	_, _ = w.Write([]byte("\n"))
+}
+
+// Handle answers an OAI-PMH request.
+// Protocol errors are reported in the response, the error is for failed crosswalks.
+func (p *Provider) Handle(args url.Values) (*Response, error) {
+	resp := &Response{
+		XmlnsXsi:       xsiNamespace,
+		SchemaLocation: SchemaLocation,
+		ResponseDate:   time.Now().UTC().Format(timeFormat),
+		Request:        Request{BaseURL: p.opts.BaseURL},
+	}
+	fail := func(code, message string) (*Response, error) {
+		resp.Errors = append(resp.Errors, Error{Code: code, Message: message})
+		return resp, nil
+	}
+
+	for key, values := range args {
+		if len(values) > 1 {
+			return fail(BadArgument, fmt.Sprintf("repeated argument %s", key))
+		}
+	}
+
+	verb := args.Get("verb")
+	allowed, ok := verbArguments[verb]
+	if !ok {
+		return fail(BadVerb, fmt.Sprintf("illegal verb %q", verb))
+	}
+	for key := range args {
+		if key != "verb" && !slices.Contains(allowed, key) {
+			return fail(BadArgument, fmt.Sprintf("illegal argument %s for %s", key, verb))
+		}
+	}
+	if args.Has("resumptionToken") && len(args) > 2 {
+		return fail(BadArgument, "resumptionToken is an exclusive argument")
+	}
+
+	resp.Request = Request{
+		Verb:            verb,
+		Identifier:      args.Get("identifier"),
+		MetadataPrefix:  args.Get("metadataPrefix"),
+		From:            args.Get("from"),
+		Until:           args.Get("until"),
+		Set:             args.Get("set"),
+		ResumptionToken: args.Get("resumptionToken"),
+		BaseURL:         p.opts.BaseURL,
+	}
+
+	idx := p.index.Load()
+	switch verb {
+	case "Identify":
+		earliest := idx.earliest
+		if earliest.IsZero() {
+			earliest = time.Unix(0, 0)
+		}
+		resp.Identify = &Identify{
+			RepositoryName:    p.opts.RepositoryName,
+			BaseURL:           p.opts.BaseURL,
+			ProtocolVersion:   "2.0",
+			AdminEmail:        p.opts.AdminEmails,
+			EarliestDatestamp: earliest.UTC().Format(timeFormat),
+			DeletedRecord:     "no",
+			Granularity:       Granularity,
+		}
+	case "ListMetadataFormats":
+		if id := args.Get("identifier"); id != "" {
+			if _, ok := p.lookup(idx, id); !ok {
+				return fail(IdDoesNotExist, fmt.Sprintf("unknown identifier %s", id))
+			}
+		}
+		resp.ListMetadataFormats = &ListMetadataFormats{}
+		for _, f := range formats {
+			resp.ListMetadataFormats.MetadataFormat = append(resp.ListMetadataFormats.MetadataFormat, f.MetadataFormat)
+		}
+	case "ListSets":
+		if args.Has("resumptionToken") {
+			return fail(BadResumptionToken, "sets are listed in a single response")
+		}
+		if len(idx.sets) == 0 {
+			return fail(NoSetHierarchy, "the repository has no sets")
+		}
+		resp.ListSets = &ListSets{Set: idx.sets}
+	case "GetRecord":
+		id, prefix := args.Get("identifier"), args.Get("metadataPrefix")
+		if id == "" || prefix == "" {
+			return fail(BadArgument, "identifier and metadataPrefix are required")
+		}
+		f, ok := lookupFormat(prefix)
+		if !ok {
+			return fail(CannotDisseminateFormat, fmt.Sprintf("unsupported metadataPrefix %s", prefix))
+		}
+		r, ok := p.lookup(idx, id)
+		if !ok {
+			return fail(IdDoesNotExist, fmt.Sprintf("unknown identifier %s", id))
+		}
+		rec, err := p.record(r, f)
+		if err != nil {
+			return nil, err
+		}
+		resp.GetRecord = &GetRecord{Record: rec}
+	case "ListIdentifiers", "ListRecords":
+		var q query
+		if token := args.Get("resumptionToken"); token != "" {
+			q, err := decodeToken(token)
+			if err != nil {
+				return fail(BadResumptionToken, "invalid or expired resumptionToken")
+			}
+			return p.list(resp, idx, verb, q)
+		}
+		q.Prefix, q.From, q.Until, q.Set = args.Get("metadataPrefix"), args.Get("from"), args.Get("until"), args.Get("set")
+		if q.Prefix == "" {
+			return fail(BadArgument, "metadataPrefix is required")
+		}
+		if code, message := q.validate(); code != "" {
+			return fail(code, message)
+		}
+		if q.Set != "" && len(idx.sets) == 0 {
+			return fail(NoSetHierarchy, "the repository has no sets")
+		}
+		if q.Set != "" && !slices.ContainsFunc(idx.sets, func(s Set) bool { return s.SetSpec == q.Set }) {
+			return fail(NoRecordsMatch, fmt.Sprintf("unknown set %s", q.Set))
+		}
+		return p.list(resp, idx, verb, q)
+	}
+
+	return resp, nil
+}
+
+var verbArguments = map[string][]string{
+	"Identify":            {},
+	"ListMetadataFormats": {"identifier"},
+	"ListSets":            {"resumptionToken"},
+	"GetRecord":           {"identifier", "metadataPrefix"},
+	"ListIdentifiers":     {"metadataPrefix", "from", "until", "set", "resumptionToken"},
+	"ListRecords":         {"metadataPrefix", "from", "until", "set", "resumptionToken"},
+}
+
+func (p *Provider) list(resp *Response, idx *index, verb string, q query) (*Response, error) {
+	f, ok := lookupFormat(q.Prefix)
+	if !ok {
+		resp.Errors = append(resp.Errors, Error{Code: CannotDisseminateFormat, Message: fmt.Sprintf("unsupported metadataPrefix %s", q.Prefix)})
+		return resp, nil
+	}
+
+	from, until, _ := q.bounds()
+	matches := []*record{}
+	for _, r := range idx.records {
+		if !from.IsZero() && r.datestamp.Before(from) {
+			continue
+		}
+		if !until.IsZero() && r.datestamp.After(until) {
+			continue
+		}
+		if q.Set != "" && !slices.Contains(r.sets, q.Set) {
+			continue
+		}
+		matches = append(matches, r)
+	}
+	if len(matches) == 0 {
+		resp.Errors = append(resp.Errors, Error{Code: NoRecordsMatch, Message: "no records match the request"})
+		return resp, nil
+	}
+
+	start := 0
+	if q.Nid != 0 {
+		start = sort.Search(len(matches), func(i int) bool { return q.resumes(matches[i]) })
+	}
+	end := min(start+p.opts.PageSize, len(matches))
+	var token *ResumptionToken
+	if start > 0 || end < len(matches) {
+		token = &ResumptionToken{CompleteListSize: len(matches), Cursor: start}
+		if end < len(matches) {
+			last := matches[end-1]
+			next := q
+			next.After, next.Nid = last.datestamp.Unix(), last.nid
+			token.Value = next.encode()
+		}
+	}
+
+	page := matches[start:end]
+	if verb == "ListIdentifiers" {
+		resp.ListIdentifiers = &ListIdentifiers{ResumptionToken: token}
+		for _, r := range page {
+			resp.ListIdentifiers.Header = append(resp.ListIdentifiers.Header, p.header(r))
+		}
+		return resp, nil
+	}
+
+	resp.ListRecords = &ListRecords{ResumptionToken: token}
+	for _, r := range page {
+		rec, err := p.record(r, f)
+		if err != nil {
+			return nil, err
+		}
+		resp.ListRecords.Record = append(resp.ListRecords.Record, rec)
+	}
+
+	return resp, nil
+}
+
+func (p *Provider) header(r *record) Header {
+	return Header{
+		Identifier: p.Identifier(r.nid),
+		Datestamp:  r.datestamp.UTC().Format(timeFormat),
+		SetSpec:    r.sets,
+	}
+}
+
+func (p *Provider) record(r *record, f format) (Record, error) {
+	m, err := mods.FromNode(r.node, p.opts.Mods)
+	if err != nil {
+		return Record{}, fmt.Errorf("unable to crosswalk node %d: %v", r.nid, err)
+	}
+	data, err := xml.Marshal(f.encode(m))
+	if err != nil {
+		return Record{}, fmt.Errorf("unable to encode node %d as %s: %v", r.nid, f.MetadataPrefix, err)
+	}
+
+	return Record{Header: p.header(r), Metadata: &Metadata{Inner: data}}, nil
+}
+
+// Identifier returns the OAI identifier of a node
+func (p *Provider) Identifier(nid int) string {
+	return fmt.Sprintf("oai:%s:node/%d", p.opts.RepositoryIdentifier, nid)
+}
+
+func (p *Provider) lookup(idx *index, identifier string) (*record, bool) {
+	id, ok := strings.CutPrefix(identifier, fmt.Sprintf("oai:%s:node/", p.opts.RepositoryIdentifier))
+	if !ok {
+		return nil, false
+	}
+	nid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false
+	}
+	r, ok := idx.byNid[nid]
+
+	return r, ok
+}
+
+func lookupFormat(prefix string) (format, bool) {
+	for _, f := range formats {
+		if f.MetadataPrefix == prefix {
+			return f, true
+		}
+	}
+
+	return format{}, false
+}
+
+func setSpec(nid int) string {
+	return fmt.Sprintf("collection_%d", nid)
+}
+
+func title(node *api.IslandoraObject) string {
+	if node.Title == nil || len(*node.Title) == 0 {
+		return node.Nid.String()
+	}
+
+	return (*node.Title)[0].Value
+}
+
+// datestamp is when the node last changed, falling back to when it was created
+func datestamp(node *api.IslandoraObject) (time.Time, error) {
+	for _, field := range []*model.GenericField{node.Changed, node.Created} {
+		if field == nil || len(*field) == 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, (*field)[0].Value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %v", (*field)[0].Value, err)
+		}
+		return t.UTC().Truncate(time.Second), nil
+	}
+
+	return time.Unix(0, 0).UTC(), nil
+}
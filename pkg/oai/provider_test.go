@@ -0,0 +1,197 @@
+package oai
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNodes = `[
+	{"nid": [{"value": 1}], "title": [{"value": "Steel Collection"}], "changed": [{"value": "2024-01-01T00:00:00+00:00"}]},
+	{"nid": [{"value": 2}], "title": [{"value": "A book"}], "changed": [{"value": "2024-03-01T12:00:00-05:00"}], "field_member_of": [{"target_id": 1}]},
+	{"nid": [{"value": 3}], "title": [{"value": "Page 1"}], "changed": [{"value": "2024-02-01T00:00:00+00:00"}], "field_member_of": [{"target_id": 2}]},
+	{"nid": [{"value": 4}], "title": [{"value": "Draft"}], "status": [{"value": false}], "changed": [{"value": "2024-02-01T00:00:00+00:00"}]}
+]`
+
+func testProvider(t *testing.T) *Provider {
+	nodes, err := islandora.ParseNodes([]byte(testNodes))
+	require.NoError(t, err)
+
+	p := NewProvider(Options{
+		BaseURL:              "https://example.com/oai",
+		RepositoryIdentifier: "example.com",
+		PageSize:             2,
+	})
+	require.NoError(t, p.SetNodes(nodes))
+
+	return p
+}
+
+func handle(t *testing.T, p *Provider, query string) *Response {
+	args, err := url.ParseQuery(query)
+	require.NoError(t, err)
+	resp, err := p.Handle(args)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func errorCode(resp *Response) string {
+	if len(resp.Errors) == 0 {
+		return ""
+	}
+
+	return resp.Errors[0].Code
+}
+
+func TestIdentify(t *testing.T) {
+	resp := handle(t, testProvider(t), "verb=Identify")
+	require.NotNil(t, resp.Identify)
+	assert.Equal(t, "2024-01-01T00:00:00Z", resp.Identify.EarliestDatestamp)
+	assert.Equal(t, "https://example.com/oai", resp.Identify.BaseURL)
+	assert.Equal(t, Granularity, resp.Identify.Granularity)
+}
+
+func TestListSets(t *testing.T) {
+	resp := handle(t, testProvider(t), "verb=ListSets")
+	require.NotNil(t, resp.ListSets)
+	assert.Equal(t, []Set{
+		{SetSpec: "collection_1", SetName: "Steel Collection"},
+		{SetSpec: "collection_2", SetName: "A book"},
+	}, resp.ListSets.Set)
+}
+
+func TestNoSetHierarchy(t *testing.T) {
+	nodes, err := islandora.ParseNodes([]byte(`[
+		{"nid": [{"value": 1}], "title": [{"value": "A photo"}], "changed": [{"value": "2024-01-01T00:00:00+00:00"}]}
+	]`))
+	require.NoError(t, err)
+	p := NewProvider(Options{BaseURL: "https://example.com/oai", RepositoryIdentifier: "example.com"})
+	require.NoError(t, p.SetNodes(nodes))
+
+	for _, query := range []string{
+		"verb=ListSets",
+		"verb=ListRecords&metadataPrefix=oai_dc&set=collection_1",
+		"verb=ListIdentifiers&metadataPrefix=oai_dc&set=collection_1",
+	} {
+		assert.Equal(t, NoSetHierarchy, errorCode(handle(t, p, query)), query)
+	}
+	assert.Empty(t, errorCode(handle(t, p, "verb=ListRecords&metadataPrefix=oai_dc")))
+}
+
+func TestListIdentifiers(t *testing.T) {
+	p := testProvider(t)
+
+	resp := handle(t, p, "verb=ListIdentifiers&metadataPrefix=oai_dc")
+	require.NotNil(t, resp.ListIdentifiers)
+	require.Len(t, resp.ListIdentifiers.Header, 2)
+	assert.Equal(t, Header{Identifier: "oai:example.com:node/1", Datestamp: "2024-01-01T00:00:00Z"}, resp.ListIdentifiers.Header[0])
+	assert.Equal(t, "oai:example.com:node/3", resp.ListIdentifiers.Header[1].Identifier)
+	assert.Equal(t, []string{"collection_1", "collection_2"}, resp.ListIdentifiers.Header[1].SetSpec)
+	token := resp.ListIdentifiers.ResumptionToken
+	require.NotNil(t, token)
+	assert.Equal(t, 3, token.CompleteListSize)
+	assert.Equal(t, 0, token.Cursor)
+
+	resp = handle(t, p, "verb=ListIdentifiers&resumptionToken="+token.Value)
+	require.NotNil(t, resp.ListIdentifiers)
+	require.Len(t, resp.ListIdentifiers.Header, 1)
+	assert.Equal(t, Header{
+		Identifier: "oai:example.com:node/2",
+		Datestamp:  "2024-03-01T17:00:00Z",
+		SetSpec:    []string{"collection_1"},
+	}, resp.ListIdentifiers.Header[0])
+	assert.Equal(t, &ResumptionToken{CompleteListSize: 3, Cursor: 2}, resp.ListIdentifiers.ResumptionToken)
+
+	resp = handle(t, p, "verb=ListIdentifiers&metadataPrefix=mods&set=collection_2")
+	require.Len(t, resp.ListIdentifiers.Header, 1)
+	assert.Nil(t, resp.ListIdentifiers.ResumptionToken)
+
+	resp = handle(t, p, "verb=ListIdentifiers&metadataPrefix=mods&from=2024-02-01&until=2024-02-01")
+	require.Len(t, resp.ListIdentifiers.Header, 1)
+	assert.Equal(t, "oai:example.com:node/3", resp.ListIdentifiers.Header[0].Identifier)
+
+	// tokens survive reloading the same nodes
+	nodes, err := islandora.ParseNodes([]byte(testNodes))
+	require.NoError(t, err)
+	require.NoError(t, p.SetNodes(nodes))
+	resp = handle(t, p, "verb=ListIdentifiers&resumptionToken="+token.Value)
+	require.NotNil(t, resp.ListIdentifiers)
+	require.Len(t, resp.ListIdentifiers.Header, 1)
+	assert.Equal(t, "oai:example.com:node/2", resp.ListIdentifiers.Header[0].Identifier)
+
+	// a record changed after the token was issued is listed again
+	nodes, err = islandora.ParseNodes([]byte(strings.Replace(testNodes, "2024-02-01T00:00:00+00:00", "2024-04-01T00:00:00+00:00", 1)))
+	require.NoError(t, err)
+	require.NoError(t, p.SetNodes(nodes))
+	resp = handle(t, p, "verb=ListIdentifiers&resumptionToken="+token.Value)
+	require.NotNil(t, resp.ListIdentifiers)
+	require.Len(t, resp.ListIdentifiers.Header, 2)
+	assert.Equal(t, "oai:example.com:node/2", resp.ListIdentifiers.Header[0].Identifier)
+	assert.Equal(t, "oai:example.com:node/3", resp.ListIdentifiers.Header[1].Identifier)
+	assert.Equal(t, &ResumptionToken{CompleteListSize: 3, Cursor: 1}, resp.ListIdentifiers.ResumptionToken)
+}
+
+func TestErrors(t *testing.T) {
+	p := testProvider(t)
+
+	for query, code := range map[string]string{
+		"":                                     BadVerb,
+		"verb=Bogus":                           BadVerb,
+		"verb=Identify&set=foo":                BadArgument,
+		"verb=ListRecords":                     BadArgument,
+		"verb=ListRecords&metadataPrefix=marc": CannotDisseminateFormat,
+		"verb=ListRecords&metadataPrefix=oai_dc&from=2024-01-01&until=2024-02-01T00:00:00Z": BadArgument,
+		"verb=ListRecords&metadataPrefix=oai_dc&from=2025-01-01":                            NoRecordsMatch,
+		"verb=ListRecords&metadataPrefix=oai_dc&set=collection_9":                           NoRecordsMatch,
+		"verb=ListRecords&resumptionToken=bogus":                                            BadResumptionToken,
+		"verb=ListRecords&resumptionToken=x&metadataPrefix=oai_dc":                          BadArgument,
+		"verb=GetRecord&identifier=oai:example.com:node/4&metadataPrefix=oai_dc":            IdDoesNotExist,
+		"verb=GetRecord&identifier=oai:example.com:node/1":                                  BadArgument,
+		"verb=ListMetadataFormats&identifier=oai:other:node/1":                              IdDoesNotExist,
+		"verb=Identify&verb=Identify":                                                       BadArgument,
+	} {
+		assert.Equal(t, code, errorCode(handle(t, p, query)), query)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	server := httptest.NewServer(testProvider(t))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "?verb=GetRecord&metadataPrefix=oai_dc&identifier=oai:example.com:node/2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "text/xml; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(body), `<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/"`)
+	assert.Contains(t, string(body), `<request verb="GetRecord" identifier="oai:example.com:node/2" metadataPrefix="oai_dc">https://example.com/oai</request>`)
+	assert.Contains(t, string(body), `<dc:title>A book</dc:title>`)
+
+	var parsed struct {
+		GetRecord struct {
+			Record struct {
+				Header Header `xml:"header"`
+			} `xml:"record"`
+		} `xml:"GetRecord"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &parsed))
+	assert.Equal(t, "2024-03-01T17:00:00Z", parsed.GetRecord.Record.Header.Datestamp)
+
+	resp, err = http.PostForm(server.URL, url.Values{"verb": {"ListRecords"}, "metadataPrefix": {"mods"}, "set": {"collection_2"}})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(body), "<mods "))
+	assert.Contains(t, string(body), `<title>Page 1</title>`)
+}
@@ -0,0 +1,97 @@
+package oai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// query is a list request. Resumption tokens encode the query with the
+// datestamp and nid of the last record listed so the provider stays
+// stateless. Records are ordered by datestamp then nid, so a token keeps
+// working when the nodes are reloaded and records changed since it was
+// issued are listed again on a later page.
+type query struct {
+	Prefix string `json:"p"`
+	From   string `json:"f,omitempty"`
+	Until  string `json:"u,omitempty"`
+	Set    string `json:"s,omitempty"`
+	After  int64  `json:"a"`
+	Nid    int    `json:"n"`
+}
+
+func (q query) encode() string {
+	data, _ := json.Marshal(q)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeToken(token string) (query, error) {
+	var q query
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return q, err
+	}
+	err = json.Unmarshal(data, &q)
+	if err != nil {
+		return q, err
+	}
+	if q.Nid <= 0 {
+		return q, fmt.Errorf("missing cursor")
+	}
+
+	return q, nil
+}
+
+// resumes reports whether a record comes after the last record the token listed
+func (q query) resumes(r *record) bool {
+	if r.datestamp.Unix() != q.After {
+		return r.datestamp.Unix() > q.After
+	}
+
+	return r.nid > q.Nid
+}
+
+// validate returns the protocol error for bad from and until arguments
+func (q query) validate() (string, string) {
+	_, _, err := q.bounds()
+	if err != nil {
+		return BadArgument, err.Error()
+	}
+	if q.From != "" && q.Until != "" && len(q.From) != len(q.Until) {
+		return BadArgument, "from and until must have the same granularity"
+	}
+
+	return "", ""
+}
+
+// bounds parses from and until, an until date includes the whole day
+func (q query) bounds() (time.Time, time.Time, error) {
+	from, err := parseDatestamp(q.From)
+	if err != nil {
+		return from, time.Time{}, fmt.Errorf("invalid from %q", q.From)
+	}
+	until, err := parseDatestamp(q.Until)
+	if err != nil {
+		return from, until, fmt.Errorf("invalid until %q", q.Until)
+	}
+	if len(q.Until) == len(dateFormat) {
+		until = until.Add(24*time.Hour - time.Second)
+	}
+	if !from.IsZero() && !until.IsZero() && from.After(until) {
+		return from, until, fmt.Errorf("from %s is after until %s", q.From, q.Until)
+	}
+
+	return from, until, nil
+}
+
+func parseDatestamp(value string) (time.Time, error) {
+	switch len(value) {
+	case 0:
+		return time.Time{}, nil
+	case len(dateFormat):
+		return time.Parse(dateFormat, value)
+	default:
+		return time.Parse(timeFormat, value)
+	}
+}
@@ -0,0 +1,42 @@
+package schemaorg
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// ScholarRequired are the meta tags Google Scholar needs to index an item
+var ScholarRequired = []string{
+	"citation_title",
+	"citation_author",
+	"citation_publication_date",
+	"citation_pdf_url",
+}
+
+// HTML writes the meta tags as HTML <meta> elements, one per line
+func HTML(tags []Meta) string {
+	var b strings.Builder
+	for _, tag := range tags {
+		fmt.Fprintf(&b, "<meta name=\"%s\" content=\"%s\">\n", html.EscapeString(tag.Name), html.EscapeString(tag.Content))
+	}
+
+	return b.String()
+}
+
+// Check returns why Google Scholar would not index the node
+func Check(m *Metadata) []string {
+	if m.Hidden {
+		return []string{"citation_* meta tags are hidden by field_hide_gscholar_metatags"}
+	}
+
+	problems := []string{}
+	for _, name := range ScholarRequired {
+		if !slices.ContainsFunc(m.Tags, func(tag Meta) bool { return tag.Name == name }) {
+			problems = append(problems, "missing "+name)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,485 @@
+// Package schemaorg describes Islandora nodes as schema.org JSON-LD
+// and the Highwire Press citation_* meta tags Google Scholar indexes
+package schemaorg
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/mods"
+)
+
+const Context = "https://schema.org"
+
+type Options struct {
+	Mapping *mods.Mapping
+	// Terms resolves taxonomy terms and linked agents, which are skipped when nil
+	Terms islandora.TermLookup
+	// BaseUrl is used for the node's URL
+	BaseUrl string
+	// Publisher is used when the node has no publisher
+	Publisher string
+	// FileUse is the media use of the file linked as the PDF or content URL, default Original File
+	FileUse string
+}
+
+// Thing is a schema.org CreativeWork, along with the
+// PublicationIssue, PublicationVolume and Periodical an article is part of
+type Thing struct {
+	Context            string          `json:"@context,omitempty"`
+	Type               string          `json:"@type"`
+	Id                 string          `json:"@id,omitempty"`
+	Url                string          `json:"url,omitempty"`
+	Name               string          `json:"name,omitempty"`
+	Headline           string          `json:"headline,omitempty"`
+	AlternateName      []string        `json:"alternateName,omitempty"`
+	Author             []Agent         `json:"author,omitempty"`
+	Editor             []Agent         `json:"editor,omitempty"`
+	Contributor        []Agent         `json:"contributor,omitempty"`
+	Publisher          *Agent          `json:"publisher,omitempty"`
+	SourceOrganization *Agent          `json:"sourceOrganization,omitempty"`
+	InSupportOf        string          `json:"inSupportOf,omitempty"`
+	DatePublished      string          `json:"datePublished,omitempty"`
+	DateCreated        string          `json:"dateCreated,omitempty"`
+	DateModified       string          `json:"dateModified,omitempty"`
+	Description        string          `json:"description,omitempty"`
+	Keywords           []string        `json:"keywords,omitempty"`
+	InLanguage         string          `json:"inLanguage,omitempty"`
+	License            string          `json:"license,omitempty"`
+	CopyrightNotice    string          `json:"copyrightNotice,omitempty"`
+	Identifier         []PropertyValue `json:"identifier,omitempty"`
+	SameAs             []string        `json:"sameAs,omitempty"`
+	IsPartOf           *Thing          `json:"isPartOf,omitempty"`
+	Issn               string          `json:"issn,omitempty"`
+	VolumeNumber       string          `json:"volumeNumber,omitempty"`
+	IssueNumber        string          `json:"issueNumber,omitempty"`
+	PageStart          string          `json:"pageStart,omitempty"`
+	PageEnd            string          `json:"pageEnd,omitempty"`
+	ContentUrl         string          `json:"contentUrl,omitempty"`
+	EncodingFormat     string          `json:"encodingFormat,omitempty"`
+	Encoding           []MediaObject   `json:"encoding,omitempty"`
+	Distribution       []MediaObject   `json:"distribution,omitempty"`
+}
+
+type Agent struct {
+	Type        string  `json:"@type"`
+	Name        string  `json:"name"`
+	GivenName   string  `json:"givenName,omitempty"`
+	FamilyName  string  `json:"familyName,omitempty"`
+	SameAs      string  `json:"sameAs,omitempty"`
+	Affiliation []Agent `json:"affiliation,omitempty"`
+}
+
+type PropertyValue struct {
+	Type       string `json:"@type"`
+	PropertyID string `json:"propertyID"`
+	Value      string `json:"value"`
+}
+
+type MediaObject struct {
+	Type           string `json:"@type"`
+	ContentUrl     string `json:"contentUrl"`
+	EncodingFormat string `json:"encodingFormat,omitempty"`
+	ContentSize    string `json:"contentSize,omitempty"`
+}
+
+// Meta is a citation_* meta tag
+type Meta struct {
+	Name    string
+	Content string
+}
+
+// Metadata is a node's JSON-LD and the equivalent meta tags
+type Metadata struct {
+	Nid    int
+	JsonLd *Thing
+	Tags   []Meta
+	// Hidden is set by field_hide_gscholar_metatags, Tags is empty when it is
+	Hidden bool
+}
+
+// Type picks the schema.org type from a node's model, genre and resource type names.
+// Theses, datasets and articles are recognized by genre, otherwise
+// Image nodes are an ImageObject and Digital Documents a ScholarlyArticle.
+func Type(model string, genres []string, resourceType string) string {
+	for _, genre := range genres {
+		g := strings.ToLower(genre)
+		switch {
+		case strings.Contains(g, "thesis"), strings.Contains(g, "theses"), strings.Contains(g, "dissertation"):
+			return "Thesis"
+		case strings.Contains(g, "dataset"):
+			return "Dataset"
+		case strings.Contains(g, "article"):
+			return "ScholarlyArticle"
+		}
+	}
+	switch {
+	case strings.EqualFold(resourceType, "Dataset"):
+		return "Dataset"
+	case model == "Image", strings.EqualFold(resourceType, "Still Image"):
+		return "ImageObject"
+	case model == "Digital Document":
+		return "ScholarlyArticle"
+	}
+
+	return "CreativeWork"
+}
+
+// FromNode describes a node as JSON-LD and meta tags.
+// media are the node's media, the one with Options.FileUse is the PDF or content URL.
+func FromNode(node *api.IslandoraObject, media []islandora.Media, opts Options) (*Metadata, error) {
+	if opts.FileUse == "" {
+		opts.FileUse = "Original File"
+	}
+	record, err := mods.FromNode(node, mods.Options{Mapping: opts.Mapping, Terms: opts.Terms, BaseUrl: opts.BaseUrl})
+	if err != nil {
+		return nil, err
+	}
+	agents, err := islandora.LinkedAgents(node, opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+	models, err := islandora.FieldStrings(node, "field_model", opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+	degrees, err := islandora.FieldStrings(node, "field_degree_name", opts.Terms)
+	if err != nil {
+		return nil, err
+	}
+	file, err := contentFile(media, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []string{}
+	for _, g := range record.Genre {
+		genres = append(genres, g.Value)
+	}
+	resourceType := ""
+	if len(record.TypeOfResource) > 0 {
+		resourceType = record.TypeOfResource[0].Value
+	}
+	model := ""
+	if len(models) > 0 {
+		model = models[0]
+	}
+
+	t := &Thing{
+		Context: Context,
+		Type:    Type(model, genres, resourceType),
+		Url:     nodeUrl(node, opts.BaseUrl),
+	}
+	t.Id = t.Url
+	m := &Metadata{JsonLd: t}
+	if node.Nid != nil && len(*node.Nid) > 0 {
+		m.Nid = (*node.Nid)[0].Value
+	}
+
+	for _, title := range record.TitleInfo {
+		value := title.Title
+		if title.SubTitle != "" {
+			value += ": " + title.SubTitle
+		}
+		if t.Name == "" && title.Type == "" {
+			t.Name = value
+			continue
+		}
+		t.AlternateName = append(t.AlternateName, value)
+	}
+	if t.Type == "ScholarlyArticle" {
+		t.Headline = t.Name
+	}
+
+	for _, agent := range agents {
+		a := schemaAgent(agent)
+		switch agent.Relator {
+		case "aut", "cre":
+			t.Author = append(t.Author, a)
+		case "edt":
+			t.Editor = append(t.Editor, a)
+		case "pbl":
+			if t.Publisher == nil {
+				t.Publisher = &a
+			}
+		case "dgg":
+			if t.SourceOrganization == nil {
+				t.SourceOrganization = &a
+			}
+		default:
+			t.Contributor = append(t.Contributor, a)
+		}
+	}
+	for _, origin := range record.OriginInfo {
+		if t.Publisher == nil && len(origin.Publisher) > 0 {
+			t.Publisher = &Agent{Type: "Organization", Name: origin.Publisher[0].Value}
+		}
+		if t.DatePublished == "" && len(origin.DateIssued) > 0 {
+			t.DatePublished, _ = Date(origin.DateIssued[0].Value)
+		}
+		if t.DateCreated == "" && len(origin.DateCreated) > 0 {
+			t.DateCreated, _ = Date(origin.DateCreated[0].Value)
+		}
+	}
+	if t.Publisher == nil && opts.Publisher != "" {
+		t.Publisher = &Agent{Type: "Organization", Name: opts.Publisher}
+	}
+	if node.Changed != nil && len(*node.Changed) > 0 {
+		t.DateModified = (*node.Changed)[0].Value
+	}
+	if t.Type == "Thesis" && len(degrees) > 0 {
+		t.InSupportOf = degrees[0]
+	}
+
+	if len(record.Abstract) > 0 {
+		t.Description = record.Abstract[0].Value
+	}
+	t.Keywords = keywords(record)
+	t.InLanguage = language(record)
+	for _, a := range record.AccessCondition {
+		if strings.HasPrefix(a.Value, "http://") || strings.HasPrefix(a.Value, "https://") {
+			if t.License == "" {
+				t.License = a.Value
+			}
+		} else if t.CopyrightNotice == "" {
+			t.CopyrightNotice = a.Value
+		}
+	}
+
+	doi := islandora.Doi(node)
+	if doi != "" {
+		t.Identifier = append(t.Identifier, PropertyValue{Type: "PropertyValue", PropertyID: "DOI", Value: doi})
+		t.SameAs = append(t.SameAs, "https://doi.org/"+doi)
+	}
+
+	journal, issn := "", ""
+	for _, item := range record.RelatedItem {
+		if item.Type != "host" {
+			continue
+		}
+		if len(item.TitleInfo) > 0 {
+			journal = item.TitleInfo[0].Title
+		}
+		if len(item.Identifier) > 0 {
+			issn = item.Identifier[0].Value
+		}
+		break
+	}
+	volume := islandora.PartNumber(node, "volume")
+	issue := islandora.PartNumber(node, "issue")
+	firstPage, lastPage := islandora.Pages(node)
+	if t.Type == "ScholarlyArticle" {
+		t.PageStart, t.PageEnd = firstPage, lastPage
+		t.IsPartOf = periodical(journal, issn, volume, issue)
+	}
+
+	if file != nil {
+		object := MediaObject{Type: "MediaObject", ContentUrl: file.FileUrl(), EncodingFormat: file.Mime()}
+		if size := file.Size(); size > 0 {
+			object.ContentSize = fmt.Sprintf("%d B", size)
+		}
+		switch t.Type {
+		case "ImageObject":
+			t.ContentUrl, t.EncodingFormat = object.ContentUrl, object.EncodingFormat
+		case "Dataset":
+			object.Type = "DataDownload"
+			t.Distribution = append(t.Distribution, object)
+		default:
+			t.Encoding = append(t.Encoding, object)
+		}
+	}
+
+	if node.FieldHideGscholarMetatags != nil && len(*node.FieldHideGscholarMetatags) > 0 && (*node.FieldHideGscholarMetatags)[0].Value {
+		m.Hidden = true
+		return m, nil
+	}
+
+	m.add("citation_title", t.Name)
+	for _, author := range t.Author {
+		m.add("citation_author", citationName(author))
+		for _, affiliation := range author.Affiliation {
+			m.add("citation_author_institution", affiliation.Name)
+		}
+		m.add("citation_author_orcid", author.SameAs)
+	}
+	if _, date := Date(firstDate(record)); date != "" {
+		m.add("citation_publication_date", date)
+	}
+	if t.Publisher != nil {
+		m.add("citation_publisher", t.Publisher.Name)
+	}
+	switch t.Type {
+	case "Thesis":
+		institution := opts.Publisher
+		if t.SourceOrganization != nil {
+			institution = t.SourceOrganization.Name
+		} else if t.Publisher != nil {
+			institution = t.Publisher.Name
+		}
+		m.add("citation_dissertation_institution", institution)
+	case "ScholarlyArticle":
+		m.add("citation_journal_title", journal)
+		m.add("citation_issn", issn)
+		m.add("citation_volume", volume)
+		m.add("citation_issue", issue)
+		m.add("citation_firstpage", firstPage)
+		m.add("citation_lastpage", lastPage)
+	}
+	m.add("citation_doi", doi)
+	if file != nil && file.Mime() == "application/pdf" {
+		m.add("citation_pdf_url", file.FileUrl())
+	}
+	m.add("citation_abstract_html_url", t.Url)
+	m.add("citation_language", t.InLanguage)
+	for _, keyword := range t.Keywords {
+		m.add("citation_keywords", keyword)
+	}
+
+	return m, nil
+}
+
+func (m *Metadata) add(name, content string) {
+	if content != "" {
+		m.Tags = append(m.Tags, Meta{Name: name, Content: content})
+	}
+}
+
+// Date converts an EDTF date into an ISO 8601 date and a citation_publication_date (YYYY/MM/DD).
+// Qualifiers are dropped, and intervals and sets use their first date.
+// Both are empty when the year isn't known.
+func Date(value string) (string, string) {
+	parsed, err := edtf.Parse(value)
+	if err != nil {
+		return "", ""
+	}
+	d := parsed.Start
+	if parsed.Kind == edtf.KindSet && len(parsed.Dates) > 0 {
+		d = parsed.Dates[0]
+	}
+	year, ok := d.YearInt()
+	if !ok {
+		return "", ""
+	}
+
+	parts := []string{fmt.Sprintf("%04d", year)}
+	if d.Month != "" && d.Season() == 0 && !strings.Contains(d.Month, "X") {
+		parts = append(parts, d.Month)
+		if d.Day != "" && !strings.Contains(d.Day, "X") {
+			parts = append(parts, d.Day)
+		}
+	}
+
+	return strings.Join(parts, "-"), strings.Join(parts, "/")
+}
+
+func firstDate(record *mods.Mods) string {
+	for _, origin := range record.OriginInfo {
+		for _, dates := range [][]mods.Date{origin.DateIssued, origin.DateCreated, origin.CopyrightDate} {
+			if len(dates) > 0 {
+				return dates[0].Value
+			}
+		}
+	}
+
+	return ""
+}
+
+func schemaAgent(agent islandora.Agent) Agent {
+	if !agent.Personal() {
+		return Agent{Type: "Organization", Name: agent.Name}
+	}
+
+	a := Agent{Type: "Person", Name: agent.Name, SameAs: agent.OrcidUrl()}
+	a.FamilyName, a.GivenName = agent.FamilyGiven()
+	if a.GivenName != "" {
+		a.Name = a.GivenName + " " + a.FamilyName
+	}
+	for _, affiliation := range agent.Affiliations {
+		a.Affiliation = append(a.Affiliation, Agent{Type: "Organization", Name: affiliation})
+	}
+
+	return a
+}
+
+// citationName writes a person as "Family, Given" as Scholar prefers
+func citationName(a Agent) string {
+	if a.Type == "Person" && a.GivenName != "" {
+		return a.FamilyName + ", " + a.GivenName
+	}
+
+	return a.Name
+}
+
+func periodical(journal, issn, volume, issue string) *Thing {
+	if journal == "" && issn == "" {
+		return nil
+	}
+
+	part := &Thing{Type: "Periodical", Name: journal, Issn: issn}
+	if volume != "" {
+		part = &Thing{Type: "PublicationVolume", VolumeNumber: volume, IsPartOf: part}
+	}
+	if issue != "" {
+		part = &Thing{Type: "PublicationIssue", IssueNumber: issue, IsPartOf: part}
+	}
+
+	return part
+}
+
+func keywords(record *mods.Mods) []string {
+	keywords := []string{}
+	for _, s := range record.Subject {
+		for _, t := range slices.Concat(s.Topic, s.Geographic, s.Temporal, s.Genre) {
+			keywords = append(keywords, t.Value)
+		}
+		for _, n := range s.Name {
+			for _, part := range n.NamePart {
+				keywords = append(keywords, part.Value)
+			}
+		}
+	}
+
+	return keywords
+}
+
+func language(record *mods.Mods) string {
+	for _, l := range record.Language {
+		for _, t := range l.LanguageTerm {
+			if t.Type == "code" {
+				return t.Value
+			}
+		}
+		if len(l.LanguageTerm) > 0 {
+			return l.LanguageTerm[0].Value
+		}
+	}
+
+	return ""
+}
+
+// contentFile returns the media with Options.FileUse, or failing that the first PDF
+func contentFile(media []islandora.Media, opts Options) (*islandora.Media, error) {
+	file, err := islandora.MediaWithUse(media, opts.FileUse, opts.Terms)
+	if err != nil || file != nil {
+		return file, err
+	}
+	for i := range media {
+		if media[i].Mime() == "application/pdf" {
+			return &media[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func nodeUrl(node *api.IslandoraObject, baseUrl string) string {
+	if baseUrl == "" || node.Nid == nil || len(*node.Nid) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/node/%d", strings.TrimSuffix(baseUrl, "/"), (*node.Nid)[0].Value)
+}
@@ -0,0 +1,110 @@
+package schemaorg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNode(t *testing.T, data string) *api.IslandoraObject {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(data), &node))
+	return &node
+}
+
+var terms = islandora.MapTerms{
+	1: islandora.NewTerm("person", "Doe, Jane"),
+	2: islandora.NewTerm("genre", "Journal articles"),
+	3: islandora.NewTerm("islandora_models", "Digital Document"),
+	4: islandora.NewTerm("islandora_media_use", "Original File"),
+	5: islandora.NewTerm("islandora_models", "Image"),
+}
+
+var pdf = islandora.Media{
+	MediaUse: model.EntityReferenceField{{TargetId: 4}},
+	MimeType: model.GenericField{{Value: "application/pdf"}},
+	Document: model.FileField{{Url: "https://example.com/files/article.pdf"}},
+}
+
+func TestFromNode(t *testing.T) {
+	node := testNode(t, `{
+		"nid": [{"value": 7}],
+		"title": [{"value": "Rolling mills & \"furnaces\""}],
+		"field_model": [{"target_id": 3, "target_type": "taxonomy_term"}],
+		"field_genre": [{"target_id": 2, "target_type": "taxonomy_term"}],
+		"field_linked_agent": [{"target_id": 1, "rel_type": "relators:aut"}],
+		"field_edtf_date_issued": [{"value": "2020-05-04"}],
+		"field_identifier": [{"attr0": "doi", "value": "10.1234/mill"}],
+		"field_related_item": [{"title": "Steel Quarterly", "identifier": "1234-5678"}],
+		"field_part_detail": [{"type": "volume", "number": "12"}, {"type": "issue", "number": "3"}, {"type": "page", "number": "10–24"}]
+	}`)
+
+	m, err := FromNode(node, []islandora.Media{pdf}, Options{Terms: terms, BaseUrl: "https://example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, Check(m))
+
+	ld := m.JsonLd
+	assert.Equal(t, "ScholarlyArticle", ld.Type)
+	assert.Equal(t, "2020-05-04", ld.DatePublished)
+	assert.Equal(t, []Agent{{Type: "Person", Name: "Jane Doe", GivenName: "Jane", FamilyName: "Doe"}}, ld.Author)
+	assert.Equal(t, "10", ld.PageStart)
+	assert.Equal(t, "24", ld.PageEnd)
+	assert.Equal(t, &Thing{Type: "PublicationIssue", IssueNumber: "3", IsPartOf: &Thing{
+		Type: "PublicationVolume", VolumeNumber: "12", IsPartOf: &Thing{Type: "Periodical", Name: "Steel Quarterly", Issn: "1234-5678"},
+	}}, ld.IsPartOf)
+	assert.Equal(t, []MediaObject{{Type: "MediaObject", ContentUrl: "https://example.com/files/article.pdf", EncodingFormat: "application/pdf"}}, ld.Encoding)
+
+	assert.Equal(t, []Meta{
+		{"citation_title", `Rolling mills & "furnaces"`},
+		{"citation_author", "Doe, Jane"},
+		{"citation_publication_date", "2020/05/04"},
+		{"citation_journal_title", "Steel Quarterly"},
+		{"citation_issn", "1234-5678"},
+		{"citation_volume", "12"},
+		{"citation_issue", "3"},
+		{"citation_firstpage", "10"},
+		{"citation_lastpage", "24"},
+		{"citation_doi", "10.1234/mill"},
+		{"citation_pdf_url", "https://example.com/files/article.pdf"},
+		{"citation_abstract_html_url", "https://example.com/node/7"},
+	}, m.Tags)
+	assert.Contains(t, HTML(m.Tags), `<meta name="citation_title" content="Rolling mills &amp; &#34;furnaces&#34;">`)
+}
+
+func TestCheck(t *testing.T) {
+	node := testNode(t, `{
+		"nid": [{"value": 8}],
+		"title": [{"value": "A photograph"}],
+		"field_model": [{"target_id": 5, "target_type": "taxonomy_term"}],
+		"field_edtf_date_created": [{"value": "19XX"}]
+	}`)
+	m, err := FromNode(node, nil, Options{Terms: terms})
+	require.NoError(t, err)
+	assert.Equal(t, "ImageObject", m.JsonLd.Type)
+	assert.Equal(t, []string{"missing citation_author", "missing citation_publication_date", "missing citation_pdf_url"}, Check(m))
+
+	node.FieldHideGscholarMetatags = &model.BoolField{{Value: true}}
+	m, err = FromNode(node, nil, Options{Terms: terms})
+	require.NoError(t, err)
+	assert.Empty(t, m.Tags)
+	assert.Equal(t, []string{"citation_* meta tags are hidden by field_hide_gscholar_metatags"}, Check(m))
+}
+
+func TestFromNodeWithoutTerms(t *testing.T) {
+	node := testNode(t, `{
+		"nid": [{"value": 9}],
+		"title": [{"value": "Untitled"}],
+		"field_linked_agent": [{"target_id": 1, "rel_type": "relators:aut"}]
+	}`)
+	m, err := FromNode(node, []islandora.Media{pdf}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "Untitled", m.JsonLd.Name)
+	assert.Empty(t, m.JsonLd.Author)
+	// the PDF is found by its mime type without media use terms
+	assert.Equal(t, []string{"missing citation_author", "missing citation_publication_date"}, Check(m))
+}
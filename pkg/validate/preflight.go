@@ -0,0 +1,414 @@
+package validate
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+)
+
+// Site looks up existing content on the Islandora site being ingested into
+type Site interface {
+	// Node returns an error wrapping islandora.ErrNotFound if the node does not exist
+	Node(nid int) (*api.IslandoraObject, error)
+	TermName(tid int) (string, error)
+}
+
+// IslandoraSite is a Site backed by an Islandora site's REST API
+type IslandoraSite struct {
+	BaseUrl string
+}
+
+// Node is fetched uncached so preflight sees the live site
+func (s IslandoraSite) Node(nid int) (*api.IslandoraObject, error) {
+	return islandora.FetchNodeFresh(fmt.Sprintf("%s/node/%d?_format=json", s.BaseUrl, nid))
+}
+
+func (s IslandoraSite) TermName(tid int) (string, error) {
+	term, err := islandora.FetchTerm(fmt.Sprintf("%s/taxonomy/term/%d?_format=json", s.BaseUrl, tid))
+	if err != nil {
+		return "", err
+	}
+	if len(term.Name) == 0 {
+		return "", fmt.Errorf("term %d has no name", tid)
+	}
+
+	return term.Name[0].Value, nil
+}
+
+type PreflightOptions struct {
+	Options
+	// Site is checked for parent collections and nodes being updated
+	// site checks are skipped when nil
+	Site Site
+	// CollectionModels are the models a Parent Collection may have
+	// default: Collection
+	CollectionModels []string
+	// DerivativeFactor scales the size of the files to estimate
+	// the storage needed including derivatives, default: 1
+	DerivativeFactor float64
+}
+
+// PreflightReport is a validation report along with the storage an ingest needs
+type PreflightReport struct {
+	api.ValidationReport
+	Files     int   `json:"files"`
+	FileBytes int64 `json:"file_bytes"`
+	// EstimatedBytes is FileBytes scaled by the derivative factor
+	EstimatedBytes int64 `json:"estimated_bytes"`
+}
+
+// modelMimeTypes are the MIME type prefixes expected for a model's file
+// models not listed here accept any file
+var modelMimeTypes = map[string][]string{
+	"audio":            {"audio/"},
+	"digital document": {"application/pdf", "application/msword", "application/vnd.", "text/"},
+	"image":            {"image/"},
+	"page":             {"image/", "application/pdf"},
+	"video":            {"video/"},
+}
+
+// extensionTypes fills in common repository formats
+// missing from Go's builtin table
+var extensionTypes = map[string]string{
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".jp2":  "image/jp2",
+	".m4a":  "audio/mp4",
+	".mov":  "video/quicktime",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+	".txt":  "text/plain",
+	".wav":  "audio/wav",
+	".zip":  "application/zip",
+}
+
+type preflight struct {
+	opts      PreflightOptions
+	validator *Validator
+	report    *PreflightReport
+
+	nodes  map[int]*api.IslandoraObject
+	failed map[int]error
+	terms  map[int]string
+	files  map[string]bool
+}
+
+// Preflight validates a CSV and checks it against the site it will be ingested into
+func Preflight(r io.Reader, opts PreflightOptions) (*PreflightReport, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return PreflightRecords(records, opts)
+}
+
+func PreflightRecords(records [][]string, opts PreflightOptions) (*PreflightReport, error) {
+	if len(opts.CollectionModels) == 0 {
+		opts.CollectionModels = []string{"Collection"}
+	}
+	if opts.DerivativeFactor <= 0 {
+		opts.DerivativeFactor = 1
+	}
+
+	v := New(opts.Options)
+	validation, err := v.ValidateRecords(records)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &preflight{
+		opts:      opts,
+		validator: v,
+		report:    &PreflightReport{ValidationReport: *validation},
+		nodes:     map[int]*api.IslandoraObject{},
+		failed:    map[int]error{},
+		terms:     map[int]string{},
+		files:     map[string]bool{},
+	}
+
+	columns := v.columns(records[0])
+	for r, record := range records[1:] {
+		if isEmpty(record) {
+			continue
+		}
+		p.checkRow(r+2, columns, record)
+	}
+	p.report.EstimatedBytes = int64(float64(p.report.FileBytes) * opts.DerivativeFactor)
+
+	return p.report, nil
+}
+
+func (p *preflight) checkRow(row int, columns []workbench.Column, record []string) {
+	model := ""
+	for i, column := range columns {
+		if i >= len(record) || column.Ignored() || column.Tag().Field != "field_model" {
+			continue
+		}
+		model = p.termName(row, column, p.values(column, record[i]))
+		break
+	}
+
+	mediaTypes := []string{}
+	for i, column := range columns {
+		if i >= len(record) || column.Ignored() || column.Tag().Field != "field_media_type" {
+			continue
+		}
+		for _, value := range p.values(column, record[i]) {
+			if name := p.termName(row, column, []string{value}); strings.Contains(name, "/") {
+				mediaTypes = append(mediaTypes, name)
+			}
+		}
+	}
+
+	for i, column := range columns {
+		if i >= len(record) || column.Field == "" || column.Ignored() {
+			continue
+		}
+		values := p.values(column, record[i])
+		switch {
+		case column.Tag().Field == "field_member_of":
+			for _, value := range values {
+				p.checkCollection(row, column, value)
+			}
+		case column.Tag().Field == "node_id":
+			for _, value := range values {
+				p.checkNode(row, column, value)
+			}
+		case slices.Contains(column.Validate, "file") && !p.opts.SkipFiles:
+			for _, value := range values {
+				expected := []string{}
+				// supplemental files can be any type
+				if column.Tag().Field == "file" {
+					expected = mediaTypes
+				}
+				p.checkFile(row, column, value, model, expected)
+			}
+		}
+	}
+}
+
+// values returns a cell's values after the column's transforms
+func (p *preflight) values(column workbench.Column, cell string) []string {
+	value, err := column.Apply(cell)
+	if err != nil {
+		// already reported by validation
+		return nil
+	}
+
+	values := []string{}
+	for _, v := range strings.Split(value, "|") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func (p *preflight) node(nid int) (*api.IslandoraObject, error) {
+	if node, ok := p.nodes[nid]; ok {
+		return node, nil
+	}
+	if err, ok := p.failed[nid]; ok {
+		return nil, err
+	}
+
+	node, err := p.opts.Site.Node(nid)
+	if err != nil {
+		p.failed[nid] = err
+		return nil, err
+	}
+	p.nodes[nid] = node
+
+	return node, nil
+}
+
+// termName returns the name of the first term in values
+// term IDs are looked up on the site, names are returned as is
+func (p *preflight) termName(row int, column workbench.Column, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	value := values[0]
+	if !isInteger(value) {
+		// workbench's vocabulary_id:term name syntax
+		if _, name, ok := strings.Cut(value, ":"); ok {
+			return name
+		}
+		return value
+	}
+	if p.opts.Site == nil {
+		return ""
+	}
+
+	tid, _ := strconv.Atoi(value)
+	if name, ok := p.terms[tid]; ok {
+		return name
+	}
+	name, err := p.opts.Site.TermName(tid)
+	if err != nil {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("unable to load term %d: %v", tid, err))
+	}
+	p.terms[tid] = name
+
+	return name
+}
+
+func (p *preflight) checkNode(row int, column workbench.Column, value string) *api.IslandoraObject {
+	nid, err := strconv.Atoi(value)
+	if err != nil {
+		p.report.Add(row, column.Name, value, "must be a node ID")
+		return nil
+	}
+	if p.opts.Site == nil {
+		return nil
+	}
+
+	node, err := p.node(nid)
+	if errors.Is(err, islandora.ErrNotFound) {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("node %d does not exist", nid))
+		return nil
+	}
+	if err != nil {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("unable to load node %d: %v", nid, err))
+		return nil
+	}
+
+	return node
+}
+
+func (p *preflight) checkCollection(row int, column workbench.Column, value string) {
+	node := p.checkNode(row, column, value)
+	if node == nil {
+		return
+	}
+
+	model := ""
+	if node.FieldModel != nil && len(*node.FieldModel) > 0 {
+		tid := (*node.FieldModel)[0].TargetId
+		model = p.termName(row, column, []string{strconv.Itoa(tid)})
+	}
+	if !slices.ContainsFunc(p.opts.CollectionModels, func(m string) bool { return strings.EqualFold(m, model) }) {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("node %s has model %q, expected %s", value, model, quoteJoin(p.opts.CollectionModels)))
+	}
+}
+
+// checkFile counts a local file towards the storage estimate
+// and makes sure its content matches its extension and what is expected for the row
+func (p *preflight) checkFile(row int, column workbench.Column, value, model string, expected []string) {
+	path, ok := p.validator.localPath(value)
+	if !ok {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		// already reported by validation
+		return
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if !p.files[abs] {
+		p.files[abs] = true
+		p.report.Files++
+		p.report.FileBytes += info.Size()
+	}
+
+	sniffed, err := sniffMimeType(path)
+	if err != nil {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("unable to read file: %v", err))
+		return
+	}
+	byExtension := extensionType(path)
+	mimeType := sniffed
+	if genericMimeType(sniffed) {
+		if byExtension != "" {
+			mimeType = byExtension
+		}
+	} else if byExtension != "" && byExtension != sniffed {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("file content is %s but the extension is for %s", sniffed, byExtension))
+		return
+	}
+
+	if len(expected) > 0 {
+		if !slices.Contains(expected, mimeType) {
+			p.report.Add(row, column.Name, value, fmt.Sprintf("file is %s, expected %s", mimeType, quoteJoin(expected)))
+		}
+		return
+	}
+
+	prefixes, ok := modelMimeTypes[strings.ToLower(model)]
+	if !ok {
+		return
+	}
+	if !slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(mimeType, prefix) }) {
+		p.report.Add(row, column.Name, value, fmt.Sprintf("file is %s which is not expected for a %s", mimeType, model))
+	}
+}
+
+// sniffMimeType detects a file's MIME type from its first 512 bytes
+func sniffMimeType(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return normalizeMimeType(http.DetectContentType(buf[:n])), nil
+}
+
+func extensionType(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if t, ok := extensionTypes[ext]; ok {
+		return t
+	}
+
+	return normalizeMimeType(mime.TypeByExtension(ext))
+}
+
+func normalizeMimeType(t string) string {
+	t, _, _ = strings.Cut(t, ";")
+	t = strings.ToLower(strings.TrimSpace(t))
+	switch t {
+	case "audio/wave", "audio/x-wav":
+		return "audio/wav"
+	}
+
+	return t
+}
+
+// genericMimeType reports whether a sniffed type is too broad to compare with the extension
+// e.g. TIFFs are not sniffed and office documents are zip files
+func genericMimeType(t string) bool {
+	switch t {
+	case "", "application/octet-stream", "text/plain", "application/zip":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSite struct {
+	models map[int]int
+	terms  map[int]string
+	calls  int
+}
+
+func (s *testSite) Node(nid int) (*api.IslandoraObject, error) {
+	s.calls++
+	model, ok := s.models[nid]
+	if !ok {
+		return nil, fmt.Errorf("bad status code: %w", islandora.ErrNotFound)
+	}
+
+	return &api.IslandoraObject{
+		FieldModel: &islandoraModel.EntityReferenceField{{TargetId: model}},
+	}, nil
+}
+
+func (s *testSite) TermName(tid int) (string, error) {
+	return s.terms[tid], nil
+}
+
+func TestPreflightRecords(t *testing.T) {
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.pdf"), []byte("%PDF-1.7"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.png"), png, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fake.pdf"), png, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scan.tif"), []byte("II*\x00"), 0644))
+
+	site := &testSite{
+		models: map[int]int{1: 10, 2: 11},
+		terms:  map[int]string{10: "Collection", 11: "Image"},
+	}
+	report, err := PreflightRecords([][]string{
+		{"Upload ID", "Node ID", "Parent Collection", "Title", "Object Model", "File Path", "File Format (MIME Type)"},
+		{"a", "", "1", "PDF", "Digital Document", "foo.pdf", ""},
+		{"b", "", "1|2", "PNG", "Digital Document", "foo.png", ""},
+		{"c", "", "3", "Fake", "Digital Document", "fake.pdf", ""},
+		{"d", "", "1", "TIFF", "Image", "scan.tif", "image/jp2"},
+		{"e", "", "1", "Again", "Image", "foo.png", ""},
+		{"", "2", "", "", "", "", ""},
+		{"", "4", "", "", "", "", ""},
+	}, PreflightOptions{
+		Options:          Options{FilesDir: dir},
+		Site:             site,
+		DerivativeFactor: 2,
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+
+	assert.Empty(t, errorsFor(&report.ValidationReport, 2))
+	assert.Equal(t, map[string]string{
+		"Parent Collection": `node 2 has model "Image", expected "Collection"`,
+		"File Path":         "file is image/png which is not expected for a Digital Document",
+	}, errorsFor(&report.ValidationReport, 3))
+	assert.Equal(t, map[string]string{
+		"Parent Collection": "node 3 does not exist",
+		"File Path":         "file content is image/png but the extension is for application/pdf",
+	}, errorsFor(&report.ValidationReport, 4))
+	assert.Equal(t, map[string]string{
+		"File Path": `file is image/tiff, expected "image/jp2"`,
+	}, errorsFor(&report.ValidationReport, 5))
+	assert.Empty(t, errorsFor(&report.ValidationReport, 6))
+	assert.Empty(t, errorsFor(&report.ValidationReport, 7))
+	assert.Equal(t, map[string]string{"Node ID": "node 4 does not exist"}, errorsFor(&report.ValidationReport, 8))
+
+	// nodes are only looked up once
+	assert.Equal(t, 4, site.calls)
+	// foo.png is referenced twice but only counted once
+	assert.Equal(t, 4, report.Files)
+	assert.Equal(t, int64(8+12+12+4), report.FileBytes)
+	assert.Equal(t, 2*report.FileBytes, report.EstimatedBytes)
+}
@@ -0,0 +1,349 @@
+// Package validate checks ingest spreadsheets before they are
+// transformed and handed to Islandora Workbench
+package validate
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/edtf"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+)
+
+// ensure that we've conformed to the `api.Validator` with a compile-time check
+var _ api.Validator = (*Validator)(nil)
+
+type Options struct {
+	Mapping *workbench.Mapping
+	// FilesDir is the directory relative file paths are resolved against
+	FilesDir string
+	// SkipFiles disables checking that files exist
+	// e.g. when validating on a server that does not have the files
+	SkipFiles bool
+	// Vocabularies enables checking term backed columns against the site's terms
+	Vocabularies Vocabularies
+	// FieldVocabularies are the vocabularies each entity reference field targets
+	// e.g. field_genre => genre
+	FieldVocabularies map[string][]string
+}
+
+type Validator struct {
+	opts Options
+}
+
+func New(opts Options) *Validator {
+	if opts.Mapping == nil {
+		opts.Mapping = workbench.DefaultMapping()
+	}
+
+	return &Validator{opts: opts}
+}
+
+// Validate checks a Google Sheets template CSV or a workbench CSV
+func (v *Validator) Validate(r io.Reader) (*api.ValidationReport, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return v.ValidateRecords(records)
+}
+
+// ValidateRecords checks CSV rows (including the header)
+// an error is only returned if the CSV can not be validated at all
+func (v *Validator) ValidateRecords(records [][]string) (*api.ValidationReport, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV has no header")
+	}
+
+	report := &api.ValidationReport{
+		Valid:  true,
+		Rows:   len(records) - 1,
+		Errors: []api.ValidationError{},
+	}
+
+	columns := v.columns(records[0])
+	for i, column := range columns {
+		if column.Field == "" {
+			report.Add(1, strings.TrimSpace(records[0][i]), "", "unknown column")
+		}
+	}
+
+	v.checkRequired(report, columns, records)
+	v.checkVocabularies(report, columns)
+	failedVocabularies := map[string]bool{}
+	for r, record := range records[1:] {
+		for i, column := range columns {
+			if i >= len(record) || column.Field == "" || column.Ignored() {
+				continue
+			}
+			value := v.checkCell(report, r+2, column, record[i])
+			v.checkTerms(report, r+2, column, value, failedVocabularies)
+		}
+	}
+	checkHierarchy(report, columns, records)
+
+	return report, nil
+}
+
+// columns resolves the CSV header, unknown columns are left empty
+func (v *Validator) columns(header []string) []workbench.Column {
+	columns := make([]workbench.Column, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == workbench.OverflowColumn {
+			columns[i] = workbench.Column{Name: name, Field: "-"}
+			continue
+		}
+		column, ok := v.opts.Mapping.Resolve(name)
+		if ok {
+			columns[i] = column
+		}
+	}
+
+	return columns
+}
+
+// checkRequired makes sure each new node has a value for every required field
+func (v *Validator) checkRequired(report *api.ValidationReport, columns []workbench.Column, records [][]string) {
+	required := []workbench.Column{}
+	for _, column := range v.opts.Mapping.Columns {
+		if column.Required {
+			required = append(required, column)
+		}
+	}
+
+	nodeId := fieldIndexes(columns, "node_id")
+	for r, record := range records[1:] {
+		if isEmpty(record) || firstValue(record, nodeId) != "" {
+			continue
+		}
+		for _, req := range required {
+			field := req.Tag().Field
+			indexes := fieldIndexes(columns, field)
+			if firstValue(record, indexes) != "" {
+				continue
+			}
+			// a default fills the value in
+			if len(indexes) > 0 && columns[indexes[0]].Default != "" {
+				continue
+			}
+			name := req.Name
+			if len(indexes) > 0 {
+				name = columns[indexes[0]].Name
+			}
+			report.Add(r+2, name, "", "required")
+		}
+	}
+}
+
+// checkCell validates a cell's values, returning the value after the column's transforms
+func (v *Validator) checkCell(report *api.ValidationReport, row int, column workbench.Column, cell string) string {
+	if strings.TrimSpace(cell) == "" {
+		return ""
+	}
+
+	value, err := column.Apply(cell)
+	if err != nil {
+		report.Add(row, column.Name, cell, strings.TrimPrefix(err.Error(), fmt.Sprintf("column %q: ", column.Name)))
+		return ""
+	}
+
+	checks := slices.Clone(column.Validate)
+	tag := column.Tag()
+	// attribute tagged columns hold the value of a typed text or part detail,
+	// not the whole field
+	if tag.Property == "" {
+		fieldType, _ := workbench.FieldType(tag.Field)
+		switch fieldType {
+		case reflect.TypeOf(islandoraModel.EdtfField{}):
+			checks = append(checks, "edtf")
+		case reflect.TypeOf(islandoraModel.EmailField{}):
+			checks = append(checks, "email")
+		case reflect.TypeOf(islandoraModel.IntField{}):
+			checks = append(checks, "integer")
+		}
+	}
+
+	for _, val := range strings.Split(value, "|") {
+		val = strings.TrimSpace(val)
+		if val == "" {
+			continue
+		}
+		for _, check := range checks {
+			msg := checkValue(check, val)
+			if msg != "" {
+				report.Add(row, column.Name, val, msg)
+			}
+		}
+	}
+
+	if slices.Contains(checks, "file") && !v.opts.SkipFiles {
+		for _, path := range strings.Split(value, "|") {
+			msg := v.checkFile(strings.TrimSpace(path))
+			if msg != "" {
+				report.Add(row, column.Name, path, msg)
+			}
+		}
+	}
+
+	return value
+}
+
+func checkValue(check, value string) string {
+	switch check {
+	case "edtf":
+		err := edtf.Validate(value)
+		if err != nil {
+			return fmt.Sprintf("invalid EDTF: %v", err)
+		}
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return "invalid email address"
+		}
+	case "integer":
+		_, err := strconv.Atoi(value)
+		if err != nil {
+			return "must be a whole number"
+		}
+	}
+
+	return ""
+}
+
+func (v *Validator) checkFile(path string) string {
+	path, ok := v.localPath(path)
+	if !ok {
+		return ""
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "file does not exist"
+	}
+	if info.IsDir() {
+		return "file is a directory"
+	}
+
+	return ""
+}
+
+// localPath resolves a file column's path against FilesDir
+// returning false for URLs workbench downloads itself
+func (v *Validator) localPath(path string) (string, bool) {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return "", false
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(v.opts.FilesDir, path)
+	}
+
+	return path, true
+}
+
+// checkHierarchy makes sure Page/Item Parent ID references an Upload ID
+// that comes before it, and that children have a unique Child Sort Order
+func checkHierarchy(report *api.ValidationReport, columns []workbench.Column, records [][]string) {
+	idIndexes := fieldIndexes(columns, "id")
+	parentIndexes := fieldIndexes(columns, "parent_id")
+	weightIndexes := fieldIndexes(columns, "field_weight")
+
+	ids := map[string]int{}
+	for r, record := range records[1:] {
+		id := firstValue(record, idIndexes)
+		if id == "" {
+			continue
+		}
+		if first, ok := ids[id]; ok {
+			report.Add(r+2, columns[idIndexes[0]].Name, id, fmt.Sprintf("duplicates the ID on row %d", first))
+			continue
+		}
+		ids[id] = r + 2
+	}
+
+	if len(parentIndexes) == 0 {
+		return
+	}
+	parentColumn := columns[parentIndexes[0]].Name
+	weightColumn := "field_weight"
+	if len(weightIndexes) > 0 {
+		weightColumn = columns[weightIndexes[0]].Name
+	}
+
+	siblings := map[string]map[string]int{}
+	for r, record := range records[1:] {
+		row := r + 2
+		parent := firstValue(record, parentIndexes)
+		if parent == "" {
+			continue
+		}
+
+		parentRow, ok := ids[parent]
+		switch {
+		case parent == firstValue(record, idIndexes):
+			report.Add(row, parentColumn, parent, "an item can not be its own parent")
+		case !ok:
+			report.Add(row, parentColumn, parent, "does not match an ID in the spreadsheet")
+		case parentRow > row:
+			report.Add(row, parentColumn, parent, fmt.Sprintf("the parent on row %d must come before its children", parentRow))
+		}
+
+		weight := firstValue(record, weightIndexes)
+		if weight == "" {
+			report.Add(row, weightColumn, "", fmt.Sprintf("required when %s is set", parentColumn))
+			continue
+		}
+		if siblings[parent] == nil {
+			siblings[parent] = map[string]int{}
+		}
+		if sibling, ok := siblings[parent][weight]; ok {
+			report.Add(row, weightColumn, weight, fmt.Sprintf("duplicates the sort order on row %d", sibling))
+			continue
+		}
+		siblings[parent][weight] = row
+	}
+}
+
+// fieldIndexes returns the indexes of the columns populating field
+func fieldIndexes(columns []workbench.Column, field string) []int {
+	indexes := []int{}
+	for i, column := range columns {
+		if column.Field != "" && !column.Ignored() && column.Tag().Field == field {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+func firstValue(record []string, indexes []int) string {
+	for _, i := range indexes {
+		if i < len(record) && strings.TrimSpace(record[i]) != "" {
+			return strings.TrimSpace(record[i])
+		}
+	}
+
+	return ""
+}
+
+func isEmpty(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
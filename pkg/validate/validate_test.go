@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func errorsFor(report *api.ValidationReport, row int) map[string]string {
+	errs := map[string]string{}
+	for _, e := range report.ByRow()[row] {
+		errs[e.Column] = e.Message
+	}
+
+	return errs
+}
+
+func TestValidateRecords(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.pdf"), []byte("%PDF"), 0644))
+
+	v := New(Options{FilesDir: dir})
+	report, err := v.ValidateRecords([][]string{
+		{"Upload ID", "Page/Item Parent ID", "Child Sort Order", "Node ID", "Title", "Object Model", "Make Public (Y/N)", "Creation Date", "File Path", "Bogus"},
+		{"1", "", "", "", "Book", "Paged Content", "Y", "2020-01", "foo.pdf", ""},
+		{"2", "1", "1", "", "Page 1", "Page", "N", "2020-13", "missing.pdf", ""},
+		{"3", "1", "1", "", "Page 2", "Page", "maybe", "", "", ""},
+		{"4", "5", "", "", "", "Page", "", "", "", ""},
+		{"5", "", "", "", "Later", "Page", "", "", "https://example.com/foo.pdf", ""},
+		{"1", "", "", "", "Dupe", "Image", "", "", "", ""},
+		{"", "", "", "10", "", "", "", "1985-04-12", "", ""},
+		{"", "", "", "", "", "", "", "", "", ""},
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, 8, report.Rows)
+
+	assert.Equal(t, map[string]string{"Bogus": "unknown column"}, errorsFor(report, 1))
+	assert.Empty(t, errorsFor(report, 2))
+	assert.Contains(t, errorsFor(report, 3)["Creation Date"], "invalid EDTF")
+	assert.Equal(t, "file does not exist", errorsFor(report, 3)["File Path"])
+	assert.Equal(t, `"maybe" is not Y or N`, errorsFor(report, 4)["Make Public (Y/N)"])
+	assert.Equal(t, "duplicates the sort order on row 3", errorsFor(report, 4)["Child Sort Order"])
+	assert.Equal(t, map[string]string{
+		"Title":               "required",
+		"Page/Item Parent ID": "the parent on row 6 must come before its children",
+		"Child Sort Order":    "required when Page/Item Parent ID is set",
+	}, errorsFor(report, 5))
+	assert.Empty(t, errorsFor(report, 6))
+	assert.Equal(t, "duplicates the ID on row 2", errorsFor(report, 7)["Upload ID"])
+	// updates to existing nodes don't need required fields
+	assert.Empty(t, errorsFor(report, 8))
+	assert.Empty(t, errorsFor(report, 9))
+}
+
+func TestValidateWorkbenchCsv(t *testing.T) {
+	v := New(Options{SkipFiles: true})
+	report, err := v.ValidateRecords([][]string{
+		{"id", "parent_id", "field_weight", "title", "field_model", "field_edtf_date_issued", "field_creator_email", "file"},
+		{"1", "", "", "Book", "Paged Content", "2020", "someone@example.com", "missing.pdf"},
+		{"2", "3", "one", "", "Page", "", "not an email", ""},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, errorsFor(report, 2))
+	assert.Equal(t, map[string]string{
+		"title":               "required",
+		"parent_id":           "does not match an ID in the spreadsheet",
+		"field_weight":        "must be a whole number",
+		"field_creator_email": "invalid email address",
+	}, errorsFor(report, 3))
+}
+
+func TestPostUpload(t *testing.T) {
+	s := api.NewValidationServer(New(Options{SkipFiles: true}))
+
+	w := httptest.NewRecorder()
+	s.PostUpload(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("Upload ID,Title,Object Model\n1,,Image\n")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report api.ValidationReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.False(t, report.Valid)
+	assert.Equal(t, []api.ValidationError{{Row: 2, Column: "Title", Message: "required"}}, report.Errors)
+
+	w = httptest.NewRecorder()
+	api.NewServer().PostUpload(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("")))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,274 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+	"github.com/lehigh-university-libraries/go-islandora/pkg/islandora"
+	"github.com/lehigh-university-libraries/go-islandora/workbench"
+)
+
+// Vocabularies looks up the term names in a vocabulary
+type Vocabularies interface {
+	Terms(vid string) ([]string, error)
+}
+
+// VocabularySnapshot is a local copy of term names keyed by vocabulary ID
+type VocabularySnapshot map[string][]string
+
+func (s VocabularySnapshot) Terms(vid string) ([]string, error) {
+	terms, ok := s[vid]
+	if !ok {
+		return nil, fmt.Errorf("vocabulary %s is not in the snapshot", vid)
+	}
+
+	return terms, nil
+}
+
+func LoadVocabularySnapshot(path string) (VocabularySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s VocabularySnapshot
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vocabulary snapshot %s: %v", path, err)
+	}
+
+	return s, nil
+}
+
+// SiteVocabularies fetches vocabularies from an Islandora site's JSON:API
+type SiteVocabularies struct {
+	BaseUrl string
+
+	mu    sync.Mutex
+	terms map[string][]string
+}
+
+func NewSiteVocabularies(baseUrl string) *SiteVocabularies {
+	return &SiteVocabularies{
+		BaseUrl: baseUrl,
+		terms:   map[string][]string{},
+	}
+}
+
+func (s *SiteVocabularies) Terms(vid string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if terms, ok := s.terms[vid]; ok {
+		return terms, nil
+	}
+
+	vocabulary, err := islandora.FetchVocabulary(s.BaseUrl, vid)
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]string, len(vocabulary))
+	for i, term := range vocabulary {
+		terms[i] = term.Name
+	}
+	s.terms[vid] = terms
+
+	return terms, nil
+}
+
+// Snapshot copies vocabularies into a snapshot that can be saved for offline validation
+func Snapshot(v Vocabularies, vids []string) (VocabularySnapshot, error) {
+	s := VocabularySnapshot{}
+	for _, vid := range vids {
+		terms, err := v.Terms(vid)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load vocabulary %s: %v", vid, err)
+		}
+		s[vid] = terms
+	}
+
+	return s, nil
+}
+
+// vocabulariesFor returns the vocabularies a column's terms belong to
+func (v *Validator) vocabulariesFor(column workbench.Column) []string {
+	tag := column.Tag()
+	switch {
+	case tag.Property == "vid":
+		return []string{tag.Value}
+	case len(column.Vocabularies) > 0:
+		return column.Vocabularies
+	case tag.Property == "":
+		return v.opts.FieldVocabularies[tag.Field]
+	}
+
+	return nil
+}
+
+// checkVocabularies warns about term columns that can't be checked
+// because their vocabularies are unknown
+func (v *Validator) checkVocabularies(report *api.ValidationReport, columns []workbench.Column) {
+	if v.opts.Vocabularies == nil {
+		return
+	}
+	for _, column := range columns {
+		if column.Field == "" || column.Ignored() || len(v.vocabulariesFor(column)) > 0 {
+			continue
+		}
+		field := column.Tag().Field
+		fieldType, _ := workbench.FieldType(field)
+		if fieldType != reflect.TypeOf(islandoraModel.EntityReferenceField{}) || slices.Contains(nodeReferences, field) {
+			continue
+		}
+		report.Warn(1, column.Name, "", fmt.Sprintf("terms are not checked, no vocabulary is known for %s", field))
+	}
+}
+
+// nodeReferences are entity reference fields that reference nodes, not terms
+var nodeReferences = []string{"field_member_of"}
+
+// checkTerms reports values that are not terms in the column's vocabularies.
+// Values close to an existing term are likely typos and are errors,
+// other values are warnings since ingesting them creates a new term.
+func (v *Validator) checkTerms(report *api.ValidationReport, row int, column workbench.Column, value string, failed map[string]bool) {
+	vids := v.vocabulariesFor(column)
+	if v.opts.Vocabularies == nil || len(vids) == 0 {
+		return
+	}
+
+	for _, name := range strings.Split(value, "|") {
+		name = strings.TrimSpace(name)
+		if name == "" || isInteger(name) {
+			continue
+		}
+
+		candidates := vids
+		// workbench's vocabulary_id:term name syntax
+		if vid, term, ok := strings.Cut(name, ":"); ok && slices.Contains(vids, vid) {
+			candidates = []string{vid}
+			name = term
+		}
+
+		terms := []string{}
+		for _, vid := range candidates {
+			t, err := v.opts.Vocabularies.Terms(vid)
+			if err != nil {
+				if !failed[vid] {
+					report.Add(row, column.Name, "", fmt.Sprintf("unable to load vocabulary %s: %v", vid, err))
+					failed[vid] = true
+				}
+				continue
+			}
+			terms = append(terms, t...)
+		}
+
+		key := normalizeTerm(name)
+		if slices.ContainsFunc(terms, func(t string) bool { return normalizeTerm(t) == key }) {
+			continue
+		}
+
+		suggestions := nearMatches(name, terms, 3)
+		vocabularies := strings.Join(candidates, ", ")
+		if len(suggestions) > 0 {
+			report.Add(row, column.Name, name, fmt.Sprintf("not a term in %s, did you mean %s?", vocabularies, quoteJoin(suggestions)))
+			continue
+		}
+		report.Warn(row, column.Name, name, fmt.Sprintf("would create a new term in %s", vocabularies))
+	}
+}
+
+func normalizeTerm(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// nearMatches returns up to limit terms within a small edit distance of name, closest first
+func nearMatches(name string, terms []string, limit int) []string {
+	key := normalizeTerm(name)
+	max := len([]rune(key)) / 4
+	if max < 1 {
+		max = 1
+	}
+	if max > 4 {
+		max = 4
+	}
+
+	type match struct {
+		term     string
+		distance int
+	}
+	matches := []match{}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		d := levenshtein(key, normalizeTerm(term))
+		if d <= max {
+			matches = append(matches, match{term, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance == matches[j].distance {
+			return matches[i].term < matches[j].term
+		}
+		return matches[i].distance < matches[j].distance
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(matches) && i < limit; i++ {
+		suggestions = append(suggestions, matches[i].term)
+	}
+
+	return suggestions
+}
+
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
+
+func isInteger(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+
+	return strings.Join(quoted, " or ")
+}
@@ -0,0 +1,81 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTerms(t *testing.T) {
+	v := New(Options{
+		SkipFiles: true,
+		Vocabularies: VocabularySnapshot{
+			"genre":            {"Photographs", "Maps", "Theses"},
+			"geographic_naf":   {"Bethlehem (Pa.)"},
+			"geographic_local": {"South Side"},
+			"islandora_models": {"Image"},
+			"resource_types":   {"Still Image"},
+		},
+		FieldVocabularies: map[string][]string{
+			"field_genre": {"genre"},
+		},
+	})
+
+	report, err := v.ValidateRecords([][]string{
+		{"Upload ID", "Title", "Object Model", "Genre (Getty AAT)", "Subject Geographic (LCNAF)", "Resource Type"},
+		{"1", "Foo", "Image", "photographs|Maps", "Bethlehem (Pa.)", "Still Image"},
+		{"2", "Bar", "Image", "Photgraphs|12", "Bethlehem", ""},
+		{"3", "Baz", "Image", "Zines", "South Side", ""},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, errorsFor(report, 2))
+	assert.Equal(t, map[string]string{
+		"Genre (Getty AAT)": `not a term in genre, did you mean "Photographs"?`,
+	}, errorsFor(report, 3))
+	assert.Empty(t, errorsFor(report, 4))
+
+	warnings := map[string]string{}
+	for _, w := range report.Warnings {
+		warnings[w.Value] = w.Message
+	}
+	assert.Equal(t, map[string]string{
+		"Bethlehem":  "would create a new term in geographic_naf",
+		"Zines":      "would create a new term in genre",
+		"South Side": "would create a new term in geographic_naf",
+	}, warnings)
+
+	// a vocabulary missing from the snapshot is reported once
+	v = New(Options{SkipFiles: true, Vocabularies: VocabularySnapshot{}})
+	report, err = v.ValidateRecords([][]string{
+		{"Node ID", "Subject Geographic (Local)"},
+		{"1", "A"},
+		{"2", "B"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0].Message, "geographic_local is not in the snapshot")
+
+	// term columns without a known vocabulary are reported instead of skipped silently
+	report, err = v.ValidateRecords([][]string{
+		{"Node ID", "Subject Name (LCNAF)", "Parent Collection"},
+		{"1", "Doe, Jane", "2"},
+	})
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, "Subject Name (LCNAF)", report.Warnings[0].Column)
+	assert.Equal(t, "terms are not checked, no vocabulary is known for field_subjects_name", report.Warnings[0].Message)
+}
+
+func TestNearMatches(t *testing.T) {
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 0, levenshtein("", ""))
+	assert.Equal(t, 2, levenshtein("Zürich", "Zurch"))
+
+	terms := []string{"Photographs", "Photograph", "Phonograph records", "Maps"}
+	assert.Equal(t, []string{"Photograph", "Photographs"}, nearMatches("Photgraph", terms, 3))
+	assert.Equal(t, []string{"Maps"}, nearMatches("map", terms, 3))
+	assert.Empty(t, nearMatches("Zines", terms, 3))
+}
@@ -0,0 +1,8 @@
+// Package templates embeds the templates used to generate Open API specs
+// so the released binary can generate code outside of this repository
+package templates
+
+import "embed"
+
+//go:embed *.tmpl
+var FS embed.FS
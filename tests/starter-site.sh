@@ -6,13 +6,24 @@ if [ ! -d ./islandora-starter-site ]; then
   git clone https://github.com/Islandora-Devops/islandora-starter-site
 fi
 
+# check the committed structs before they are regenerated below
+./go-islandora generate check \
+  --node-cex-yaml=./islandora-starter-site/config/sync/node.type.islandora_object.yml \
+  --spec=api/islandora.gen.go || (echo "Failure Maybe starter site updated its data model?" && exit 1)
+
 ./go-islandora generate node-structs \
   --node-cex-yaml=./islandora-starter-site/config/sync/node.type.islandora_object.yml \
   --output=api.yaml
 
-# TODO: if/once generation of YAML is deterministric add this check back in
-# diff api.yaml fixtures/islandora_object.yaml || (echo "Failure Maybe starter site updated its data model?" && exit 1)
-
 ls -l api/islandora.gen.go
 
+# generation must be deterministic for drift detection to work
+./go-islandora generate node-structs \
+  --node-cex-yaml=./islandora-starter-site/config/sync/node.type.islandora_object.yml \
+  --output=api-rerun.yaml \
+  --out-dir=./api-rerun
+diff api.yaml api-rerun.yaml || (echo "Failure: generating the Open API spec is not deterministic" && exit 1)
+diff api/islandora.gen.go api-rerun/islandora.gen.go || (echo "Failure: generating the Go structs is not deterministic" && exit 1)
+rm -rf api-rerun.yaml api-rerun
+
 echo "Generated Open API spec matches expected output 🚀"
@@ -1,28 +1,42 @@
 package workbench
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"log/slog"
 	"net/http"
 )
 
 // ensure that we've conformed to the `ServerInterface` with a compile-time check
 var _ ServerInterface = (*Server)(nil)
 
-type Server struct{}
+type Server struct {
+	Mapping *Mapping
+}
 
 func NewServer() Server {
-	return Server{}
+	return Server{
+		Mapping: DefaultMapping(),
+	}
 }
 
 // (POST /upload)
-func (Server) PostUpload(w http.ResponseWriter, r *http.Request) {
-
-	// TODO: transform the vanilla CSV into workbench CSV
-	title := "foo"
-	resp := SheetsCsv{
-		Title: &title,
+// transforms the Google Sheets CSV in the request body into a workbench CSV
+func (s Server) PostUpload(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	err := Transform(r.Body, &buf, s.Mapping)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		slog.Error("Unable to transform CSV", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
+	w.Header().Set("Content-Type", "text/csv")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(resp)
+	_, _ = w.Write(buf.Bytes())
 }
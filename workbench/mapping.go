@@ -0,0 +1,243 @@
+package workbench
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed mapping.yaml
+var defaultMapping []byte
+
+// Mapping describes how the columns of a Google Sheets template
+// map to Islandora Workbench CSV columns
+type Mapping struct {
+	Columns []Column `yaml:"columns"`
+}
+
+type Column struct {
+	// Name is the column header in the spreadsheet
+	Name string `yaml:"name"`
+	// Field is the workbench column the spreadsheet column populates
+	// e.g. field_note.attr0=box
+	Field     string   `yaml:"field"`
+	Transform []string `yaml:"transform,omitempty"`
+	Default   string   `yaml:"default,omitempty"`
+	// Required columns must have a value when creating new nodes
+	Required bool `yaml:"required,omitempty"`
+	// Validate lists additional checks for the column's values
+	// beyond those implied by the field's type e.g. file
+	Validate []string `yaml:"validate,omitempty"`
+	// Vocabularies the column's terms belong to
+	// when they can not be determined from the field
+	Vocabularies []string `yaml:"vocabularies,omitempty"`
+}
+
+// Checks that can be listed in a column's validate
+var Checks = []string{"edtf", "email", "file", "integer"}
+
+// Tag is a parsed Column.Field
+// e.g. field_geographic_subject.vid=geographic_naf is
+// Tag{Field: "field_geographic_subject", Property: "vid", Value: "geographic_naf"}
+type Tag struct {
+	Field    string
+	Property string
+	Value    string
+}
+
+type transformFunc func(value, arg string) (string, error)
+
+var transforms = map[string]transformFunc{
+	"trim": func(value, _ string) (string, error) {
+		return strings.TrimSpace(value), nil
+	},
+	"lowercase": func(value, _ string) (string, error) {
+		return strings.ToLower(value), nil
+	},
+	"uppercase": func(value, _ string) (string, error) {
+		return strings.ToUpper(value), nil
+	},
+	"yes-no": func(value, _ string) (string, error) {
+		switch strings.ToLower(value) {
+		case "":
+			return "", nil
+		case "y", "yes", "true", "1":
+			return "1", nil
+		case "n", "no", "false", "0":
+			return "0", nil
+		}
+		return "", fmt.Errorf("%q is not Y or N", value)
+	},
+	"split": func(value, arg string) (string, error) {
+		if arg == "" {
+			return "", fmt.Errorf("split requires a delimiter e.g. split:;")
+		}
+		values := []string{}
+		for _, v := range strings.Split(value, arg) {
+			v = strings.TrimSpace(v)
+			if v != "" {
+				values = append(values, v)
+			}
+		}
+		return strings.Join(values, "|"), nil
+	},
+}
+
+// DefaultMapping returns the column mapping for Lehigh's spreadsheet template
+func DefaultMapping() *Mapping {
+	m, err := ParseMapping(defaultMapping)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// LoadMapping reads a column mapping from a YAML file
+// or returns the default mapping if path is empty
+func LoadMapping(path string) (*Mapping, error) {
+	if path == "" {
+		return DefaultMapping(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMapping(data)
+}
+
+func ParseMapping(data []byte) (*Mapping, error) {
+	var m Mapping
+	err := yaml.UnmarshalStrict(data, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.Columns) == 0 {
+		return nil, fmt.Errorf("mapping has no columns")
+	}
+
+	names := map[string]bool{}
+	for i, column := range m.Columns {
+		if column.Name == "" {
+			return nil, fmt.Errorf("column %d has no name", i+1)
+		}
+		if names[column.Name] {
+			return nil, fmt.Errorf("column %q is defined more than once", column.Name)
+		}
+		names[column.Name] = true
+
+		if column.Field == "" {
+			return nil, fmt.Errorf("column %q has no field", column.Name)
+		}
+		for _, t := range column.Transform {
+			name, _, _ := strings.Cut(t, ":")
+			if _, ok := transforms[name]; !ok {
+				return nil, fmt.Errorf("column %q has unknown transform %q", column.Name, t)
+			}
+		}
+		for _, check := range column.Validate {
+			if !slices.Contains(Checks, check) {
+				return nil, fmt.Errorf("column %q has unknown validate %q", column.Name, check)
+			}
+		}
+	}
+
+	return &m, nil
+}
+
+// Column returns the column with the given spreadsheet header
+func (m *Mapping) Column(name string) (Column, bool) {
+	for _, column := range m.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// Resolve returns the column for a CSV header which is either
+// a spreadsheet column or, for workbench CSVs, a workbench field
+func (m *Mapping) Resolve(header string) (Column, bool) {
+	if column, ok := m.Column(header); ok {
+		return column, true
+	}
+
+	for _, column := range m.Columns {
+		tag := column.Tag()
+		if !column.Ignored() && tag.Field == header && tag.Property == "" {
+			return Column{
+				Name:         header,
+				Field:        header,
+				Required:     column.Required,
+				Validate:     column.Validate,
+				Vocabularies: column.Vocabularies,
+			}, true
+		}
+	}
+	if _, ok := FieldType(header); ok {
+		return Column{Name: header, Field: header}, true
+	}
+
+	return Column{}, false
+}
+
+// Ignored reports whether the column should be left out of workbench CSVs
+func (c Column) Ignored() bool {
+	return c.Field == "-"
+}
+
+func (c Column) Tag() Tag {
+	return ParseTag(c.Field)
+}
+
+// Apply fills in the column default and runs the column's transforms on a cell value
+func (c Column) Apply(value string) (string, error) {
+	if value == "" {
+		value = c.Default
+	}
+
+	for _, t := range c.Transform {
+		name, arg, _ := strings.Cut(t, ":")
+		transform, ok := transforms[name]
+		if !ok {
+			return "", fmt.Errorf("unknown transform %q", t)
+		}
+
+		var err error
+		value, err = transform(value, arg)
+		if err != nil {
+			return "", fmt.Errorf("column %q: %v", c.Name, err)
+		}
+	}
+
+	return value, nil
+}
+
+func ParseTag(tag string) Tag {
+	field, property, _ := strings.Cut(tag, ".")
+	t := Tag{
+		Field: field,
+	}
+	t.Property, t.Value, _ = strings.Cut(property, "=")
+
+	return t
+}
+
+func (t Tag) String() string {
+	if t.Property == "" {
+		return t.Field
+	}
+	if t.Value == "" {
+		return t.Field + "." + t.Property
+	}
+
+	return t.Field + "." + t.Property + "=" + t.Value
+}
@@ -0,0 +1,100 @@
+package workbench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMapping(t *testing.T) {
+	m := DefaultMapping()
+
+	column, ok := m.Column("Archival Box")
+	require.True(t, ok)
+	assert.Equal(t, Tag{Field: "field_note", Property: "attr0", Value: "box"}, column.Tag())
+
+	column, ok = m.Column("Human Name")
+	require.True(t, ok)
+	assert.True(t, column.Ignored())
+
+	_, ok = m.Column("Nope")
+	assert.False(t, ok)
+}
+
+func TestResolve(t *testing.T) {
+	m := DefaultMapping()
+
+	column, ok := m.Resolve("File Path")
+	require.True(t, ok)
+	assert.Equal(t, "file", column.Field)
+
+	// workbench columns inherit the settings of the spreadsheet column
+	column, ok = m.Resolve("file")
+	require.True(t, ok)
+	assert.Equal(t, []string{"file"}, column.Validate)
+	column, ok = m.Resolve("title")
+	require.True(t, ok)
+	assert.True(t, column.Required)
+
+	column, ok = m.Resolve("field_edtf_date_created")
+	require.True(t, ok)
+	assert.Equal(t, "field_edtf_date_created", column.Field)
+
+	_, ok = m.Resolve("field_nope")
+	assert.False(t, ok)
+}
+
+func TestParseTag(t *testing.T) {
+	for _, tag := range []string{
+		"title",
+		"field_geographic_subject.vid=geographic_naf",
+		"field_related_item.title",
+		"field_part_detail.attr0=volume",
+	} {
+		assert.Equal(t, tag, ParseTag(tag).String())
+	}
+	assert.Equal(t, Tag{Field: "field_related_item", Property: "title"}, ParseTag("field_related_item.title"))
+}
+
+func TestParseMapping(t *testing.T) {
+	m, err := ParseMapping([]byte(`
+columns:
+  - name: Keywords
+    field: field_keywords
+    transform: [trim, "split:;"]
+  - name: Public
+    field: published
+    transform: [yes-no]
+    default: "Y"
+`))
+	require.NoError(t, err)
+
+	keywords, _ := m.Column("Keywords")
+	value, err := keywords.Apply(" foo; bar ;;baz ")
+	require.NoError(t, err)
+	assert.Equal(t, "foo|bar|baz", value)
+
+	public, _ := m.Column("Public")
+	value, err = public.Apply("")
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+	value, err = public.Apply("no")
+	require.NoError(t, err)
+	assert.Equal(t, "0", value)
+	_, err = public.Apply("maybe")
+	assert.Error(t, err)
+
+	for _, invalid := range []string{
+		`columns: []`,
+		`columns: [{field: title}]`,
+		`columns: [{name: Title}]`,
+		`columns: [{name: Title, field: title}, {name: Title, field: field_full_title}]`,
+		`columns: [{name: Title, field: title, transform: [reverse]}]`,
+		`columns: [{name: Title, field: title, delimiter: ";"}]`,
+		`columns: [{name: Title, field: title, validate: [isbn]}]`,
+	} {
+		_, err := ParseMapping([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
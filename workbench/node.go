@@ -0,0 +1,134 @@
+package workbench
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+type csvMarshaler interface {
+	MarshalCSV() (string, error)
+}
+
+type NodeOptions struct {
+	// Labeler replaces taxonomy term IDs with term labels
+	// e.g. genre:Photographs instead of 123
+	Labeler Labeler
+}
+
+// NodeRecords flattens nodes into workbench CSV rows (including the header)
+// using the model's CSV marshalers.
+// Only fields populated on at least one node are included.
+func NodeRecords(nodes []*api.IslandoraObject, opts NodeOptions) ([][]string, error) {
+	t := reflect.TypeOf(api.IslandoraObject{})
+	rows := make([]map[string]string, len(nodes))
+	populated := map[string]bool{}
+	for n, node := range nodes {
+		rows[n] = map[string]string{}
+		v := reflect.ValueOf(node).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			if v.Field(i).IsNil() {
+				continue
+			}
+			column := workbenchColumn(t.Field(i))
+			value, err := marshalNodeField(column, v.Field(i).Interface(), opts)
+			if err != nil {
+				return nil, fmt.Errorf("node %d %s: %v", n, t.Field(i).Name, err)
+			}
+			if value == "" {
+				continue
+			}
+
+			rows[n][column] = value
+			populated[column] = true
+		}
+	}
+
+	header := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		column := workbenchColumn(t.Field(i))
+		if populated[column] {
+			header = append(header, column)
+		}
+	}
+
+	records := [][]string{header}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, column := range header {
+			record[i] = row[column]
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func marshalNodeField(column string, field any, opts NodeOptions) (string, error) {
+	if opts.Labeler != nil {
+		switch f := field.(type) {
+		case *islandoraModel.EntityReferenceField:
+			return labelReferences(column, *f, opts.Labeler)
+		case *islandoraModel.TypedRelationField:
+			return labelRelations(column, *f, opts.Labeler)
+		}
+	}
+
+	m, ok := field.(csvMarshaler)
+	if !ok {
+		m, ok = reflect.ValueOf(field).Elem().Interface().(csvMarshaler)
+	}
+	if !ok {
+		return "", nil
+	}
+
+	return m.MarshalCSV()
+}
+
+// labelReferences labels the taxonomy terms in an entity reference field
+// other entities e.g. the nodes in field_member_of keep their IDs
+func labelReferences(column string, field islandoraModel.EntityReferenceField, labeler Labeler) (string, error) {
+	values := make([]string, len(field))
+	for i, ref := range field {
+		values[i] = strconv.Itoa(ref.TargetId)
+		if ref.TargetType != "taxonomy_term" {
+			continue
+		}
+		label, err := labeler(column, ref.TargetId)
+		if err != nil {
+			return "", fmt.Errorf("unable to label %s %d: %w", column, ref.TargetId, err)
+		}
+		values[i] = label
+	}
+
+	return strings.Join(values, "|"), nil
+}
+
+// labelRelations writes typed relations the way workbench accepts them
+// e.g. relators:cre:person:Doe, Jane
+func labelRelations(column string, field islandoraModel.TypedRelationField, labeler Labeler) (string, error) {
+	values := make([]string, len(field))
+	for i, rel := range field {
+		label, err := labeler(column, rel.TargetId)
+		if err != nil {
+			return "", fmt.Errorf("unable to label %s %d: %w", column, rel.TargetId, err)
+		}
+		values[i] = rel.RelType + ":" + label
+	}
+
+	return strings.Join(values, "|"), nil
+}
+
+func workbenchColumn(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	// workbench identifies existing nodes by node_id
+	if name == "nid" {
+		return "node_id"
+	}
+
+	return name
+}
@@ -0,0 +1,294 @@
+package workbench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+// OverflowColumn holds the workbench values that could not be placed
+// in one of the spreadsheet's columns, as a JSON object keyed by field
+// so they survive a round trip back through Transform
+const OverflowColumn = "Unmapped Values (do not edit)"
+
+// Labeler returns the label to show for a term ID referenced by field
+// either as "label" or "vocabulary_id:label"
+type Labeler func(field string, id int) (string, error)
+
+type ReverseOptions struct {
+	// Labeler replaces entity reference IDs with term labels
+	Labeler Labeler
+	// KeepIDs are entity reference fields that should not be labeled
+	// e.g. field_member_of references nodes, not terms
+	KeepIDs []string
+}
+
+// ReverseTransform reads a workbench CSV and writes a CSV using the spreadsheet template's columns
+func ReverseTransform(r io.Reader, w io.Writer, mapping *Mapping, opts ReverseOptions) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	records, err = ReverseRecords(records, mapping, opts)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.WriteAll(records)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReverseRecords converts workbench rows (including the header) into spreadsheet template rows.
+// Typed text and part detail values are split back into their attribute tagged columns.
+func ReverseRecords(records [][]string, mapping *Mapping, opts ReverseOptions) ([][]string, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV has no header")
+	}
+
+	header := make([]string, 0, len(mapping.Columns)+1)
+	for _, column := range mapping.Columns {
+		header = append(header, column.Name)
+	}
+	header = append(header, OverflowColumn)
+
+	output := [][]string{header}
+	for r, record := range records[1:] {
+		row, err := reverseRow(records[0], record, mapping, opts)
+		if err != nil {
+			// the header is row 1
+			return nil, fmt.Errorf("row %d: %w", r+2, err)
+		}
+		output = append(output, row)
+	}
+
+	return output, nil
+}
+
+func reverseRow(header, record []string, mapping *Mapping, opts ReverseOptions) ([]string, error) {
+	cells := make([][]string, len(mapping.Columns))
+	overflow := map[string][]string{}
+
+	for i, field := range header {
+		if i >= len(record) || record[i] == "" {
+			continue
+		}
+		for _, value := range strings.Split(record[i], "|") {
+			placed, err := placeValue(field, value, mapping, cells, opts)
+			if err != nil {
+				return nil, err
+			}
+			if !placed {
+				overflow[field] = append(overflow[field], value)
+			}
+		}
+	}
+
+	row := make([]string, len(mapping.Columns)+1)
+	for i, column := range mapping.Columns {
+		row[i] = column.Unapply(strings.Join(cells[i], "|"))
+	}
+	if len(overflow) > 0 {
+		data, err := json.Marshal(overflow)
+		if err != nil {
+			return nil, err
+		}
+		row[len(row)-1] = string(data)
+	}
+
+	return row, nil
+}
+
+// placeValue puts a single workbench value into the spreadsheet column(s) it belongs in
+// reporting whether a column was found
+func placeValue(field, value string, mapping *Mapping, cells [][]string, opts ReverseOptions) (bool, error) {
+	plain := -1
+	for i, column := range mapping.Columns {
+		if !column.Ignored() && column.Field == field {
+			plain = i
+			break
+		}
+	}
+
+	if strings.HasPrefix(value, "{") {
+		var obj map[string]string
+		if json.Unmarshal([]byte(value), &obj) == nil {
+			if placeObject(field, obj, mapping, cells) {
+				return true, nil
+			}
+		}
+	} else {
+		var err error
+		value, err = labelValue(field, value, opts)
+		if err != nil {
+			return false, err
+		}
+
+		// workbench's vocabulary_id:term name syntax
+		if vid, term, ok := strings.Cut(value, ":"); ok && isMachineName(vid) {
+			for i, column := range mapping.Columns {
+				tag := column.Tag()
+				if tag.Field == field && tag.Property == "vid" && tag.Value == vid {
+					cells[i] = appendUnique(cells[i], term)
+					return true, nil
+				}
+			}
+			if plain != -1 && isTermReference(field) {
+				cells[plain] = appendUnique(cells[plain], term)
+				return true, nil
+			}
+		}
+	}
+
+	if plain == -1 {
+		return false, nil
+	}
+	cells[plain] = appendUnique(cells[plain], value)
+
+	return true, nil
+}
+
+// placeObject splits a JSON value into attribute tagged columns
+// only if every property in the value has a column
+func placeObject(field string, obj map[string]string, mapping *Mapping, cells [][]string) bool {
+	for k, v := range obj {
+		if v == "" {
+			delete(obj, k)
+		}
+	}
+
+	// typed text and part detail
+	attr0, value := obj["attr0"], obj["value"]
+	if fieldTypes[field] == reflect.TypeOf(islandoraModel.PartDetailField{}) {
+		attr0, value = obj["type"], obj["number"]
+	}
+	if attr0 != "" && len(obj) == 2 && value != "" {
+		for i, column := range mapping.Columns {
+			tag := column.Tag()
+			if tag.Field == field && tag.Property == "attr0" && tag.Value == attr0 {
+				cells[i] = appendUnique(cells[i], value)
+				return true
+			}
+		}
+		return false
+	}
+
+	// sub-properties e.g. field_related_item.title
+	placements := map[int]string{}
+	consumed := map[string]bool{}
+	for i, column := range mapping.Columns {
+		tag := column.Tag()
+		if tag.Field != field || tag.Property == "" || tag.Property == "attr0" || tag.Property == "vid" {
+			continue
+		}
+		if tag.Value == "" {
+			if v, ok := obj[tag.Property]; ok {
+				placements[i] = v
+				consumed[tag.Property] = true
+			}
+			continue
+		}
+		if obj[tag.Property] != tag.Value {
+			continue
+		}
+		key := strings.TrimSuffix(tag.Property, "_type")
+		if key == tag.Property {
+			key = "value"
+		}
+		if v, ok := obj[key]; ok {
+			placements[i] = v
+			consumed[tag.Property] = true
+			consumed[key] = true
+		}
+	}
+	if len(placements) == 0 || len(consumed) != len(obj) {
+		return false
+	}
+	for i, v := range placements {
+		cells[i] = appendUnique(cells[i], v)
+	}
+
+	return true
+}
+
+func labelValue(field, value string, opts ReverseOptions) (string, error) {
+	if opts.Labeler == nil || slices.Contains(opts.KeepIDs, field) {
+		return value, nil
+	}
+
+	// typed relations are rel:code:id e.g. relators:aut:123
+	prefix := ""
+	switch {
+	case isTermReference(field):
+	case fieldTypes[field] == reflect.TypeOf(islandoraModel.TypedRelationField{}):
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) != 3 {
+			return value, nil
+		}
+		prefix, value = parts[0]+":"+parts[1]+":", parts[2]
+	default:
+		return value, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return prefix + value, nil
+	}
+
+	label, err := opts.Labeler(field, id)
+	if err != nil {
+		return "", fmt.Errorf("unable to label %s %d: %w", field, id, err)
+	}
+
+	return prefix + label, nil
+}
+
+func isTermReference(field string) bool {
+	return fieldTypes[field] == reflect.TypeOf(islandoraModel.EntityReferenceField{})
+}
+
+// Unapply reverses the column's transforms so a workbench value reads
+// the way catalogers enter it in the spreadsheet
+func (c Column) Unapply(value string) string {
+	for i := len(c.Transform) - 1; i >= 0; i-- {
+		name, arg, _ := strings.Cut(c.Transform[i], ":")
+		switch name {
+		case "yes-no":
+			switch value {
+			case "1":
+				value = "Y"
+			case "0":
+				value = "N"
+			}
+		case "split":
+			value = strings.ReplaceAll(value, "|", strings.TrimSpace(arg)+" ")
+		}
+	}
+
+	return value
+}
+
+func isMachineName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
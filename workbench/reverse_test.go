@@ -0,0 +1,148 @@
+package workbench
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseRecords(t *testing.T) {
+	mapping := DefaultMapping()
+	records := [][]string{
+		{"node_id", "title", "published", "field_note", "field_part_detail", "field_genre", "field_member_of", "field_geographic_subject", "field_related_item", "field_local_thing"},
+		{
+			"10",
+			"Foo",
+			"1",
+			`{"attr0":"box","value":"2"}|{"attr0":"general","value":"hello"}`,
+			`{"type":"volume","number":"10"}`,
+			"5",
+			"7",
+			"6",
+			`{"identifier":"1234-5678","identifier_type":"issn","title":"Journal"}`,
+			"bar",
+		},
+	}
+	labels := map[int]string{
+		5: "genre:Photographs",
+		6: "geographic_naf:Bethlehem (Pa.)",
+	}
+	opts := ReverseOptions{
+		Labeler: func(field string, id int) (string, error) {
+			return labels[id], nil
+		},
+		KeepIDs: []string{"field_member_of"},
+	}
+
+	out, err := ReverseRecords(records, mapping, opts)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	row := map[string]string{}
+	for i, column := range out[0] {
+		row[column] = out[1][i]
+	}
+	assert.Equal(t, "10", row["Node ID"])
+	assert.Equal(t, "Y", row["Make Public (Y/N)"])
+	assert.Equal(t, "2", row["Archival Box"])
+	assert.Equal(t, "10", row["Volume Number"])
+	assert.Equal(t, "Photographs", row["Genre (Getty AAT)"])
+	assert.Equal(t, "7", row["Parent Collection"])
+	assert.Equal(t, "Bethlehem (Pa.)", row["Subject Geographic (LCNAF)"])
+	assert.Equal(t, "Journal", row["Source Publication Title"])
+	assert.Equal(t, "1234-5678", row["Source Publication L-ISSN"])
+
+	var overflow map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(row[OverflowColumn]), &overflow))
+	assert.Equal(t, map[string][]string{
+		"field_note":        {`{"attr0":"general","value":"hello"}`},
+		"field_local_thing": {"bar"},
+	}, overflow)
+
+	// nothing is lost going back to workbench
+	back, err := TransformRecords(out, mapping)
+	require.NoError(t, err)
+	values := map[string]string{}
+	for i, column := range back[0] {
+		values[column] = back[1][i]
+	}
+	assert.Equal(t, `{"attr0":"box","value":"2"}|{"attr0":"general","value":"hello"}`, values["field_note"])
+	assert.Equal(t, "bar", values["field_local_thing"])
+	assert.Equal(t, "geographic_naf:Bethlehem (Pa.)", values["field_geographic_subject"])
+	assert.Equal(t, "1", values["published"])
+}
+
+func TestNodeRecords(t *testing.T) {
+	var node api.IslandoraObject
+	err := json.Unmarshal([]byte(`{
+		"nid": [{"value": 1}],
+		"title": [{"value": "Foo"}],
+		"type": [{"target_id": "islandora_object", "target_type": "node_type"}],
+		"field_member_of": [{"target_id": 2}, {"target_id": 3}]
+	}`), &node)
+	require.NoError(t, err)
+
+	records, err := NodeRecords([]*api.IslandoraObject{&node, {}}, NodeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"field_member_of", "node_id", "title", "type"},
+		{"2|3", "1", "Foo", "islandora_object"},
+		{"", "", "", ""},
+	}, records)
+}
+
+func TestNodeRecordsLabels(t *testing.T) {
+	var node api.IslandoraObject
+	err := json.Unmarshal([]byte(`{
+		"field_member_of": [{"target_id": 2, "target_type": "node"}],
+		"field_genre": [{"target_id": 5, "target_type": "taxonomy_term"}],
+		"field_linked_agent": [{"target_id": 7, "rel_type": "relators:cre"}]
+	}`), &node)
+	require.NoError(t, err)
+
+	labels := map[int]string{5: "genre:Photographs", 7: "person:Doe, Jane"}
+	records, err := NodeRecords([]*api.IslandoraObject{&node}, NodeOptions{
+		Labeler: func(field string, id int) (string, error) {
+			return labels[id], nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"field_genre", "field_linked_agent", "field_member_of"},
+		{"genre:Photographs", "relators:cre:person:Doe, Jane", "2"},
+	}, records)
+}
+
+func TestReverseLinkedAgent(t *testing.T) {
+	var node api.IslandoraObject
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"nid": [{"value": 1}],
+		"field_linked_agent": [{"target_id": 7, "rel_type": "relators:aut"}]
+	}`), &node))
+	labeler := func(field string, id int) (string, error) {
+		return map[int]string{7: "person:Doe, Jane"}[id], nil
+	}
+	column := func(records [][]string, name string) string {
+		for i, c := range records[0] {
+			if c == name {
+				return records[1][i]
+			}
+		}
+		return ""
+	}
+
+	// node JSON is labeled as it is flattened
+	records, err := NodeRecords([]*api.IslandoraObject{&node}, NodeOptions{Labeler: labeler})
+	require.NoError(t, err)
+	out, err := ReverseRecords(records, DefaultMapping(), ReverseOptions{Labeler: labeler})
+	require.NoError(t, err)
+	assert.Equal(t, "relators:aut:person:Doe, Jane", column(out, "LinkedAgent"))
+
+	// workbench CSV relations are labeled when reversed
+	out, err = ReverseRecords([][]string{{"node_id", "field_linked_agent"}, {"1", "relators:aut:7"}}, DefaultMapping(), ReverseOptions{Labeler: labeler})
+	require.NoError(t, err)
+	assert.Equal(t, "relators:aut:person:Doe, Jane", column(out, "LinkedAgent"))
+}
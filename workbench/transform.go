@@ -0,0 +1,261 @@
+package workbench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/lehigh-university-libraries/go-islandora/api"
+	islandoraModel "github.com/lehigh-university-libraries/go-islandora/model"
+)
+
+// fieldTypes maps the drupal field name to its model type
+// e.g. field_part_detail => model.PartDetailField
+var fieldTypes = func() map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	t := reflect.TypeOf(api.IslandoraObject{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		types[name] = t.Field(i).Type.Elem()
+	}
+	return types
+}()
+
+// FieldType returns the model type of a drupal field
+// e.g. field_part_detail => model.PartDetailField
+func FieldType(field string) (reflect.Type, bool) {
+	t, ok := fieldTypes[field]
+	return t, ok
+}
+
+// Transform reads a CSV exported from the Google Sheets template
+// and writes an Islandora Workbench CSV
+func Transform(r io.Reader, w io.Writer, mapping *Mapping) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	records, err = TransformRecords(records, mapping)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.WriteAll(records)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TransformRecords converts Google Sheets template rows (including the header)
+// into Islandora Workbench rows.
+// Columns tagged with attributes (e.g. field_note.attr0=box) are folded into JSON
+// for their field, and columns populating the same field are merged.
+func TransformRecords(records [][]string, mapping *Mapping) ([][]string, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV has no header")
+	}
+
+	header := records[0]
+	columns := make([]Column, len(header))
+	fields := []string{}
+	overflow := -1
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == OverflowColumn {
+			overflow = i
+			columns[i] = Column{Name: name, Field: "-"}
+			continue
+		}
+		column, ok := mapping.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns[i] = column
+		if column.Ignored() {
+			continue
+		}
+		field := column.Tag().Field
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	// values ReverseRecords couldn't place in a column
+	overflows := make([]map[string][]string, len(records))
+	if overflow != -1 {
+		for r, record := range records[1:] {
+			if overflow >= len(record) || record[overflow] == "" {
+				continue
+			}
+			err := json.Unmarshal([]byte(record[overflow]), &overflows[r])
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid %s: %w", r+2, OverflowColumn, err)
+			}
+			for _, field := range sortedKeys(overflows[r]) {
+				if !slices.Contains(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+
+	output := [][]string{fields}
+	for r, record := range records[1:] {
+		row, err := transformRow(columns, record)
+		if err != nil {
+			// the header is row 1
+			return nil, fmt.Errorf("row %d: %w", r+2, err)
+		}
+		for field, values := range overflows[r] {
+			if row == nil {
+				row = map[string][]string{}
+			}
+			for _, value := range values {
+				row[field] = appendUnique(row[field], value)
+			}
+		}
+		if len(row) == 0 {
+			continue
+		}
+
+		out := make([]string, len(fields))
+		for i, field := range fields {
+			out[i] = strings.Join(row[field], "|")
+		}
+		output = append(output, out)
+	}
+
+	return output, nil
+}
+
+// transformRow returns the values for each field populated in a row
+// or nil if the row is empty
+func transformRow(columns []Column, record []string) (map[string][]string, error) {
+	row := map[string][]string{}
+	// sub-properties of a field set by separate columns
+	// e.g. field_related_item.title and field_related_item.identifier_type=issn
+	// are combined into a single value
+	objects := map[string]map[string]string{}
+	objectFields := []string{}
+	empty := true
+
+	for i, column := range columns {
+		if i >= len(record) || column.Ignored() {
+			continue
+		}
+		if strings.TrimSpace(record[i]) != "" {
+			empty = false
+		}
+
+		cell, err := column.Apply(record[i])
+		if err != nil {
+			return nil, err
+		}
+		if cell == "" {
+			continue
+		}
+
+		tag := column.Tag()
+		switch tag.Property {
+		case "":
+			for _, value := range strings.Split(cell, "|") {
+				row[tag.Field] = appendUnique(row[tag.Field], value)
+			}
+		case "vid":
+			// workbench's vocabulary_id:term name syntax
+			for _, value := range strings.Split(cell, "|") {
+				row[tag.Field] = appendUnique(row[tag.Field], tag.Value+":"+value)
+			}
+		case "attr0":
+			for _, value := range strings.Split(cell, "|") {
+				v, err := attributeValue(tag, value)
+				if err != nil {
+					return nil, err
+				}
+				row[tag.Field] = appendUnique(row[tag.Field], v)
+			}
+		default:
+			if _, ok := objects[tag.Field]; !ok {
+				objects[tag.Field] = map[string]string{}
+				objectFields = append(objectFields, tag.Field)
+			}
+			if tag.Value == "" {
+				objects[tag.Field][tag.Property] = cell
+				continue
+			}
+			// e.g. identifier_type=issn sets identifier to the cell
+			// and identifier_type to issn
+			key := strings.TrimSuffix(tag.Property, "_type")
+			if key == tag.Property {
+				key = "value"
+			}
+			objects[tag.Field][tag.Property] = tag.Value
+			objects[tag.Field][key] = cell
+		}
+	}
+
+	if empty {
+		return nil, nil
+	}
+
+	for _, field := range objectFields {
+		data, err := json.Marshal(objects[field])
+		if err != nil {
+			return nil, err
+		}
+		row[field] = appendUnique(row[field], string(data))
+	}
+
+	return row, nil
+}
+
+// attributeValue serializes a value from an attr0 tagged column
+// as the JSON workbench expects for the field's type
+func attributeValue(tag Tag, value string) (string, error) {
+	var v any
+	switch fieldTypes[tag.Field] {
+	case reflect.TypeOf(islandoraModel.PartDetailField{}):
+		v = islandoraModel.PartDetail{
+			Type:   tag.Value,
+			Number: value,
+		}
+	default:
+		v = islandoraModel.TypedText{
+			Attr0: tag.Value,
+			Value: value,
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
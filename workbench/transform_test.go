@@ -0,0 +1,61 @@
+package workbench
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformRecords(t *testing.T) {
+	records := [][]string{
+		{"Human Name", "Upload ID", "Title", "Make Public (Y/N)", "Archival Box", "Archival Folder", "Volume Number", "Issue Number", "Subject Geographic (LCNAF)", "Subject Geographic (Local)", "Source Publication Title", "Source Publication L-ISSN", "Keyword"},
+		{"ignored", "1", "Foo", "Y", "2", "3", "10", "4", "Bethlehem (Pa.)", "South Side", "Journal", "1234-5678", "a|b"},
+		{"", "", "", "", "", "", "", "", "", "", "", "", ""},
+		{"", "2", "Bar", "n", "", "", "", "", "", "", "", "", ""},
+	}
+
+	out, err := TransformRecords(records, DefaultMapping())
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+
+	assert.Equal(t, []string{"id", "title", "published", "field_note", "field_part_detail", "field_geographic_subject", "field_related_item", "field_keywords"}, out[0])
+	assert.Equal(t, []string{
+		"1",
+		"Foo",
+		"1",
+		`{"attr0":"box","value":"2"}|{"attr0":"folder","value":"3"}`,
+		`{"type":"volume","number":"10"}|{"type":"issue","number":"4"}`,
+		"geographic_naf:Bethlehem (Pa.)|geographic_local:South Side",
+		`{"identifier":"1234-5678","identifier_type":"issn","title":"Journal"}`,
+		"a|b",
+	}, out[1])
+	assert.Equal(t, []string{"2", "Bar", "0", "", "", "", "", ""}, out[2])
+}
+
+func TestTransformRecordsErrors(t *testing.T) {
+	_, err := TransformRecords([][]string{{"Not A Column"}}, DefaultMapping())
+	assert.ErrorContains(t, err, "unknown column")
+
+	_, err = TransformRecords([][]string{{"Title", "Make Public (Y/N)"}, {"Foo", "maybe"}}, DefaultMapping())
+	assert.ErrorContains(t, err, "row 2")
+}
+
+func TestPostUpload(t *testing.T) {
+	s := NewServer()
+
+	body := "Upload ID,Title,Page Count\n1,Foo,12\n"
+	w := httptest.NewRecorder()
+	s.PostUpload(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id,title,field_extent\n1,Foo,\"{\"\"attr0\"\":\"\"page\"\",\"\"value\"\":\"\"12\"\"}\"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	s.PostUpload(w, httptest.NewRequest(http.MethodPost, "/upload", bytes.NewBufferString("Bogus\nfoo\n")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}